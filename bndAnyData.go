@@ -0,0 +1,188 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+type bndAnyData struct {
+	stmt      *Stmt
+	ocibnd    *C.OCIBind
+	anyData   *C.OCIAnyData
+	null      *C.OCIInd
+	ociNumber C.OCINumber
+	ociString *C.OCIString
+	ociDate   C.OCIDate
+}
+
+func (bnd *bndAnyData) bind(value AnyData, position int, stmt *Stmt) (err error) {
+	bnd.stmt = stmt
+	env := bnd.stmt.ses.srv.env
+	tdo, err := bnd.stmt.ses.typeByName("SYS", "ANYDATA")
+	if err != nil {
+		return err
+	}
+	// the indicator is read by oci during execute; keep it in C memory
+	bnd.null = (*C.OCIInd)(C.malloc(C.sizeof_OCIInd))
+	*bnd.null = C.OCI_IND_NULL
+	if !value.IsNull && value.Value != nil {
+		var typeCode C.OCITypeCode
+		var dataValue unsafe.Pointer
+		var length C.ub4
+		switch v := value.Value.(type) {
+		case int64, int32, int16, int8, int, uint64, uint32, uint16, uint8, uint, float64, float32:
+			var text string
+			switch v := v.(type) {
+			case int64:
+				text = strconv.FormatInt(v, 10)
+			case int32:
+				text = strconv.FormatInt(int64(v), 10)
+			case int16:
+				text = strconv.FormatInt(int64(v), 10)
+			case int8:
+				text = strconv.FormatInt(int64(v), 10)
+			case int:
+				text = strconv.FormatInt(int64(v), 10)
+			case uint64:
+				text = strconv.FormatUint(v, 10)
+			case uint32:
+				text = strconv.FormatUint(uint64(v), 10)
+			case uint16:
+				text = strconv.FormatUint(uint64(v), 10)
+			case uint8:
+				text = strconv.FormatUint(uint64(v), 10)
+			case uint:
+				text = strconv.FormatUint(uint64(v), 10)
+			case float64:
+				text = strconv.FormatFloat(v, 'f', -1, 64)
+			case float32:
+				text = strconv.FormatFloat(float64(v), 'f', -1, 32)
+			}
+			err = env.numberFromText(text, &bnd.ociNumber)
+			if err != nil {
+				return err
+			}
+			typeCode = C.OCI_TYPECODE_NUMBER
+			dataValue = unsafe.Pointer(&bnd.ociNumber)
+			length = C.ub4(C.sizeof_OCINumber)
+		case string:
+			cValue := C.CString(v)
+			defer C.free(unsafe.Pointer(cValue))
+			r := C.OCIStringAssignText(
+				env.ocienv,                           //OCIEnv          *env,
+				env.ocierr,                           //OCIError        *err,
+				(*C.oratext)(unsafe.Pointer(cValue)), //const oratext   *rhs,
+				C.ub4(len(v)),                        //ub4             rhs_len,
+				&bnd.ociString)                       //OCIString       **lhs );
+			if r == C.OCI_ERROR {
+				return env.ociError()
+			}
+			typeCode = C.OCI_TYPECODE_VARCHAR2
+			dataValue = unsafe.Pointer(bnd.ociString)
+			length = C.ub4(len(v))
+		case time.Time:
+			bnd.ociDate.OCIDateYYYY = C.sb2(v.Year())
+			bnd.ociDate.OCIDateMM = C.ub1(v.Month())
+			bnd.ociDate.OCIDateDD = C.ub1(v.Day())
+			bnd.ociDate.OCIDateTime.OCITimeHH = C.ub1(v.Hour())
+			bnd.ociDate.OCIDateTime.OCITimeMI = C.ub1(v.Minute())
+			bnd.ociDate.OCIDateTime.OCITimeSS = C.ub1(v.Second())
+			typeCode = C.OCI_TYPECODE_DATE
+			dataValue = unsafe.Pointer(&bnd.ociDate)
+			length = C.ub4(C.sizeof_OCIDate)
+		default:
+			return errF("Unsupported AnyData value type (%T). Expected a Go integer, float, string or time.Time.", value.Value)
+		}
+		notNull := C.OCIInd(C.OCI_IND_NOTNULL)
+		r := C.OCIAnyDataConvert(
+			bnd.stmt.ses.srv.ocisvcctx, //OCISvcCtx     *svchp,
+			env.ocierr,                 //OCIError      *errhp,
+			typeCode,                   //OCITypeCode   tc,
+			nil,                        //OCIType       *type,
+			C.OCI_DURATION_SESSION,     //OCIDuration   dur,
+			unsafe.Pointer(&notNull),   //void          *ind,
+			dataValue,                  //void          *data_value,
+			length,                     //ub4           len,
+			&bnd.anyData)               //OCIAnyData    **sdata );
+		if r == C.OCI_ERROR {
+			return env.ociError()
+		}
+		*bnd.null = C.OCI_IND_NOTNULL
+	}
+	r := bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd), //OCIBind      **bindpp,
+		position,                   //ub4          position,
+		nil,                        //void         *valuep,
+		0,                          //sb8          value_sz,
+		C.SQLT_NTY,                 //ub2          dty,
+		nil,                        //void         *indp,
+		nil,                        //ub2          *alenp,
+		nil,                        //ub2          *rcodep,
+		0,                          //ub4          maxarr_len,
+		nil,                        //ub4          *curelep,
+		C.OCI_DEFAULT)              //ub4          mode );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	r = C.OCIBindObject(
+		bnd.ocibnd, //OCIBind         *bindp,
+		env.ocierr, //OCIError        *errhp,
+		tdo,        //const OCIType   *type,
+		(*unsafe.Pointer)(unsafe.Pointer(&bnd.anyData)), //void            **pgvpp,
+		nil, //ub4             *pvszsp,
+		(*unsafe.Pointer)(unsafe.Pointer(&bnd.null)), //void            **indpp,
+		nil) //ub4             *indszp );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+func (bnd *bndAnyData) setPtr() error {
+	return nil
+}
+
+func (bnd *bndAnyData) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	stmt := bnd.stmt
+	env := stmt.ses.srv.env
+	if bnd.anyData != nil {
+		C.OCIAnyDataDestroy(
+			stmt.ses.srv.ocisvcctx, //OCISvcCtx     *svchp,
+			env.ocierr,             //OCIError      *errhp,
+			bnd.anyData)            //OCIAnyData    *sdata );
+	}
+	if bnd.ociString != nil {
+		C.OCIStringResize(
+			env.ocienv,     //OCIEnv       *env,
+			env.ocierr,     //OCIError     *err,
+			0,              //ub4          new_size,
+			&bnd.ociString) //OCIString    **str );
+	}
+	if bnd.null != nil {
+		C.free(unsafe.Pointer(bnd.null))
+	}
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	bnd.anyData = nil
+	bnd.null = nil
+	bnd.ociString = nil
+	stmt.putBnd(bndIdxAnyData, bnd)
+	return nil
+}
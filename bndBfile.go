@@ -64,11 +64,9 @@ func (bnd *bndBfile) bind(value Bfile, position int, stmt *Stmt) error {
 	if r == C.OCI_ERROR {
 		return bnd.stmt.ses.srv.env.ociError()
 	}
-	r = C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                                //OCIStmt      *stmtp,
+	r = bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),                      //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,                     //OCIError     *errhp,
-		C.ub4(position),                                 //ub4          position,
+		position,                                        //ub4          position,
 		unsafe.Pointer(&bnd.ociLobLocator),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociLobLocator)), //sb8          value_sz,
 		C.SQLT_FILE,   //ub2          dty,
@@ -16,22 +16,25 @@ type bndBin struct {
 	ocibnd *C.OCIBind
 }
 
-func (bnd *bndBin) bind(value []byte, position int, stmt *Stmt) (err error) {
+// bind binds value as a RAW, or as a LONG RAW when longer than rawThreshold.
+func (bnd *bndBin) bind(value []byte, position int, rawThreshold int, stmt *Stmt) (err error) {
 	bnd.stmt = stmt
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,            //OCIStmt      *stmtp,
-		(**C.OCIBind)(&bnd.ocibnd),  //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
-		C.ub4(position),             //ub4          position,
-		unsafe.Pointer(&value[0]),   //void         *valuep,
-		C.LENGTH_TYPE(len(value)),   //sb8          value_sz,
-		C.SQLT_LBI,                  //ub2          dty,
-		nil,                         //void         *indp,
-		nil,                         //ub2          *alenp,
-		nil,                         //ub2          *rcodep,
-		0,                           //ub4          maxarr_len,
-		nil,                         //ub4          *curelep,
-		C.OCI_DEFAULT)               //ub4          mode );
+	dty := C.ub2(C.SQLT_BIN)
+	if len(value) > rawThreshold {
+		dty = C.SQLT_LBI
+	}
+	r := bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd), //OCIBind      **bindpp,
+		position,                   //ub4          position,
+		unsafe.Pointer(&value[0]),  //void         *valuep,
+		C.LENGTH_TYPE(len(value)),  //sb8          value_sz,
+		dty,                        //ub2          dty,
+		nil,                        //void         *indp,
+		nil,                        //ub2          *alenp,
+		nil,                        //ub2          *rcodep,
+		0,                          //ub4          maxarr_len,
+		nil,                        //ub4          *curelep,
+		C.OCI_DEFAULT)              //ub4          mode );
 	if r == C.OCI_ERROR {
 		return bnd.stmt.ses.srv.env.ociError()
 	}
@@ -13,6 +13,10 @@ import (
 	"unsafe"
 )
 
+// maxRawLen is the maximum length in bytes of a RAW column without extended
+// data types, and the default StmtCfg.RawThreshold.
+const maxRawLen = 2000
+
 type bndBinSlice struct {
 	stmt   *Stmt
 	ocibnd *C.OCIBind
@@ -36,15 +40,25 @@ func (bnd *bndBinSlice) bind(values [][]byte, nullInds []C.sb2, position int, lo
 	bnd.stmt = stmt
 	if nullInds == nil {
 		nullInds = make([]C.sb2, len(values))
+		for i := range values {
+			if values[i] == nil { // a nil []byte is NULL
+				nullInds[i] = C.sb2(-1)
+			}
+		}
 	}
 	alenp := make([]C.ACTUAL_LENGTH_TYPE, len(values))
 	rcodep := make([]C.ub2, len(values))
-	var maxLen int
+	maxLen := 1 // a buffer is required even when all values are empty
 	for _, b := range values {
 		if len(b) > maxLen {
 			maxLen = len(b)
 		}
 	}
+	// bind as an array of RAW, reserving LONG RAW for longer values
+	dty := C.ub2(C.SQLT_BIN)
+	if maxLen > bnd.stmt.cfg.rawThreshold {
+		dty = C.SQLT_LBI
+	}
 	n := maxLen * len(values)
 	if cap(bnd.buf) < n {
 		bnd.buf = make([]byte, n)
@@ -59,14 +73,12 @@ func (bnd *bndBinSlice) bind(values [][]byte, nullInds []C.sb2, position int, lo
 		copy(bnd.buf[i*maxLen:], b)
 		alenp[i] = C.ACTUAL_LENGTH_TYPE(len(b))
 	}
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,             //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),   //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,  //OCIError     *errhp,
-		C.ub4(position),              //ub4          position,
+		position,                     //ub4          position,
 		unsafe.Pointer(&bnd.buf[0]),  //void         *valuep,
 		C.LENGTH_TYPE(maxLen),        //sb8          value_sz,
-		C.SQLT_LBI,                   //ub2          dty,
+		dty,                          //ub2          dty,
 		unsafe.Pointer(&nullInds[0]), //void         *indp,
 		&alenp[0],                    //ub4          *alenp,
 		&rcodep[0],                   //ub2          *rcodep,
@@ -16,14 +16,18 @@ import (
 )
 
 type bndBool struct {
-	stmt    *Stmt
-	ocibnd  *C.OCIBind
-	cString *C.char
+	stmt      *Stmt
+	ocibnd    *C.OCIBind
+	cString   *C.char
+	ociNumber C.OCINumber
 }
 
 func (bnd *bndBool) bind(value bool, position int, c StmtCfg, stmt *Stmt) (err error) {
 	//Log.Infof("%s.bind(%t, %d)", bnd, value, position)
 	bnd.stmt = stmt
+	if c.IsBoolNumeric {
+		return bnd.bindNumeric(value, position)
+	}
 	var str string
 	if value {
 		str, err = strconv.Unquote(strconv.QuoteRune(c.TrueRune))
@@ -34,11 +38,9 @@ func (bnd *bndBool) bind(value bool, position int, c StmtCfg, stmt *Stmt) (err e
 		return err
 	}
 	bnd.cString = C.CString(str)
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,            //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),  //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
-		C.ub4(position),             //ub4          position,
+		position,                    //ub4          position,
 		unsafe.Pointer(bnd.cString), //void         *valuep,
 		C.LENGTH_TYPE(1),            //sb8          value_sz,
 		C.SQLT_AFC,                  //ub2          dty,
@@ -54,6 +56,38 @@ func (bnd *bndBool) bind(value bool, position int, c StmtCfg, stmt *Stmt) (err e
 	return nil
 }
 
+func (bnd *bndBool) bindNumeric(value bool, position int) error {
+	var num int8
+	if value {
+		num = 1
+	}
+	r := C.OCINumberFromInt(
+		bnd.stmt.ses.srv.env.ocierr, //OCIError            *err,
+		unsafe.Pointer(&num),        //const void          *inum,
+		1,                   //uword               inum_length,
+		C.OCI_NUMBER_SIGNED, //uword               inum_s_flag,
+		&bnd.ociNumber)      //OCINumber           *number );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	r = bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd),        //OCIBind      **bindpp,
+		position,                          //ub4          position,
+		unsafe.Pointer(&bnd.ociNumber),    //void         *valuep,
+		C.LENGTH_TYPE(C.sizeof_OCINumber), //sb8          value_sz,
+		C.SQLT_VNU,                        //ub2          dty,
+		nil,                               //void         *indp,
+		nil,                               //ub2          *alenp,
+		nil,                               //ub2          *rcodep,
+		0,                                 //ub4          maxarr_len,
+		nil,                               //ub4          *curelep,
+		C.OCI_DEFAULT)                     //ub4          mode );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
 func (bnd *bndBool) setPtr() error {
 	return nil
 }
@@ -65,7 +99,9 @@ func (bnd *bndBool) close() (err error) {
 		}
 	}()
 
-	C.free(unsafe.Pointer(bnd.cString))
+	if bnd.cString != nil {
+		C.free(unsafe.Pointer(bnd.cString))
+	}
 	stmt := bnd.stmt
 	bnd.stmt = nil
 	bnd.ocibnd = nil
@@ -31,11 +31,9 @@ func (bnd *bndBoolPtr) bind(value *bool, position int, trueRune rune, stmt *Stmt
 	if cap(bnd.buf) < 2 {
 		bnd.buf = make([]byte, 2)
 	}
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,            //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),  //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
-		C.ub4(position),             //ub4          position,
+		position,                    //ub4          position,
 		unsafe.Pointer(&bnd.buf[0]), //void         *valuep,
 		C.LENGTH_TYPE(len(bnd.buf)), //sb8          value_sz,
 		C.SQLT_CHR,                  //ub2          dty,
@@ -0,0 +1,191 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+type bndColl struct {
+	stmt     *Stmt
+	ocibnd   *C.OCIBind
+	instance unsafe.Pointer
+	null     *C.OCIInd
+}
+
+// bind binds a Coll to a VARRAY or nested table parameter, appending each
+// element of the Coll's slice to a new collection instance.
+func (bnd *bndColl) bind(value Coll, position int, stmt *Stmt) (err error) {
+	bnd.stmt = stmt
+	env := stmt.ses.srv.env
+	if value.TypeName == "" {
+		return er("Coll.TypeName may not be empty.")
+	}
+	typ, err := stmt.ses.objType(strings.ToUpper(value.TypeName))
+	if err != nil {
+		return err
+	}
+	if typ.typeCode != C.OCI_TYPECODE_NAMEDCOLLECTION {
+		return errF("Type %v isn't a VARRAY or nested table type (typecode %v).", typ.name, typ.typeCode)
+	}
+	r := C.OCIObjectNew(
+		env.ocienv,             //OCIEnv          *env,
+		env.ocierr,             //OCIError        *err,
+		stmt.ses.srv.ocisvcctx, //const OCISvcCtx *svc,
+		typ.collTypeCode,       //OCITypeCode     typecode,
+		typ.tdo,                //OCIType         *tdo,
+		nil,                    //void            *table,
+		C.OCI_DURATION_SESSION, //OCIDuration     duration,
+		C.TRUE,                 //boolean         value,
+		&bnd.instance)          //void            **instance );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	// the indicator is read by oci during execute; keep it in C memory
+	bnd.null = (*C.OCIInd)(C.malloc(C.sizeof_OCIInd))
+	*bnd.null = C.OCI_IND_NULL
+	if !value.IsNull && value.Value != nil {
+		rv := reflect.ValueOf(value.Value)
+		if rv.Kind() != reflect.Slice {
+			return errF("Unsupported Coll value type (%T). Expected a slice.", value.Value)
+		}
+		for n := 0; n < rv.Len(); n++ {
+			err = bnd.append(typ, rv.Index(n))
+			if err != nil {
+				return err
+			}
+		}
+		*bnd.null = C.OCI_IND_NOTNULL
+	}
+	r = bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd), //OCIBind      **bindpp,
+		position,                   //ub4          position,
+		nil,                        //void         *valuep,
+		0,                          //sb8          value_sz,
+		C.SQLT_NTY,                 //ub2          dty,
+		nil,                        //void         *indp,
+		nil,                        //ub2          *alenp,
+		nil,                        //ub2          *rcodep,
+		0,                          //ub4          maxarr_len,
+		nil,                        //ub4          *curelep,
+		C.OCI_DEFAULT)              //ub4          mode );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	r = C.OCIBindObject(
+		bnd.ocibnd,    //OCIBind         *bindp,
+		env.ocierr,    //OCIError        *errhp,
+		typ.tdo,       //const OCIType   *type,
+		&bnd.instance, //void            **pgvpp,
+		nil,           //ub4             *pvszsp,
+		(*unsafe.Pointer)(unsafe.Pointer(&bnd.null)), //void            **indpp,
+		nil) //ub4             *indszp );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+// append appends an element to the collection instance. The collection copies
+// the element.
+func (bnd *bndColl) append(typ *objType, rv reflect.Value) error {
+	env := bnd.stmt.ses.srv.env
+	var elem unsafe.Pointer
+	switch typ.elem.typeCode {
+	case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT,
+		C.OCI_TYPECODE_DECIMAL, C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE:
+		var text string
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			text = strconv.FormatInt(rv.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			text = strconv.FormatUint(rv.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			text = strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits())
+		case reflect.String:
+			text = rv.String()
+		default:
+			return errF("Unable to append %v to a collection of NUMBER (%v).", rv.Type(), typ.name)
+		}
+		number := (*C.OCINumber)(C.malloc(C.sizeof_OCINumber))
+		defer C.free(unsafe.Pointer(number))
+		err := env.numberFromText(text, number)
+		if err != nil {
+			return err
+		}
+		elem = unsafe.Pointer(number)
+	case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_CHAR, C.OCI_TYPECODE_VARCHAR:
+		if rv.Kind() != reflect.String {
+			return errF("Unable to append %v to a collection of strings (%v).", rv.Type(), typ.name)
+		}
+		value := rv.String()
+		cValue := C.CString(value)
+		defer C.free(unsafe.Pointer(cValue))
+		var ociString *C.OCIString
+		r := C.OCIStringAssignText(
+			env.ocienv,                           //OCIEnv          *env,
+			env.ocierr,                           //OCIError        *err,
+			(*C.oratext)(unsafe.Pointer(cValue)), //const oratext   *rhs,
+			C.ub4(len(value)),                    //ub4             rhs_len,
+			&ociString)                           //OCIString       **lhs );
+		if r == C.OCI_ERROR {
+			return env.ociError()
+		}
+		defer C.OCIStringResize(env.ocienv, env.ocierr, 0, &ociString)
+		elem = unsafe.Pointer(ociString)
+	default:
+		return errF("Unsupported element type of collection type %v (typecode %v). Only NUMBER, VARCHAR2, CHAR and VARCHAR elements are supported.", typ.name, typ.elem.typeCode)
+	}
+	r := C.OCICollAppend(
+		env.ocienv,                 //OCIEnv          *env,
+		env.ocierr,                 //OCIError        *err,
+		elem,                       //const void      *elem,
+		nil,                        //const void      *elemind,
+		(*C.OCIColl)(bnd.instance)) //OCIColl         *coll );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+func (bnd *bndColl) setPtr() error {
+	return nil
+}
+
+func (bnd *bndColl) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	stmt := bnd.stmt
+	env := stmt.ses.srv.env
+	if bnd.instance != nil {
+		C.OCIObjectFree(
+			env.ocienv,             //OCIEnv      *env,
+			env.ocierr,             //OCIError    *err,
+			bnd.instance,           //void        *instance,
+			C.OCI_OBJECTFREE_FORCE) //ub2         flags );
+	}
+	if bnd.null != nil {
+		C.free(unsafe.Pointer(bnd.null))
+	}
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	bnd.instance = nil
+	bnd.null = nil
+	stmt.putBnd(bndIdxColl, bnd)
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+type bndDecimal struct {
+	stmt      *Stmt
+	ocibnd    *C.OCIBind
+	ociNumber C.OCINumber
+}
+
+func (bnd *bndDecimal) bind(value string, position int, stmt *Stmt) error {
+	bnd.stmt = stmt
+	err := bnd.stmt.ses.srv.env.numberFromText(value, &bnd.ociNumber)
+	if err != nil {
+		return err
+	}
+	r := bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd),        //OCIBind      **bindpp,
+		position,                          //ub4          position,
+		unsafe.Pointer(&bnd.ociNumber),    //void         *valuep,
+		C.LENGTH_TYPE(C.sizeof_OCINumber), //sb8          value_sz,
+		C.SQLT_VNU,                        //ub2          dty,
+		nil,                               //void         *indp,
+		nil,           //ub2          *alenp,
+		nil,           //ub2          *rcodep,
+		0,             //ub4          maxarr_len,
+		nil,           //ub4          *curelep,
+		C.OCI_DEFAULT) //ub4          mode );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+func (bnd *bndDecimal) setPtr() error {
+	return nil
+}
+
+func (bnd *bndDecimal) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	stmt := bnd.stmt
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	stmt.putBnd(bndIdxDecimal, bnd)
+	return nil
+}
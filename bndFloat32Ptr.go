@@ -24,11 +24,9 @@ type bndFloat32Ptr struct {
 func (bnd *bndFloat32Ptr) bind(value *float32, position int, stmt *Stmt) error {
 	bnd.stmt = stmt
 	bnd.value = value
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                  //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),        //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,       //OCIError     *errhp,
-		C.ub4(position),                   //ub4          position,
+		position,                          //ub4          position,
 		unsafe.Pointer(&bnd.ociNumber),    //void         *valuep,
 		C.LENGTH_TYPE(C.sizeof_OCINumber), //sb8          value_sz,
 		C.SQLT_VNU,                        //ub2          dty,
@@ -51,11 +51,9 @@ func (bnd *bndFloat32Slice) bind(values []float32, nullInds []C.sb2, position in
 			return bnd.stmt.ses.srv.env.ociError()
 		}
 	}
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                   //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),         //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,        //OCIError     *errhp,
-		C.ub4(position),                    //ub4          position,
+		position,                           //ub4          position,
 		unsafe.Pointer(&bnd.ociNumbers[0]), //void         *valuep,
 		C.LENGTH_TYPE(C.sizeof_OCINumber),  //sb8          value_sz,
 		C.SQLT_VNU,                         //ub2          dty,
@@ -19,16 +19,40 @@ type bndInt64Ptr struct {
 	ociNumber C.OCINumber
 	isNull    C.sb2
 	value     *int64
+	nullp     *bool
+}
+
+// bindOut binds value as an IN OUT parameter, sending the current value or a
+// NULL when isNull is true.
+func (bnd *bndInt64Ptr) bindOut(value *int64, isNull *bool, position int, stmt *Stmt) error {
+	err := bnd.bind(value, position, stmt)
+	if err != nil {
+		return err
+	}
+	bnd.nullp = isNull
+	bnd.isNull = C.sb2(0)
+	if isNull != nil && *isNull {
+		bnd.isNull = C.sb2(-1)
+		return nil
+	}
+	r := C.OCINumberFromInt(
+		bnd.stmt.ses.srv.env.ocierr, //OCIError            *err,
+		unsafe.Pointer(value),       //const void          *inum,
+		8,                           //uword               inum_length,
+		C.OCI_NUMBER_SIGNED,         //uword               inum_s_flag,
+		&bnd.ociNumber)              //OCINumber           *number );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
 }
 
 func (bnd *bndInt64Ptr) bind(value *int64, position int, stmt *Stmt) error {
 	bnd.stmt = stmt
 	bnd.value = value
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                  //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),        //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,       //OCIError     *errhp,
-		C.ub4(position),                   //ub4          position,
+		position,                          //ub4          position,
 		unsafe.Pointer(&bnd.ociNumber),    //void         *valuep,
 		C.LENGTH_TYPE(C.sizeof_OCINumber), //sb8          value_sz,
 		C.SQLT_VNU,                        //ub2          dty,
@@ -45,6 +69,9 @@ func (bnd *bndInt64Ptr) bind(value *int64, position int, stmt *Stmt) error {
 }
 
 func (bnd *bndInt64Ptr) setPtr() error {
+	if bnd.nullp != nil {
+		*bnd.nullp = bnd.isNull < C.sb2(0)
+	}
 	if bnd.isNull > C.sb2(-1) {
 		r := C.OCINumberToInt(
 			bnd.stmt.ses.srv.env.ocierr, //OCIError              *err,
@@ -70,6 +97,7 @@ func (bnd *bndInt64Ptr) close() (err error) {
 	bnd.stmt = nil
 	bnd.ocibnd = nil
 	bnd.value = nil
+	bnd.nullp = nil
 	stmt.putBnd(bndIdxInt64Ptr, bnd)
 	return nil
 }
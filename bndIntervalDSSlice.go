@@ -56,11 +56,9 @@ func (bnd *bndIntervalDSSlice) bind(values []IntervalDS, position int, stmt *Stm
 		}
 		alenp[n] = C.ACTUAL_LENGTH_TYPE(unsafe.Sizeof(bnd.ociIntervals[n]))
 	}
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                                  //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),                        //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,                       //OCIError     *errhp,
-		C.ub4(position),                                   //ub4          position,
+		position,                                          //ub4          position,
 		unsafe.Pointer(&bnd.ociIntervals[0]),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociIntervals[0])), //sb8          value_sz,
 		C.SQLT_INTERVAL_DS,                                //ub2          dty,
@@ -41,11 +41,9 @@ func (bnd *bndIntervalYM) bind(value IntervalYM, position int, stmt *Stmt) error
 	if r == C.OCI_ERROR {
 		return bnd.stmt.ses.srv.env.ociError()
 	}
-	r = C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                              //OCIStmt      *stmtp,
+	r = bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),                    //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,                   //OCIError     *errhp,
-		C.ub4(position),                               //ub4          position,
+		position,                                      //ub4          position,
 		unsafe.Pointer(&bnd.ociInterval),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociInterval)), //sb8          value_sz,
 		C.SQLT_INTERVAL_YM,                            //ub2          dty,
@@ -11,6 +11,7 @@ package ora
 import "C"
 import (
 	"io"
+	"strings"
 	"unsafe"
 )
 
@@ -18,6 +19,7 @@ type bndLob struct {
 	stmt          *Stmt
 	ocibnd        *C.OCIBind
 	ociLobLocator *C.OCILobLocator
+	sqlt          C.ub2
 }
 
 // bindReader binds an io.Reader: reads from rdr, and writes to a temprary LOB,
@@ -34,24 +36,35 @@ type bndLob struct {
 // None of the chunks can be empty, so we have to pre-read the next chunk,
 // before sending the actual, to know whether this is the last or not.
 func (bnd *bndLob) bindReader(rdr io.Reader, position int, lobBufferSize int, stmt *Stmt) (err error) {
+	return bnd.bindTempLob(rdr, position, lobBufferSize, C.OCI_TEMP_BLOB, C.SQLT_BLOB, stmt)
+}
+
+// bindClob writes a string exceeding StmtCfg.ClobThreshold to a temporary
+// CLOB, then binds that.
+func (bnd *bndLob) bindClob(value string, position int, lobBufferSize int, stmt *Stmt) (err error) {
+	return bnd.bindTempLob(strings.NewReader(value), position, lobBufferSize, C.OCI_TEMP_CLOB, C.SQLT_CLOB, stmt)
+}
+
+func (bnd *bndLob) bindTempLob(rdr io.Reader, position int, lobBufferSize int, lobType C.ub1, sqlt C.ub2, stmt *Stmt) (err error) {
 	bnd.stmt = stmt
+	bnd.sqlt = sqlt
 	if lobBufferSize <= 0 {
 		lobBufferSize = lobChunkSize
 	}
 
-	finish, err := bnd.allocTempLob()
+	bnd.ociLobLocator, _, err = allocTempLob(bnd.stmt, lobType)
 	if err != nil {
 		return err
 	}
 
 	if err = writeLob(bnd.ociLobLocator, bnd.stmt, rdr, lobBufferSize); err != nil {
 		bnd.stmt.ses.srv.Break()
-		finish()
+		bnd.freeLob()
 		return err
 	}
 
 	if err = bnd.bindByPos(position); err != nil {
-		finish()
+		bnd.freeLob()
 		return err
 	}
 	return nil
@@ -61,10 +74,22 @@ func (bnd *bndLob) setPtr() error {
 	return nil
 }
 
+// freeLob frees the temporary LOB. Stmt.exe frees it once the statement is
+// executed, whether or not the execution succeeds.
 func (bnd *bndLob) freeLob() {
-	defer func() {
-		recover()
-	}()
+	if bnd.ociLobLocator == nil {
+		return
+	}
+	// free temporary lob
+	C.OCILobFreeTemporary(
+		bnd.stmt.ses.srv.ocisvcctx,  //OCISvcCtx          *svchp,
+		bnd.stmt.ses.srv.env.ocierr, //OCIError           *errhp,
+		bnd.ociLobLocator)           //OCILobLocator      *locp,
+	// free lob locator handle
+	C.OCIDescriptorFree(
+		unsafe.Pointer(bnd.ociLobLocator), //void     *descp,
+		C.OCI_DTYPE_LOB)                   //ub4      type );
+	bnd.ociLobLocator = nil
 }
 
 func (bnd *bndLob) close() (err error) {
@@ -75,37 +100,22 @@ func (bnd *bndLob) close() (err error) {
 	}()
 
 	// no need to clear bnd.buf
-	// free temporary lob
-	C.OCILobFreeTemporary(
-		bnd.stmt.ses.srv.ocisvcctx,  //OCISvcCtx          *svchp,
-		bnd.stmt.ses.srv.env.ocierr, //OCIError           *errhp,
-		bnd.ociLobLocator)           //OCILobLocator      *locp,
-	// free lob locator handle
-	C.OCIDescriptorFree(
-		unsafe.Pointer(bnd.ociLobLocator), //void     *descp,
-		C.OCI_DTYPE_LOB)                   //ub4      type );
+	bnd.freeLob()
 	stmt := bnd.stmt
 	bnd.stmt = nil
 	bnd.ocibnd = nil
-	bnd.ociLobLocator = nil
+	bnd.sqlt = 0
 	stmt.putBnd(bndIdxLob, bnd)
 	return nil
 }
 
-func (bnd *bndLob) allocTempLob() (finish func(), err error) {
-	bnd.ociLobLocator, finish, err = allocTempLob(bnd.stmt)
-	return
-}
-
 func (bnd *bndLob) bindByPos(position int) error {
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                                //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),                      //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,                     //OCIError     *errhp,
-		C.ub4(position),                                 //ub4          position,
+		position,                                        //ub4          position,
 		unsafe.Pointer(&bnd.ociLobLocator),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociLobLocator)), //sb8          value_sz,
-		C.SQLT_BLOB,   //ub2          dty,
+		bnd.sqlt,      //ub2          dty,
 		nil,           //void         *indp,
 		nil,           //ub2          *alenp,
 		nil,           //ub2          *rcodep,
@@ -137,14 +147,13 @@ func writeLob(ociLobLocator *C.OCILobLocator, stmt *Stmt, r io.Reader, lobBuffer
 	var n int
 	var byte_amtp, off C.oraub8
 	var actPiece, nextPiece C.ub1 = C.OCI_FIRST_PIECE, C.OCI_NEXT_PIECE
-	// OCILobWrite2 doesn't support writing zero bytes
-	// nor is writing 1 byte and erasing the one byte supported
-	// therefore, throw an error
+	// OCILobWrite2 doesn't support writing zero bytes;
+	// an empty reader leaves the LOB empty, which is distinct from a NULL LOB
 	var err error
 	if n, err = io.ReadFull(r, actBuf); err != nil {
 		switch err {
 		case io.EOF: // no bytes read
-			return errNew("writing a zero-length BLOB is unsupported")
+			return nil
 		case io.ErrUnexpectedEOF:
 			actPiece = C.OCI_ONE_PIECE
 		default:
@@ -209,7 +218,7 @@ func writeLob(ociLobLocator *C.OCILobLocator, stmt *Stmt, r io.Reader, lobBuffer
 	return nil
 }
 
-func allocTempLob(stmt *Stmt) (
+func allocTempLob(stmt *Stmt, lobType C.ub1) (
 	ociLobLocator *C.OCILobLocator,
 	finish func(),
 	err error,
@@ -234,7 +243,7 @@ func allocTempLob(stmt *Stmt) (
 		ociLobLocator,           //OCILobLocator      *locp,
 		C.OCI_DEFAULT,           //ub2                csid,
 		C.SQLCS_IMPLICIT,        //ub1                csfrm,
-		C.OCI_TEMP_BLOB,         //ub1                lobtype,
+		lobType,                 //ub1                lobtype,
 		C.TRUE,                  //boolean            cache,
 		C.OCI_DURATION_SESSION)  //OCIDuration        duration);
 	if r == C.OCI_ERROR {
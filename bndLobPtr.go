@@ -96,16 +96,14 @@ func (bnd *bndLobPtr) close() (err error) {
 }
 
 func (bnd *bndLobPtr) allocTempLob() (finish func(), err error) {
-	bnd.ociLobLocator, finish, err = allocTempLob(bnd.stmt)
+	bnd.ociLobLocator, finish, err = allocTempLob(bnd.stmt, C.OCI_TEMP_BLOB)
 	return
 }
 
 func (bnd *bndLobPtr) bindByPos(position int) error {
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                                //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),                      //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,                     //OCIError     *errhp,
-		C.ub4(position),                                 //ub4          position,
+		position,                                        //ub4          position,
 		unsafe.Pointer(&bnd.ociLobLocator),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociLobLocator)), //sb8          value_sz,
 		C.SQLT_BLOB,   //ub2          dty,
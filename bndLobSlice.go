@@ -67,7 +67,7 @@ func (bnd *bndLobSlice) bindReaders(
 	}()
 
 	for i, r := range values {
-		bnd.ociLobLocators[i], finishers[i], err = allocTempLob(bnd.stmt)
+		bnd.ociLobLocators[i], finishers[i], err = allocTempLob(bnd.stmt, C.OCI_TEMP_BLOB)
 		if err != nil {
 			return err
 		}
@@ -82,11 +82,9 @@ func (bnd *bndLobSlice) bindReaders(
 		}
 	}
 
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                                    //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),                          //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,                         //OCIError     *errhp,
-		C.ub4(position),                                     //ub4          position,
+		position,                                            //ub4          position,
 		unsafe.Pointer(&bnd.ociLobLocators[0]),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociLobLocators[0])), //sb8          value_sz,
 		C.SQLT_BLOB,                  //ub2          dty,
@@ -21,11 +21,9 @@ type bndNil struct {
 func (bnd *bndNil) bind(position int, sqlt C.ub2, stmt *Stmt) error {
 	bnd.stmt = stmt
 	indp := C.sb2(-1)
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,            //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),  //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
-		C.ub4(position),             //ub4          position,
+		position,                    //ub4          position,
 		nil,                         //void         *valuep,
 		0,                           //sb8          value_sz,
 		sqlt,                        //C.SQLT_CHR,                                          //ub2          dty,
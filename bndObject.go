@@ -0,0 +1,132 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"reflect"
+	"unsafe"
+)
+
+type bndObject struct {
+	stmt       *Stmt
+	ocibnd     *C.OCIBind
+	instance   unsafe.Pointer
+	null       unsafe.Pointer // null structure of the instance
+	ociStrings []*C.OCIString
+}
+
+// bind binds a struct, or pointer to a struct, of a type mapped by AddObj to
+// the object type of the specified name. A nil pointer is bound as a NULL
+// object.
+func (bnd *bndObject) bind(value interface{}, typeName string, position int, stmt *Stmt) (err error) {
+	bnd.stmt = stmt
+	env := stmt.ses.srv.env
+	typ, err := stmt.ses.objType(typeName)
+	if err != nil {
+		return err
+	}
+	if typ.typeCode != C.OCI_TYPECODE_OBJECT {
+		return errF("Type %v isn't an object type (typecode %v).", typ.name, typ.typeCode)
+	}
+	r := C.OCIObjectNew(
+		env.ocienv,             //OCIEnv          *env,
+		env.ocierr,             //OCIError        *err,
+		stmt.ses.srv.ocisvcctx, //const OCISvcCtx *svc,
+		C.OCI_TYPECODE_OBJECT,  //OCITypeCode     typecode,
+		typ.tdo,                //OCIType         *tdo,
+		nil,                    //void            *table,
+		C.OCI_DURATION_SESSION, //OCIDuration     duration,
+		C.TRUE,                 //boolean         value,
+		&bnd.instance)          //void            **instance );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	r = C.OCIObjectGetInd(
+		env.ocienv,   //OCIEnv      *env,
+		env.ocierr,   //OCIError    *err,
+		bnd.instance, //void        *instance,
+		&bnd.null)    //void        **null_struct );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		*(*C.OCIInd)(bnd.null) = C.OCI_IND_NULL
+	} else {
+		*(*C.OCIInd)(bnd.null) = C.OCI_IND_NOTNULL
+		err = typ.setStruct(stmt.ses, bnd.instance, bnd.null, reflect.Indirect(rv), &bnd.ociStrings)
+		if err != nil {
+			return err
+		}
+	}
+	r = bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd), //OCIBind      **bindpp,
+		position,                   //ub4          position,
+		nil,                        //void         *valuep,
+		0,                          //sb8          value_sz,
+		C.SQLT_NTY,                 //ub2          dty,
+		nil,                        //void         *indp,
+		nil,                        //ub2          *alenp,
+		nil,                        //ub2          *rcodep,
+		0,                          //ub4          maxarr_len,
+		nil,                        //ub4          *curelep,
+		C.OCI_DEFAULT)              //ub4          mode );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	r = C.OCIBindObject(
+		bnd.ocibnd,    //OCIBind         *bindp,
+		env.ocierr,    //OCIError        *errhp,
+		typ.tdo,       //const OCIType   *type,
+		&bnd.instance, //void            **pgvpp,
+		nil,           //ub4             *pvszsp,
+		&bnd.null,     //void            **indpp,
+		nil)           //ub4             *indszp );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+func (bnd *bndObject) setPtr() error {
+	return nil
+}
+
+func (bnd *bndObject) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	stmt := bnd.stmt
+	env := stmt.ses.srv.env
+	if bnd.instance != nil {
+		C.OCIObjectFree(
+			env.ocienv,             //OCIEnv      *env,
+			env.ocierr,             //OCIError    *err,
+			bnd.instance,           //void        *instance,
+			C.OCI_OBJECTFREE_FORCE) //ub2         flags );
+	}
+	for n := range bnd.ociStrings {
+		C.OCIStringResize(
+			env.ocienv,         //OCIEnv       *env,
+			env.ocierr,         //OCIError     *err,
+			0,                  //ub4          new_size,
+			&bnd.ociStrings[n]) //OCIString    **str );
+	}
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	bnd.instance = nil
+	bnd.null = nil
+	bnd.ociStrings = bnd.ociStrings[:0]
+	stmt.putBnd(bndIdxObject, bnd)
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// bndPlsArr binds a PlsArr of int64 or string elements as a PL/SQL
+// associative array.
+type bndPlsArr struct {
+	stmt       *Stmt
+	ocibnd     *C.OCIBind
+	int64s     *[]int64
+	strs       *[]string
+	ociNumbers []C.OCINumber
+	buf        []byte
+	width      int
+	nullInds   []C.sb2
+	alens      []C.ACTUAL_LENGTH_TYPE
+	curlen     C.ub4
+}
+
+func (bnd *bndPlsArr) bind(value PlsArr, position int, stringPtrBufferSize int, stmt *Stmt) error {
+	bnd.stmt = stmt
+	var int64s []int64
+	var strs []string
+	isNumber := false
+	capacity := value.Cap
+	switch v := value.Value.(type) {
+	case []int64:
+		int64s, isNumber = v, true
+	case *[]int64:
+		int64s, isNumber = *v, true
+		bnd.int64s = v
+		if capacity == 0 {
+			capacity = cap(*v)
+		}
+	case []string:
+		strs = v
+	case *[]string:
+		strs = *v
+		bnd.strs = v
+		if capacity == 0 {
+			capacity = cap(*v)
+		}
+	default:
+		return errF("Unsupported PlsArr value type (%T). Expected []int64, *[]int64, []string or *[]string.", value.Value)
+	}
+	length := len(strs)
+	if isNumber {
+		length = len(int64s)
+	}
+	if capacity < length {
+		capacity = length
+	}
+	// a zero maxarr_len isn't an associative array bind
+	if capacity == 0 {
+		capacity = 1
+	}
+	bnd.curlen = C.ub4(length)
+	bnd.nullInds = make([]C.sb2, capacity)
+	bnd.alens = make([]C.ACTUAL_LENGTH_TYPE, capacity)
+	var valuep unsafe.Pointer
+	var valueSz C.LENGTH_TYPE
+	var dty C.ub2
+	if isNumber {
+		bnd.ociNumbers = make([]C.OCINumber, capacity)
+		for n := range int64s {
+			bnd.alens[n] = C.ACTUAL_LENGTH_TYPE(C.sizeof_OCINumber)
+			r := C.OCINumberFromInt(
+				bnd.stmt.ses.srv.env.ocierr, //OCIError            *err,
+				unsafe.Pointer(&int64s[n]),  //const void          *inum,
+				8,                           //uword               inum_length,
+				C.OCI_NUMBER_SIGNED,         //uword               inum_s_flag,
+				&bnd.ociNumbers[n])          //OCINumber           *number );
+			if r == C.OCI_ERROR {
+				return bnd.stmt.ses.srv.env.ociError()
+			}
+		}
+		valuep = unsafe.Pointer(&bnd.ociNumbers[0])
+		valueSz = C.LENGTH_TYPE(C.sizeof_OCINumber)
+		dty = C.SQLT_VNU
+	} else {
+		bnd.width = stringPtrBufferSize
+		for _, s := range strs {
+			if bnd.width < len(s) {
+				bnd.width = len(s)
+			}
+		}
+		if bnd.width < 1 {
+			bnd.width = 1
+		}
+		bnd.buf = make([]byte, capacity*bnd.width)
+		for n, s := range strs {
+			bnd.alens[n] = C.ACTUAL_LENGTH_TYPE(copy(bnd.buf[n*bnd.width:], s))
+		}
+		valuep = unsafe.Pointer(&bnd.buf[0])
+		valueSz = C.LENGTH_TYPE(bnd.width)
+		dty = C.SQLT_CHR
+	}
+	r := bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd),       //OCIBind      **bindpp,
+		position,                         //ub4          position,
+		valuep,                           //void         *valuep,
+		valueSz,                          //sb8          value_sz,
+		dty,                              //ub2          dty,
+		unsafe.Pointer(&bnd.nullInds[0]), //void         *indp,
+		&bnd.alens[0],                    //ub2          *alenp,
+		nil,                              //ub2          *rcodep,
+		C.ub4(capacity),                  //ub4          maxarr_len,
+		&bnd.curlen,                      //ub4          *curelep,
+		C.OCI_DEFAULT)                    //ub4          mode );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+// setPtr sets the pointed-to slice of an OUT or IN OUT PlsArr to the elements
+// returned by the server.
+func (bnd *bndPlsArr) setPtr() error {
+	length := int(bnd.curlen)
+	if bnd.int64s != nil {
+		if cap(*bnd.int64s) < length {
+			*bnd.int64s = make([]int64, length)
+		}
+		*bnd.int64s = (*bnd.int64s)[:length]
+		for n := range *bnd.int64s {
+			(*bnd.int64s)[n] = 0
+			if bnd.nullInds[n] < 0 {
+				continue
+			}
+			r := C.OCINumberToInt(
+				bnd.stmt.ses.srv.env.ocierr,       //OCIError              *err,
+				&bnd.ociNumbers[n],                //const OCINumber       *number,
+				C.uword(8),                        //uword                 rsl_length,
+				C.OCI_NUMBER_SIGNED,               //uword                 rsl_flag,
+				unsafe.Pointer(&(*bnd.int64s)[n])) //void                  *rsl );
+			if r == C.OCI_ERROR {
+				return bnd.stmt.ses.srv.env.ociError()
+			}
+		}
+	}
+	if bnd.strs != nil {
+		if cap(*bnd.strs) < length {
+			*bnd.strs = make([]string, length)
+		}
+		*bnd.strs = (*bnd.strs)[:length]
+		for n := range *bnd.strs {
+			(*bnd.strs)[n] = ""
+			if bnd.nullInds[n] < 0 {
+				continue
+			}
+			offset := n * bnd.width
+			(*bnd.strs)[n] = string(bnd.buf[offset : offset+int(bnd.alens[n])])
+		}
+	}
+	return nil
+}
+
+func (bnd *bndPlsArr) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	stmt := bnd.stmt
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	bnd.int64s = nil
+	bnd.strs = nil
+	bnd.ociNumbers = nil
+	bnd.buf = nil
+	bnd.width = 0
+	bnd.nullInds = nil
+	bnd.alens = nil
+	bnd.curlen = 0
+	stmt.putBnd(bndIdxPlsArr, bnd)
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+#include "version.h"
+
+// rowidReturning holds the rowids returned by each iteration of an array DML
+// statement. Its memory is allocated in C as OCI keeps the pointer until the
+// statement executes.
+typedef struct {
+	char  *buf;
+	ub4   width;
+	ub4   rows;
+	ub4   *alen;
+	sb2   *ind;
+	ub2   *rcode;
+} rowidReturning;
+
+// rowidReturningIn supplies no input data for a RETURNING bind.
+static sb4 rowidReturningIn(void *ictxp, OCIBind *bindp, ub4 iter, ub4 index,
+	void **bufpp, ub4 *alenp, ub1 *piecep, void **indpp) {
+	static sb2 nullInd = -1;
+	*bufpp = NULL;
+	*alenp = 0;
+	*indpp = &nullInd;
+	*piecep = OCI_ONE_PIECE;
+	return OCI_CONTINUE;
+}
+
+// rowidReturningOut supplies the buffer of the rowid returned by an iteration.
+// Only one row per iteration is supported.
+static sb4 rowidReturningOut(void *octxp, OCIBind *bindp, ub4 iter, ub4 index,
+	void **bufpp, ub4 **alenpp, ub1 *piecep, void **indpp, ub2 **rcodepp) {
+	rowidReturning *r = (rowidReturning *)octxp;
+	if (iter >= r->rows || index > 0) {
+		return OCI_ERROR;
+	}
+	*bufpp = r->buf + (size_t)iter * r->width;
+	r->alen[iter] = r->width;
+	*alenpp = &r->alen[iter];
+	*indpp = &r->ind[iter];
+	*rcodepp = &r->rcode[iter];
+	*piecep = OCI_ONE_PIECE;
+	return OCI_CONTINUE;
+}
+
+static sword rowidReturningBind(OCIBind *bindp, OCIError *errhp, rowidReturning *r) {
+	return OCIBindDynamic(bindp, errhp, r, rowidReturningIn, r, rowidReturningOut);
+}
+
+static rowidReturning *rowidReturningAlloc(ub4 rows, ub4 width) {
+	ub4 n, size = rows > 0 ? rows : 1;
+	rowidReturning *r = (rowidReturning *)calloc(1, sizeof(rowidReturning));
+	if (r == NULL) {
+		return NULL;
+	}
+	r->rows = rows;
+	r->width = width;
+	r->buf = (char *)calloc(size, width);
+	r->alen = (ub4 *)calloc(size, sizeof(ub4));
+	r->ind = (sb2 *)calloc(size, sizeof(sb2));
+	r->rcode = (ub2 *)calloc(size, sizeof(ub2));
+	if (r->buf == NULL || r->alen == NULL || r->ind == NULL || r->rcode == NULL) {
+		free(r->buf);
+		free(r->alen);
+		free(r->ind);
+		free(r->rcode);
+		free(r);
+		return NULL;
+	}
+	for (n = 0; n < rows; n++) {
+		r->ind[n] = -1; // rows which fail return no rowid
+	}
+	return r;
+}
+
+static void rowidReturningFree(rowidReturning *r) {
+	free(r->buf);
+	free(r->alen);
+	free(r->ind);
+	free(r->rcode);
+	free(r);
+}
+
+static sb2 rowidReturningInd(rowidReturning *r, ub4 n) { return r->ind[n]; }
+static ub2 rowidReturningRcode(rowidReturning *r, ub4 n) { return r->rcode[n]; }
+static char *rowidReturningValue(rowidReturning *r, ub4 n) { return r->buf + (size_t)n * r->width; }
+static ub4 rowidReturningLen(rowidReturning *r, ub4 n) { return r->alen[n]; }
+*/
+import "C"
+
+// maxReturnedRowidLen is the buffer length of each returned rowid; a physical
+// rowid is 18 characters.
+const maxReturnedRowidLen = 256
+
+// bndRowidSlicePtr binds a *[]Rowid receiving "RETURNING ROWID INTO" values
+// of an array DML statement.
+type bndRowidSlicePtr struct {
+	stmt      *Stmt
+	ocibnd    *C.OCIBind
+	value     *[]Rowid
+	returning *C.rowidReturning
+}
+
+func (bnd *bndRowidSlicePtr) bind(value *[]Rowid, iterations uint32, position int, stmt *Stmt) error {
+	bnd.stmt = stmt
+	bnd.value = value
+	bnd.returning = C.rowidReturningAlloc(C.ub4(iterations), maxReturnedRowidLen)
+	if bnd.returning == nil {
+		return er("Unable to allocate returned rowid buffer.")
+	}
+	// bind dynamically; OCI requests a buffer for the rowid of each iteration
+	r := bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd), //OCIBind      **bindpp,
+		position,                   //ub4          position,
+		nil,                        //void         *valuep,
+		maxReturnedRowidLen,        //sb8          value_sz,
+		C.SQLT_CHR,                 //ub2          dty,
+		nil,                        //void         *indp,
+		nil,                        //ub2          *alenp,
+		nil,                        //ub2          *rcodep,
+		0,                          //ub4          maxarr_len,
+		nil,                        //ub4          *curelep,
+		C.OCI_DATA_AT_EXEC)         //ub4          mode );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	r = C.rowidReturningBind(bnd.ocibnd, bnd.stmt.ses.srv.env.ocierr, bnd.returning)
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+func (bnd *bndRowidSlicePtr) setPtr() error {
+	rows := int(bnd.returning.rows)
+	if cap(*bnd.value) < rows {
+		*bnd.value = make([]Rowid, rows)
+	}
+	*bnd.value = (*bnd.value)[:rows]
+	for n := 0; n < rows; n++ {
+		if C.rowidReturningInd(bnd.returning, C.ub4(n)) < 0 {
+			(*bnd.value)[n] = Rowid{IsNull: true}
+			continue
+		}
+		if rcode := C.rowidReturningRcode(bnd.returning, C.ub4(n)); rcode != 0 {
+			return errF("Unable to return rowid of row %v (ORA-%05d).", n, int(rcode))
+		}
+		(*bnd.value)[n] = Rowid{Value: C.GoStringN(
+			C.rowidReturningValue(bnd.returning, C.ub4(n)),
+			C.int(C.rowidReturningLen(bnd.returning, C.ub4(n))))}
+	}
+	return nil
+}
+
+func (bnd *bndRowidSlicePtr) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+	stmt := bnd.stmt
+	if bnd.returning != nil {
+		C.rowidReturningFree(bnd.returning)
+	}
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	bnd.value = nil
+	bnd.returning = nil
+	stmt.putBnd(bndIdxRowidSlicePtr, bnd)
+	return nil
+}
@@ -30,11 +30,9 @@ func (bnd *bndRset) bind(value *Rset, position int, stmt *Stmt) error {
 	if err != nil {
 		return err
 	}
-	r := C.OCIBINDBYPOS(
-		stmt.ocistmt,                 //OCIStmt      *stmtp,
+	r := stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),   //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,  //OCIError     *errhp,
-		C.ub4(position),              //ub4          position,
+		position,                     //ub4          position,
 		unsafe.Pointer(&bnd.ocistmt), //void         *valuep,
 		0,                           //sb8          value_sz,
 		C.SQLT_RSET,                 //ub2          dty,
@@ -52,10 +50,21 @@ func (bnd *bndRset) bind(value *Rset, position int, stmt *Stmt) error {
 }
 
 func (bnd *bndRset) setPtr() error {
+	// re-execution replaces a cursor returned by a previous execution
+	if bnd.value.IsOpen() {
+		for e := bnd.stmt.openRsets.Front(); e != nil; e = e.Next() {
+			if e.Value.(*Rset) == bnd.value {
+				bnd.stmt.openRsets.Remove(e)
+				break
+			}
+		}
+		bnd.value.close()
+	}
 	err := bnd.value.open(bnd.stmt, bnd.ocistmt)
 	bnd.stmt.openRsets.PushBack(bnd.value)
 	if err == nil {
 		// open result set is successful; will be freed by Rset
+		bnd.value.isRefCursor = true
 		bnd.ocistmt = nil
 	}
 
@@ -23,11 +23,9 @@ type bndString struct {
 func (bnd *bndString) bind(value string, position int, stmt *Stmt) error {
 	bnd.stmt = stmt
 	bnd.cString = C.CString(value)
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,            //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),  //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
-		C.ub4(position),             //ub4          position,
+		position,                    //ub4          position,
 		unsafe.Pointer(bnd.cString), //void         *valuep,
 		C.LENGTH_TYPE(len(value)),   //sb8          value_sz,
 		C.SQLT_CHR,                  //ub2          dty,
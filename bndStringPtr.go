@@ -19,6 +19,47 @@ type bndStringPtr struct {
 	isNull C.sb2
 	value  *string
 	buf    []byte
+	alen   C.ACTUAL_LENGTH_TYPE
+	nullp  *bool
+	isOut  bool
+}
+
+// bindOut binds value as an IN OUT parameter, sending the current value or a
+// NULL when isNull is true. The buffer is enlarged to the current value when
+// it exceeds stringPtrBufferSize.
+func (bnd *bndStringPtr) bindOut(value *string, isNull *bool, position int, stringPtrBufferSize int, stmt *Stmt) error {
+	bnd.stmt = stmt
+	bnd.value = value
+	bnd.nullp = isNull
+	bnd.isOut = true
+	if stringPtrBufferSize < len(*value) {
+		stringPtrBufferSize = len(*value)
+	}
+	if cap(bnd.buf) < stringPtrBufferSize {
+		bnd.buf = make([]byte, stringPtrBufferSize)
+	}
+	bnd.buf = bnd.buf[:cap(bnd.buf)]
+	bnd.isNull = C.sb2(0)
+	if isNull != nil && *isNull {
+		bnd.isNull = C.sb2(-1)
+	}
+	bnd.alen = C.ACTUAL_LENGTH_TYPE(copy(bnd.buf, *value))
+	r := bnd.stmt.ociBind(
+		(**C.OCIBind)(&bnd.ocibnd),  //OCIBind      **bindpp,
+		position,                    //ub4          position,
+		unsafe.Pointer(&bnd.buf[0]), //void         *valuep,
+		C.LENGTH_TYPE(len(bnd.buf)), //sb8          value_sz,
+		C.SQLT_CHR,                  //ub2          dty,
+		unsafe.Pointer(&bnd.isNull), //void         *indp,
+		&bnd.alen,                   //ub2          *alenp,
+		nil,                         //ub2          *rcodep,
+		0,                           //ub4          maxarr_len,
+		nil,                         //ub4          *curelep,
+		C.OCI_DEFAULT)               //ub4          mode );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
 }
 
 func (bnd *bndStringPtr) bind(value *string, position int, stringPtrBufferSize int, stmt *Stmt) error {
@@ -27,11 +68,9 @@ func (bnd *bndStringPtr) bind(value *string, position int, stringPtrBufferSize i
 	if cap(bnd.buf) < stringPtrBufferSize {
 		bnd.buf = make([]byte, stringPtrBufferSize)
 	}
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,            //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),  //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
-		C.ub4(position),             //ub4          position,
+		position,                    //ub4          position,
 		unsafe.Pointer(&bnd.buf[0]), //void         *valuep,
 		C.LENGTH_TYPE(len(bnd.buf)), //sb8          value_sz,
 		C.SQLT_CHR,                  //ub2          dty,
@@ -48,6 +87,16 @@ func (bnd *bndStringPtr) bind(value *string, position int, stringPtrBufferSize i
 }
 
 func (bnd *bndStringPtr) setPtr() error {
+	if bnd.nullp != nil {
+		*bnd.nullp = bnd.isNull < C.sb2(0)
+	}
+	if bnd.isOut {
+		// the actual length excludes stale input bytes
+		if bnd.isNull > C.sb2(-1) {
+			*bnd.value = string(bnd.buf[:bnd.alen])
+		}
+		return nil
+	}
 	if bnd.isNull > C.sb2(-1) {
 		// Buffer is padded with Space char (32)
 		*bnd.value = stringTrimmed(bnd.buf, 32)
@@ -65,6 +114,9 @@ func (bnd *bndStringPtr) close() (err error) {
 	bnd.stmt = nil
 	bnd.ocibnd = nil
 	bnd.value = nil
+	bnd.nullp = nil
+	bnd.isOut = false
+	bnd.alen = 0
 	clear(bnd.buf, 32)
 	stmt.putBnd(bndIdxStringPtr, bnd)
 	return nil
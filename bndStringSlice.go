@@ -64,11 +64,9 @@ func (bnd *bndStringSlice) bind(values []string, nullInds []C.sb2, position int,
 		alenp[m] = C.ACTUAL_LENGTH_TYPE(len(values[m]))
 	}
 	bnd.bytes = bnd.buf.Bytes()
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,              //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),    //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,   //OCIError     *errhp,
-		C.ub4(position),               //ub4          position,
+		position,                      //ub4          position,
 		unsafe.Pointer(&bnd.bytes[0]), //void         *valuep,
 		C.LENGTH_TYPE(maxLen),         //sb8          value_sz,
 		C.SQLT_CHR,                    //ub2          dty,
@@ -11,6 +11,7 @@ package ora
 */
 import "C"
 import (
+	"bytes"
 	"time"
 	"unsafe"
 )
@@ -22,6 +23,42 @@ type bndTimePtr struct {
 	isNull      C.sb2
 	value       *time.Time
 	cZone       *C.char
+	zoneBuf     bytes.Buffer
+	nullp       *bool
+}
+
+// bindOut binds value as an IN OUT parameter, sending the current value or a
+// NULL when isNull is true.
+func (bnd *bndTimePtr) bindOut(value *time.Time, isNull *bool, position int, stmt *Stmt) error {
+	err := bnd.bind(value, position, stmt)
+	if err != nil {
+		return err
+	}
+	bnd.nullp = isNull
+	bnd.isNull = C.sb2(0)
+	if isNull != nil && *isNull {
+		bnd.isNull = C.sb2(-1)
+		return nil
+	}
+	zone := zoneOffset(*value, &bnd.zoneBuf)
+	bnd.cZone = C.CString(zone)
+	r := C.OCIDateTimeConstruct(
+		unsafe.Pointer(bnd.stmt.ses.srv.env.ocienv), //dvoid         *hndl,
+		bnd.stmt.ses.srv.env.ocierr,                 //OCIError      *err,
+		bnd.ociDateTime,                             //OCIDateTime   *datetime,
+		C.sb2(value.Year()),                         //sb2           year,
+		C.ub1(int32(value.Month())),                 //ub1           month,
+		C.ub1(value.Day()),                          //ub1           day,
+		C.ub1(value.Hour()),                         //ub1           hour,
+		C.ub1(value.Minute()),                       //ub1           min,
+		C.ub1(value.Second()),                       //ub1           sec,
+		C.ub4(value.Nanosecond()),                   //ub4           fsec,
+		(*C.OraText)(unsafe.Pointer(bnd.cZone)),     //OraText       *timezone,
+		C.size_t(len(zone)))                         //size_t        timezone_length );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
 }
 
 func (bnd *bndTimePtr) bind(value *time.Time, position int, stmt *Stmt) error {
@@ -38,11 +75,9 @@ func (bnd *bndTimePtr) bind(value *time.Time, position int, stmt *Stmt) error {
 	} else if r == C.OCI_INVALID_HANDLE {
 		return errNew("unable to allocate oci timestamp handle during bind")
 	}
-	r = C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                              //OCIStmt      *stmtp,
+	r = bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),                    //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,                   //OCIError     *errhp,
-		C.ub4(position),                               //ub4          position,
+		position,                                      //ub4          position,
 		unsafe.Pointer(&bnd.ociDateTime),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociDateTime)), //sb8          value_sz,
 		C.SQLT_TIMESTAMP_TZ,                           //ub2          dty,
@@ -59,6 +94,9 @@ func (bnd *bndTimePtr) bind(value *time.Time, position int, stmt *Stmt) error {
 }
 
 func (bnd *bndTimePtr) setPtr() (err error) {
+	if bnd.nullp != nil {
+		*bnd.nullp = bnd.isNull < C.sb2(0)
+	}
 	if bnd.value != nil && bnd.isNull > C.sb2(-1) {
 		*bnd.value, err = getTime(bnd.stmt.ses.srv.env, bnd.ociDateTime)
 	}
@@ -75,6 +113,8 @@ func (bnd *bndTimePtr) close() (err error) {
 	if bnd.cZone != nil {
 		C.free(unsafe.Pointer(bnd.cZone))
 		bnd.cZone = nil
+	}
+	if bnd.ociDateTime != nil {
 		C.OCIDescriptorFree(
 			unsafe.Pointer(bnd.ociDateTime), //void     *descp,
 			C.OCI_DTYPE_TIMESTAMP_TZ)        //ub4      type );
@@ -84,6 +124,7 @@ func (bnd *bndTimePtr) close() (err error) {
 	bnd.ocibnd = nil
 	bnd.ociDateTime = nil
 	bnd.value = nil
+	bnd.nullp = nil
 	stmt.putBnd(bndIdxTimePtr, bnd)
 	return nil
 }
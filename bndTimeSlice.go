@@ -80,11 +80,9 @@ func (bnd *bndTimeSlice) bind(values []time.Time, nullInds []C.sb2, position int
 		alenp[n] = C.ACTUAL_LENGTH_TYPE(unsafe.Sizeof(bnd.ociDateTimes[n]))
 	}
 
-	r := C.OCIBINDBYPOS(
-		bnd.stmt.ocistmt,                                  //OCIStmt      *stmtp,
+	r := bnd.stmt.ociBind(
 		(**C.OCIBind)(&bnd.ocibnd),                        //OCIBind      **bindpp,
-		bnd.stmt.ses.srv.env.ocierr,                       //OCIError     *errhp,
-		C.ub4(position),                                   //ub4          position,
+		position,                                          //ub4          position,
 		unsafe.Pointer(&bnd.ociDateTimes[0]),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociDateTimes[0])), //sb8          value_sz,
 		C.SQLT_TIMESTAMP_TZ,                               //ub2          dty,
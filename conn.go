@@ -6,6 +6,7 @@ package ora
 
 import (
 	"container/list"
+	"context"
 	"database/sql/driver"
 	"fmt"
 )
@@ -53,6 +54,10 @@ type Con struct {
 	srv  *Srv
 	ses  *Ses
 	elem *list.Element
+
+	// isBroken is set when an Oracle error of a lost connection is returned
+	// after a statement was sent to the server.
+	isBroken bool
 }
 
 // checkIsOpen validates that the connection is open.
@@ -91,6 +96,7 @@ func (con *Con) Close() (err error) {
 		con.srv = nil
 		con.ses = nil
 		con.elem = nil
+		con.isBroken = false
 		_drv.conPool.Put(con)
 	}()
 
@@ -103,43 +109,120 @@ func (con *Con) Close() (err error) {
 	return err
 }
 
+// IsValid returns false when the connection is closed, or when a statement
+// failed with an Oracle error of a lost connection, such as ORA-03113, or of a
+// call timeout, ORA-03156, so that database/sql discards the connection rather
+// than returning it to its pool.
+//
+// IsValid is a member of the driver.Validator interface.
+func (con *Con) IsValid() bool {
+	return con.IsOpen() && !con.isBroken
+}
+
+// markBroken marks the connection as broken when err is an Oracle error of a
+// lost connection or of a call interrupted by StmtCfg.CallTimeout, which OCI
+// may be unable to reset, and returns err.
+//
+// The error is returned as is rather than as driver.ErrBadConn because the
+// statement may have run on the server, and database/sql would run it again.
+func (con *Con) markBroken(err error) error {
+	if oe, ok := err.(*Error); ok && (badConnCodes[oe.Code] || oe.Timeout()) {
+		con.isBroken = true
+	}
+	return err
+}
+
 // Prepare readies a sql string for use.
 //
+// Prepare returns driver.ErrBadConn when the connection is broken.
+//
 // Prepare is a member of the driver.Conn interface.
 func (con *Con) Prepare(sql string) (driver.Stmt, error) {
 	con.log(_drv.cfg.Log.Con.Prepare)
 	if err := con.checkIsOpen(); err != nil {
 		return nil, err
 	}
+	if con.isBroken {
+		return nil, driver.ErrBadConn
+	}
 	stmt, err := con.ses.Prep(sql)
 	if err != nil {
-		return nil, err
+		return nil, badConn(err)
 	}
-	return &DrvStmt{stmt: stmt}, err
+	return &DrvStmt{stmt: stmt, con: con}, err
 }
 
 // Begin starts a transaction.
 //
+// Begin returns driver.ErrBadConn when the connection is broken.
+//
 // Begin is a member of the driver.Conn interface.
 func (con *Con) Begin() (driver.Tx, error) {
 	con.log(_drv.cfg.Log.Con.Begin)
 	if err := con.checkIsOpen(); err != nil {
 		return nil, err
 	}
+	if con.isBroken {
+		return nil, driver.ErrBadConn
+	}
 	tx, err := con.ses.StartTx()
 	if err != nil {
-		return nil, err
+		return nil, badConn(err)
 	}
 	return tx, nil
 }
 
-// Ping makes a round-trip call to an Oracle server to confirm that the connection is active.
-func (con *Con) Ping() error {
+// Ping makes a round-trip call to an Oracle server to confirm that the
+// connection is active, returning driver.ErrBadConn when it isn't, or when
+// the connection is broken, so that database/sql discards the connection and
+// opens another.
+//
+// The round trip runs on a separate goroutine. When ctx is done before the
+// round trip completes, the call is interrupted with OCIBreak and ctx.Err() is
+// returned.
+//
+// Ping is a member of the driver.Pinger interface.
+func (con *Con) Ping(ctx context.Context) error {
 	con.log(_drv.cfg.Log.Con.Ping)
 	if err := con.checkIsOpen(); err != nil {
 		return err
 	}
-	return con.srv.Ping()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if con.isBroken {
+		return driver.ErrBadConn
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- con.ses.Ping()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			con.logF(_drv.cfg.Log.Con.Ping, "%v", err)
+			return driver.ErrBadConn
+		}
+		return nil
+	case <-ctx.Done():
+		con.srv.breakCall()
+		<-done // wait for the interrupted call to return
+		// reset even when the call completed first, so that no break remains
+		// pending on the service context
+		if err := con.srv.resetCall(); err != nil {
+			con.logF(_drv.cfg.Log.Con.Ping, "%v", err)
+			return driver.ErrBadConn
+		}
+		return ctx.Err()
+	}
+}
+
+// CheckNamedValue passes ora.Number and ora.Decimal parameters to the driver
+// unchanged, as described for DrvStmt.CheckNamedValue.
+//
+// CheckNamedValue is a member of the driver.NamedValueChecker interface.
+func (con *Con) CheckNamedValue(nv *driver.NamedValue) error {
+	return checkNamedValue(nv)
 }
 
 // sysName returns a string representing the Con.
@@ -168,3 +251,8 @@ func (con *Con) logF(enabled bool, format string, v ...interface{}) {
 		}
 	}
 }
+
+var _ = driver.Pinger((*Con)(nil))
+var _ = driver.Validator((*Con)(nil))
+var _ = driver.NamedValueChecker((*Con)(nil))
+var _ = driver.NamedValueChecker((*DrvStmt)(nil))
@@ -67,6 +67,108 @@ const (
 	Bin
 	// OraBin defines a sql select column as a nullable Go ora.Binary.
 	OraBin
+	// Dur defines an INTERVAL DAY TO SECOND sql select column as a Go time.Duration.
+	Dur
+	// BigInt defines a NUMBER sql select column as a Go *big.Int.
+	BigInt
+	// Dec defines a NUMBER sql select column as a nullable Go ora.Decimal.
+	Dec
+	// OraRowid defines a ROWID or UROWID sql select column as a nullable Go ora.Rowid.
+	OraRowid
+)
+
+// DateFraction determines how a time.Time parameter with fractional seconds is
+// bound for an Oracle DATE, which stores whole seconds.
+type DateFraction uint
+
+// date fraction policies
+const (
+	// KeepFraction binds a time.Time parameter with its fractional seconds,
+	// leaving any conversion to a DATE to the Oracle server.
+	KeepFraction DateFraction = iota
+	// TruncateFraction discards the fractional seconds of a time.Time parameter.
+	TruncateFraction
+	// RoundFraction rounds a time.Time parameter to the nearest second.
+	RoundFraction
+	// ErrorFraction returns an error for a time.Time parameter with fractional
+	// seconds.
+	ErrorFraction
+)
+
+// Purity determines whether a session obtained from a Database Resident
+// Connection Pool may carry the state of its previous use.
+type Purity uint8
+
+// purities
+const (
+	// PurityDefault leaves the purity to the Oracle client; a standalone
+	// session is given PurityNew.
+	PurityDefault Purity = iota
+	// PurityNew requests a session without state from a previous use.
+	PurityNew
+	// PuritySelf permits a session used previously with the same
+	// connection class, retaining its session state.
+	PuritySelf
+)
+
+// Privilege determines the administrative privilege with which a session
+// connects.
+type Privilege uint8
+
+// privileges
+const (
+	// PrivilegeDefault connects without an administrative privilege.
+	PrivilegeDefault Privilege = iota
+	// PrivilegeSysDBA connects AS SYSDBA.
+	PrivilegeSysDBA
+	// PrivilegeSysOper connects AS SYSOPER.
+	PrivilegeSysOper
+)
+
+// Isolation determines the isolation of the transactions of a session.
+type Isolation uint8
+
+// isolation levels
+const (
+	// IsolationReadCommitted sees data committed before each statement
+	// began. It's Oracle's default.
+	IsolationReadCommitted Isolation = iota
+	// IsolationSerializable sees data committed before the transaction
+	// began. Modifying a row changed by a transaction committed after the
+	// transaction began fails with ORA-08177.
+	IsolationSerializable
+	// IsolationReadOnly is serializable and permits queries only.
+	IsolationReadOnly
+)
+
+// Visibility determines whether a queue operation is part of the session's
+// transaction.
+type Visibility uint8
+
+// visibilities
+const (
+	// VisibilityOnCommit makes the operation part of the session's
+	// transaction. It's Oracle's default.
+	VisibilityOnCommit Visibility = iota
+	// VisibilityImmediate makes the operation an autonomous transaction
+	// which commits whether or not the session's transaction commits.
+	VisibilityImmediate
+)
+
+// Navigation determines which message a dequeue returns.
+type Navigation uint8
+
+// navigations
+const (
+	// NavigationNextMsg dequeues the next message available to the session.
+	// It's Oracle's default.
+	NavigationNextMsg Navigation = iota
+	// NavigationFirstMsg dequeues the first message of the queue, restarting
+	// from the queue's beginning.
+	NavigationFirstMsg
+	// NavigationNextTransaction skips the remaining messages of the current
+	// transaction group, dequeuing the first message of the next.
+	NavigationNextTransaction
 )
 
 // bind pool indexes
@@ -127,8 +229,16 @@ const (
 	bndIdxIntervalDS
 	bndIdxIntervalDSSlice
 
+	bndIdxBigInt
+	bndIdxDecimal
+	bndIdxAnyData
+	bndIdxObject
+	bndIdxColl
+	bndIdxPlsArr
+
 	bndIdxBfile
 	bndIdxRset
+	bndIdxRowidSlicePtr
 	bndIdxNil
 )
 
@@ -152,9 +262,15 @@ const (
 	defIdxLob
 	defIdxRaw
 	defIdxLongRaw
+	defIdxLong
 
 	defIdxIntervalYM
 	defIdxIntervalDS
 	defIdxBfile
+	defIdxBigInt
+	defIdxDecimal
+	defIdxAnyData
+	defIdxObject
+	defIdxColl
 	defIdxRowid
 )
@@ -0,0 +1,202 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+type defAnyData struct {
+	rset    *Rset
+	ocidef  *C.OCIDefine
+	anyData *C.OCIAnyData
+	null    *C.OCIInd
+}
+
+func (def *defAnyData) define(position int, rset *Rset) error {
+	def.rset = rset
+	tdo, err := def.rset.stmt.ses.typeByName("SYS", "ANYDATA")
+	if err != nil {
+		return err
+	}
+	r := C.OCIDEFINEBYPOS(
+		def.rset.ocistmt,                 //OCIStmt     *stmtp,
+		&def.ocidef,                      //OCIDefine   **defnpp,
+		def.rset.stmt.ses.srv.env.ocierr, //OCIError    *errhp,
+		C.ub4(position),                  //ub4         position,
+		nil,                              //void        *valuep,
+		0,                                //sb8         value_sz,
+		C.SQLT_NTY,                       //ub2         dty,
+		nil,                              //void        *indp,
+		nil,                              //ub2         *rlenp,
+		nil,                              //ub2         *rcodep,
+		C.OCI_DEFAULT)                    //ub4         mode );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	r = C.OCIDefineObject(
+		def.ocidef,                       //OCIDefine       *defnp,
+		def.rset.stmt.ses.srv.env.ocierr, //OCIError        *errhp,
+		tdo,                              //const OCIType   *type,
+		(*unsafe.Pointer)(unsafe.Pointer(&def.anyData)), //void            **pgvpp,
+		nil, //ub4             *pvszsp,
+		(*unsafe.Pointer)(unsafe.Pointer(&def.null)), //void            **indpp,
+		nil) //ub4             *indszp );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+func (def *defAnyData) value() (value interface{}, err error) {
+	anyDataValue := AnyData{IsNull: def.anyData == nil || def.null == nil || *def.null == C.OCI_IND_NULL}
+	if !anyDataValue.IsNull {
+		anyDataValue.Value, err = getAnyData(def.rset.stmt.ses, def.anyData)
+		// an ANYDATA may hold a typed NULL
+		anyDataValue.IsNull = anyDataValue.Value == nil
+	}
+	return anyDataValue, err
+}
+
+func (def *defAnyData) alloc() error {
+	return nil
+}
+
+func (def *defAnyData) free() {
+	defer func() {
+		recover()
+	}()
+	if def.anyData != nil {
+		C.OCIObjectFree(
+			def.rset.stmt.ses.srv.env.ocienv, //OCIEnv      *env,
+			def.rset.stmt.ses.srv.env.ocierr, //OCIError    *err,
+			unsafe.Pointer(def.anyData),      //void        *instance,
+			C.OCI_OBJECTFREE_FORCE)           //ub2         flags );
+		def.anyData = nil
+		def.null = nil
+	}
+}
+
+func (def *defAnyData) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	def.anyData = nil
+	def.null = nil
+	rset.putDef(defIdxAnyData, def)
+	return nil
+}
+
+// getAnyData converts the scalar held by an ANYDATA instance to a Go value.
+// A NUMBER is returned as an int64 when it's integral and fits; otherwise, a
+// float64. A VARCHAR2, CHAR or VARCHAR is returned as a string, and a DATE as a
+// time.Time in the local time zone. A contained NULL is returned as nil.
+func getAnyData(ses *Ses, anyData *C.OCIAnyData) (interface{}, error) {
+	env := ses.srv.env
+	var typeCode C.OCITypeCode
+	var tdo *C.OCIType
+	r := C.OCIAnyDataGetType(
+		ses.srv.ocisvcctx, //OCISvcCtx     *svchp,
+		env.ocierr,        //OCIError      *errhp,
+		anyData,           //OCIAnyData    *sdata,
+		&typeCode,         //OCITypeCode   *tc,
+		&tdo)              //OCIType       **type );
+	if r == C.OCI_ERROR {
+		return nil, env.ociError()
+	}
+	var null C.OCIInd
+	var length C.ub4
+	switch typeCode {
+	case C.OCI_TYPECODE_NUMBER:
+		var ociNumber C.OCINumber
+		r = C.OCIAnyDataAccess(
+			ses.srv.ocisvcctx,          //OCISvcCtx     *svchp,
+			env.ocierr,                 //OCIError      *errhp,
+			anyData,                    //OCIAnyData    *sdata,
+			typeCode,                   //OCITypeCode   tc,
+			nil,                        //OCIType       *type,
+			unsafe.Pointer(&null),      //void          *indp,
+			unsafe.Pointer(&ociNumber), //void          *data_value,
+			&length)                    //ub4           *length );
+		if r == C.OCI_ERROR {
+			return nil, env.ociError()
+		}
+		if null == C.OCI_IND_NULL {
+			return nil, nil
+		}
+		text, err := env.numberToText(&ociNumber)
+		if err != nil {
+			return nil, err
+		}
+		if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return value, nil
+		}
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, errE(err)
+		}
+		return value, nil
+	case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_CHAR, C.OCI_TYPECODE_VARCHAR:
+		var ociString *C.OCIString
+		r = C.OCIAnyDataAccess(
+			ses.srv.ocisvcctx,          //OCISvcCtx     *svchp,
+			env.ocierr,                 //OCIError      *errhp,
+			anyData,                    //OCIAnyData    *sdata,
+			typeCode,                   //OCITypeCode   tc,
+			nil,                        //OCIType       *type,
+			unsafe.Pointer(&null),      //void          *indp,
+			unsafe.Pointer(&ociString), //void          *data_value,
+			&length)                    //ub4           *length );
+		if r == C.OCI_ERROR {
+			return nil, env.ociError()
+		}
+		if null == C.OCI_IND_NULL || ociString == nil {
+			return nil, nil
+		}
+		return C.GoStringN(
+			(*C.char)(unsafe.Pointer(C.OCIStringPtr(env.ocienv, ociString))),
+			C.int(C.OCIStringSize(env.ocienv, ociString))), nil
+	case C.OCI_TYPECODE_DATE:
+		var ociDate C.OCIDate
+		r = C.OCIAnyDataAccess(
+			ses.srv.ocisvcctx,        //OCISvcCtx     *svchp,
+			env.ocierr,               //OCIError      *errhp,
+			anyData,                  //OCIAnyData    *sdata,
+			typeCode,                 //OCITypeCode   tc,
+			nil,                      //OCIType       *type,
+			unsafe.Pointer(&null),    //void          *indp,
+			unsafe.Pointer(&ociDate), //void          *data_value,
+			&length)                  //ub4           *length );
+		if r == C.OCI_ERROR {
+			return nil, env.ociError()
+		}
+		if null == C.OCI_IND_NULL {
+			return nil, nil
+		}
+		return time.Date(
+			int(ociDate.OCIDateYYYY),
+			time.Month(int(ociDate.OCIDateMM)),
+			int(ociDate.OCIDateDD),
+			int(ociDate.OCIDateTime.OCITimeHH),
+			int(ociDate.OCIDateTime.OCITimeMI),
+			int(ociDate.OCIDateTime.OCITimeSS),
+			0,
+			time.Local), nil
+	}
+	return nil, errF("Unsupported ANYDATA type code (%v). Only NUMBER, VARCHAR2, CHAR, VARCHAR and DATE are supported.", typeCode)
+}
@@ -0,0 +1,79 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"math/big"
+	"unsafe"
+)
+
+type defBigInt struct {
+	rset      *Rset
+	ocidef    *C.OCIDefine
+	ociNumber C.OCINumber
+	null      C.sb2
+}
+
+func (def *defBigInt) define(position int, rset *Rset) error {
+	def.rset = rset
+	r := C.OCIDEFINEBYPOS(
+		def.rset.ocistmt,                  //OCIStmt     *stmtp,
+		&def.ocidef,                       //OCIDefine   **defnpp,
+		def.rset.stmt.ses.srv.env.ocierr,  //OCIError    *errhp,
+		C.ub4(position),                   //ub4         position,
+		unsafe.Pointer(&def.ociNumber),    //void        *valuep,
+		C.LENGTH_TYPE(C.sizeof_OCINumber), //sb8         value_sz,
+		C.SQLT_VNU,                        //ub2         dty,
+		unsafe.Pointer(&def.null),         //void        *indp,
+		nil,           //ub2         *rlenp,
+		nil,           //ub2         *rcodep,
+		C.OCI_DEFAULT) //ub4         mode );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+func (def *defBigInt) value() (value interface{}, err error) {
+	if def.null < C.sb2(0) {
+		return nil, nil
+	}
+	text, err := def.rset.stmt.ses.srv.env.numberToText(&def.ociNumber)
+	if err != nil {
+		return nil, err
+	}
+	bigIntValue, ok := new(big.Int).SetString(text, 10)
+	if !ok {
+		return nil, errF("Unable to convert NUMBER value (%v) to big.Int.", text)
+	}
+	return bigIntValue, nil
+}
+
+func (def *defBigInt) alloc() error {
+	return nil
+}
+
+func (def *defBigInt) free() {
+
+}
+
+func (def *defBigInt) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	rset.putDef(defIdxBigInt, def)
+	return nil
+}
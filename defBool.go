@@ -62,8 +62,7 @@ func (def *defBool) value() (value interface{}, err error) {
 		r, _ := utf8.DecodeRune(def.buf)
 		return r == def.rset.stmt.cfg.Rset.TrueRune, nil
 	}
-	// NULL is false, too
-	return false, nil
+	return nil, nil
 }
 
 func (def *defBool) alloc() error {
@@ -0,0 +1,172 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"reflect"
+	"unsafe"
+)
+
+type defColl struct {
+	rset     *Rset
+	ocidef   *C.OCIDefine
+	typ      *objType
+	instance unsafe.Pointer
+	null     unsafe.Pointer // null indicator of the instance
+}
+
+func (def *defColl) define(position int, typ *objType, rset *Rset) error {
+	def.rset = rset
+	def.typ = typ
+	if _, err := typ.elemType(); err != nil {
+		return err
+	}
+	r := C.OCIDEFINEBYPOS(
+		def.rset.ocistmt,                 //OCIStmt     *stmtp,
+		&def.ocidef,                      //OCIDefine   **defnpp,
+		def.rset.stmt.ses.srv.env.ocierr, //OCIError    *errhp,
+		C.ub4(position),                  //ub4         position,
+		nil,                              //void        *valuep,
+		0,                                //sb8         value_sz,
+		C.SQLT_NTY,                       //ub2         dty,
+		nil,                              //void        *indp,
+		nil,                              //ub2         *rlenp,
+		nil,                              //ub2         *rcodep,
+		C.OCI_DEFAULT)                    //ub4         mode );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	r = C.OCIDefineObject(
+		def.ocidef,                       //OCIDefine       *defnp,
+		def.rset.stmt.ses.srv.env.ocierr, //OCIError        *errhp,
+		typ.tdo,                          //const OCIType   *type,
+		&def.instance,                    //void            **pgvpp,
+		nil,                              //ub4             *pvszsp,
+		&def.null,                        //void            **indpp,
+		nil)                              //ub4             *indszp );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+// value returns a Coll holding a slice of the collection's elements.
+func (def *defColl) value() (value interface{}, err error) {
+	coll := Coll{TypeName: def.typ.name}
+	if def.instance == nil || def.null == nil || *(*C.OCIInd)(def.null) == C.OCI_IND_NULL {
+		coll.IsNull = true
+		return coll, nil
+	}
+	coll.Value, err = def.typ.getColl(def.rset.stmt.ses, (*C.OCIColl)(def.instance))
+	if err != nil {
+		return nil, err
+	}
+	return coll, nil
+}
+
+func (def *defColl) alloc() error {
+	return nil
+}
+
+func (def *defColl) free() {
+	defer func() {
+		recover()
+	}()
+	if def.instance != nil {
+		C.OCIObjectFree(
+			def.rset.stmt.ses.srv.env.ocienv, //OCIEnv      *env,
+			def.rset.stmt.ses.srv.env.ocierr, //OCIError    *err,
+			def.instance,                     //void        *instance,
+			C.OCI_OBJECTFREE_FORCE)           //ub2         flags );
+		def.instance = nil
+		def.null = nil
+	}
+}
+
+func (def *defColl) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	def.typ = nil
+	def.instance = nil
+	def.null = nil
+	rset.putDef(defIdxColl, def)
+	return nil
+}
+
+// elemType returns the Go slice element type of a fetched collection.
+func (typ *objType) elemType() (reflect.Type, error) {
+	switch typ.elem.typeCode {
+	case C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE:
+		return reflect.TypeOf(float64(0)), nil
+	case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT, C.OCI_TYPECODE_DECIMAL:
+		// as with NUMBER columns, a FLOAT or positive scale is a float
+		if typ.elem.precision != 0 && (typ.elem.scale > 0 || typ.elem.scale == -127) {
+			return reflect.TypeOf(float64(0)), nil
+		}
+		return reflect.TypeOf(int64(0)), nil
+	case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_CHAR, C.OCI_TYPECODE_VARCHAR:
+		return reflect.TypeOf(""), nil
+	}
+	return nil, errF("Unsupported element type of collection type %v (typecode %v). Only NUMBER, VARCHAR2, CHAR and VARCHAR elements are supported.", typ.name, typ.elem.typeCode)
+}
+
+// getColl returns a slice of the elements of a collection instance. No
+// locking occurs.
+func (typ *objType) getColl(ses *Ses, coll *C.OCIColl) (interface{}, error) {
+	elemType, err := typ.elemType()
+	if err != nil {
+		return nil, err
+	}
+	env := ses.srv.env
+	var size C.sb4
+	r := C.OCICollSize(env.ocienv, env.ocierr, coll, &size)
+	if r == C.OCI_ERROR {
+		return nil, env.ociError()
+	}
+	values := reflect.MakeSlice(reflect.SliceOf(elemType), 0, int(size))
+	for n := C.sb4(0); n < size; n++ {
+		var exists C.boolean
+		var elem, elemNull unsafe.Pointer
+		r = C.OCICollGetElem(env.ocienv, env.ocierr, coll, n, &exists, &elem, &elemNull)
+		if r == C.OCI_ERROR {
+			return nil, env.ociError()
+		}
+		if exists == C.FALSE { // deleted from a nested table
+			continue
+		}
+		rv := reflect.New(elemType).Elem()
+		if elem != nil && (elemNull == nil || *(*C.OCIInd)(elemNull) != C.OCI_IND_NULL) {
+			if elemType.Kind() == reflect.String {
+				ociString := *(**C.OCIString)(elem)
+				rv.SetString(C.GoStringN(
+					(*C.char)(unsafe.Pointer(C.OCIStringPtr(env.ocienv, ociString))),
+					C.int(C.OCIStringSize(env.ocienv, ociString))))
+			} else {
+				text, err := env.numberToText((*C.OCINumber)(elem))
+				if err != nil {
+					return nil, err
+				}
+				err = setObjNumber(rv, text)
+				if err != nil {
+					return nil, errF("Unable to get element %v of collection type %v: %v", n, typ.name, err)
+				}
+			}
+		}
+		values = reflect.Append(values, rv)
+	}
+	return values.Interface(), nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"strings"
+	"unsafe"
+)
+
+type defDecimal struct {
+	rset      *Rset
+	ocidef    *C.OCIDefine
+	ociNumber C.OCINumber
+	null      C.sb2
+	scale     int
+}
+
+func (def *defDecimal) define(position int, scale int, rset *Rset) error {
+	def.rset = rset
+	def.scale = scale
+	r := C.OCIDEFINEBYPOS(
+		def.rset.ocistmt,                  //OCIStmt     *stmtp,
+		&def.ocidef,                       //OCIDefine   **defnpp,
+		def.rset.stmt.ses.srv.env.ocierr,  //OCIError    *errhp,
+		C.ub4(position),                   //ub4         position,
+		unsafe.Pointer(&def.ociNumber),    //void        *valuep,
+		C.LENGTH_TYPE(C.sizeof_OCINumber), //sb8         value_sz,
+		C.SQLT_VNU,                        //ub2         dty,
+		unsafe.Pointer(&def.null),         //void        *indp,
+		nil,           //ub2         *rlenp,
+		nil,           //ub2         *rcodep,
+		C.OCI_DEFAULT) //ub4         mode );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+func (def *defDecimal) value() (value interface{}, err error) {
+	decimalValue := Decimal{IsNull: def.null < C.sb2(0)}
+	if !decimalValue.IsNull {
+		text, err := def.rset.stmt.ses.srv.env.numberToText(&def.ociNumber)
+		if err != nil {
+			return nil, err
+		}
+		decimalValue.Value = padScale(text, def.scale)
+	}
+	return decimalValue, nil
+}
+
+// padScale returns decimal text with a leading zero before a bare decimal
+// point and with fractional digits padded to the specified scale.
+func padScale(text string, scale int) string {
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	if strings.HasPrefix(text, ".") {
+		text = "0" + text
+	}
+	if scale > 0 {
+		index := strings.IndexByte(text, '.')
+		if index < 0 {
+			text += "."
+			index = len(text) - 1
+		}
+		if fraction := len(text) - index - 1; fraction < scale {
+			text += strings.Repeat("0", scale-fraction)
+		}
+	}
+	return sign + text
+}
+
+func (def *defDecimal) alloc() error {
+	return nil
+}
+
+func (def *defDecimal) free() {
+
+}
+
+func (def *defDecimal) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	def.scale = 0
+	rset.putDef(defIdxDecimal, def)
+	return nil
+}
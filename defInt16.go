@@ -19,11 +19,15 @@ type defInt16 struct {
 	ociNumber  C.OCINumber
 	null       C.sb2
 	isNullable bool
+	position   int
+	ociNumbers []C.OCINumber // buffers of an array fetch
+	nulls      []C.sb2
 }
 
 func (def *defInt16) define(position int, isNullable bool, rset *Rset) error {
 	def.rset = rset
 	def.isNullable = isNullable
+	def.position = position
 	r := C.OCIDEFINEBYPOS(
 		def.rset.ocistmt,                  //OCIStmt     *stmtp,
 		&def.ocidef,                       //OCIDefine   **defnpp,
@@ -42,6 +46,24 @@ func (def *defInt16) define(position int, isNullable bool, rset *Rset) error {
 	return nil
 }
 
+// defineArray redefines the column to fetch rows values per fetch.
+func (def *defInt16) defineArray(rows int) (err error) {
+	def.ociNumbers, def.nulls, err = def.rset.defineNumbers(def.position, &def.ocidef, rows, def.ociNumbers, def.nulls)
+	return err
+}
+
+// setRow selects the row of an array fetch returned by value.
+func (def *defInt16) setRow(row int) {
+	def.ociNumber = def.ociNumbers[row]
+	def.null = def.nulls[row]
+}
+
+// stringValue gets the decimal text of the Oracle number, for a value which
+// can't be converted to int16.
+func (def *defInt16) stringValue() (interface{}, error) {
+	return def.rset.numberString(&def.ociNumber, def.null, def.isNullable)
+}
+
 func (def *defInt16) alloc() error {
 	return nil
 }
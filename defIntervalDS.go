@@ -20,10 +20,12 @@ type defIntervalDS struct {
 	ocidef      *C.OCIDefine
 	ociInterval *C.OCIInterval
 	null        C.sb2
+	gct         GoColumnType
 }
 
-func (def *defIntervalDS) define(position int, rset *Rset) error {
+func (def *defIntervalDS) define(position int, gct GoColumnType, rset *Rset) error {
 	def.rset = rset
+	def.gct = gct
 	r := C.OCIDEFINEBYPOS(
 		def.rset.ocistmt,                              //OCIStmt     *stmtp,
 		&def.ocidef,                                   //OCIDefine   **defnpp,
@@ -68,6 +70,12 @@ func (def *defIntervalDS) value() (value interface{}, err error) {
 		intervalDS.Second = int32(second)
 		intervalDS.Nanosecond = int32(nanosecond)
 	}
+	if def.gct == Dur {
+		if intervalDS.IsNull {
+			return nil, err
+		}
+		return intervalDS.Duration(), err
+	}
 	return intervalDS, err
 }
 
@@ -106,6 +114,7 @@ func (def *defIntervalDS) close() (err error) {
 	def.rset = nil
 	def.ocidef = nil
 	def.ociInterval = nil
+	def.gct = D
 	rset.putDef(defIdxIntervalDS, def)
 	return nil
 }
@@ -12,6 +12,7 @@ import "C"
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"sync"
 	"unsafe"
 )
@@ -57,7 +58,7 @@ func (def *defLob) define(position int, charsetForm C.ub1, sqlt C.ub2, gct GoCol
 		return def.rset.stmt.ses.srv.env.ociError()
 	}
 	prefetchLength := C.boolean(C.TRUE)
-	return def.rset.stmt.ses.srv.env.setAttr(unsafe.Pointer(def.ocidef), C.OCI_HTYPE_DEFINE, unsafe.Pointer(&prefetchLength), 0, C.OCI_ATTR_LOBPREFETCH_LENGTH)
+	return def.rset.stmt.ses.srv.env.setOptionalAttr(unsafe.Pointer(def.ocidef), C.OCI_HTYPE_DEFINE, unsafe.Pointer(&prefetchLength), 0, C.OCI_ATTR_LOBPREFETCH_LENGTH, "OCI_ATTR_LOBPREFETCH_LENGTH")
 }
 
 func (def *defLob) Bytes() (value []byte, err error) {
@@ -139,6 +140,9 @@ func (def *defLob) Reader() (io.Reader, error) {
 func (def *defLob) value() (value interface{}, err error) {
 	//lob := def.ociLobLocator
 	//Log.Infof("value %p null=%d", lob, def.null)
+	if def.rset.stmt.cfg.IsMaterializingLobs {
+		return def.materialize()
+	}
 	if def.gct == Bin {
 		if def.null > C.sb2(-1) {
 			return def.Reader()
@@ -146,14 +150,55 @@ func (def *defLob) value() (value interface{}, err error) {
 		return value, err
 	}
 	if def.null < C.sb2(0) {
+		if def.gct == S {
+			return nil, nil
+		}
 		return Lob{}, nil
 	}
 	var r io.Reader
 	r, err = def.Reader()
 	binValue := Lob{Reader: r}
+	if closer, ok := r.(io.Closer); ok {
+		binValue.Closer = closer
+	}
 	//Log.Infof("value %p returns %#v (%v)", lob, binValue, err)
 	return binValue, err
 }
+
+// materialize reads the whole LOB, returning a string or ora.String for a CLOB
+// or NCLOB, and a []byte or ora.Raw for a BLOB.
+func (def *defLob) materialize() (value interface{}, err error) {
+	isNull := def.null < C.sb2(0)
+	var buf []byte
+	if !isNull {
+		var r io.Reader
+		r, err = def.Reader()
+		if err != nil {
+			return nil, err
+		}
+		buf, err = ioutil.ReadAll(r)
+		if closer, ok := r.(io.Closer); ok {
+			closer.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch def.gct {
+	case OraS:
+		return String{IsNull: isNull, Value: string(buf)}, nil
+	case OraBin:
+		return Raw{IsNull: isNull, Value: buf}, nil
+	}
+	if isNull {
+		return nil, nil
+	}
+	if def.gct == Bin {
+		return buf, nil
+	}
+	return string(buf), nil
+}
+
 func (def *defLob) alloc() error {
 	// Allocate lob locator handle
 	// OCI_DTYPE_LOB is for a BLOB or CLOB
@@ -239,6 +284,20 @@ func lobClose(srv *Srv, lob *C.OCILobLocator) error {
 	return nil
 }
 
+// bfileClose closes a file opened with OCILobFileOpen and frees its locator.
+func bfileClose(srv *Srv, file *C.OCILobLocator) error {
+	r := C.OCILobFileClose(
+		srv.ocisvcctx,  //OCISvcCtx          *svchp,
+		srv.env.ocierr, //OCIError           *errhp,
+		file)           //OCILobLocator      *filep );
+	C.OCIDescriptorFree(unsafe.Pointer(file), //void     *descp,
+		C.OCI_DTYPE_FILE) //ub4      type );
+	if r == C.OCI_ERROR {
+		return srv.env.ociError()
+	}
+	return nil
+}
+
 var _ = io.Reader((*lobReader)(nil))
 var _ = io.WriterTo((*lobReader)(nil))
 
@@ -249,6 +308,7 @@ type lobReader struct {
 	piece         C.ub1
 	off           C.oraub8
 	interrupted   bool
+	isFile        bool // a BFILE opened with OCILobFileOpen
 	Length        C.oraub8
 }
 
@@ -262,6 +322,9 @@ func (lr *lobReader) Close() error {
 	if lr.interrupted {
 		srv.Break()
 	}
+	if lr.isFile {
+		return bfileClose(srv, lob)
+	}
 	//Log.Infof("lobReader OCILobClose %p", lr.ociLobLocator)
 	return lobClose(srv, lob)
 }
@@ -276,6 +339,12 @@ func (lr *lobReader) Read(p []byte) (n int, err error) {
 			lr.Close()
 		}
 	}()
+	if lr.Length == 0 { // an empty LOB has nothing to read
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
 
 	var byte_amtp C.oraub8 // zero
 	//Log.Infof("LobRead2 piece=%d off=%d amt=%d", lr.piece, lr.off, len(p))
@@ -325,6 +394,10 @@ func (lr *lobReader) WriteTo(w io.Writer) (n int64, err error) {
 		}
 	}()
 
+	if lr.Length == 0 { // an empty LOB has nothing to write
+		return 0, nil
+	}
+
 	var byte_amtp C.oraub8 // zero
 	arr := lobChunkPool.Get().([lobChunkSize]byte)
 	defer lobChunkPool.Put(arr)
@@ -352,7 +425,9 @@ func (lr *lobReader) WriteTo(w io.Writer) (n int64, err error) {
 		switch r {
 		case C.OCI_SUCCESS:
 		case C.OCI_NO_DATA:
-			break
+			if byte_amtp == 0 {
+				return n, nil
+			}
 		default:
 			return 0, lr.srv.env.ociError()
 		}
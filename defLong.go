@@ -0,0 +1,188 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+#include "version.h"
+
+// longBuf receives a LONG or LONG RAW value in pieces. Its memory is allocated
+// in C as OCI keeps the pointer for the lifetime of the define.
+typedef struct {
+	char  *buf;
+	size_t cap;
+	size_t len;       // length of the pieces before the current piece
+	ub4   pieceLen;   // length of the current piece
+	ub4   pieceSize;
+	sb2   ind;
+	ub2   rcode;
+	int   failed;
+} longBuf;
+
+// longBufPiece supplies the buffer of the next piece of a fetched value,
+// growing the buffer as the pieces arrive.
+static sb4 longBufPiece(void *octxp, OCIDefine *defnp, ub4 iter, void **bufpp,
+	ub4 **alenpp, ub1 *piecep, void **indpp, ub2 **rcodepp) {
+	longBuf *b = (longBuf *)octxp;
+	if (*piecep == OCI_ONE_PIECE || *piecep == OCI_FIRST_PIECE) {
+		b->len = 0;
+		b->ind = -1;
+	} else {
+		b->len += b->pieceLen;
+	}
+	if (b->cap - b->len < b->pieceSize) {
+		size_t cap = b->cap * 2;
+		char *buf;
+		if (cap < b->len + b->pieceSize) {
+			cap = b->len + b->pieceSize;
+		}
+		buf = (char *)realloc(b->buf, cap);
+		if (buf == NULL) {
+			b->failed = 1;
+			return OCI_ERROR;
+		}
+		b->buf = buf;
+		b->cap = cap;
+	}
+	b->pieceLen = b->pieceSize;
+	*bufpp = b->buf + b->len;
+	*alenpp = &b->pieceLen;
+	*indpp = &b->ind;
+	*rcodepp = &b->rcode;
+	return OCI_CONTINUE;
+}
+
+static sword longBufDefine(OCIDefine *defnp, OCIError *errhp, longBuf *b) {
+	return OCIDefineDynamic(defnp, errhp, b, longBufPiece);
+}
+
+#define longMaxSize SB4MAXVAL
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// longBuf fetches a LONG or LONG RAW select-list column piecewise, so a
+// value isn't limited to the size of a single buffer.
+type longBuf struct {
+	c *C.longBuf
+}
+
+// define defines the column at position for dynamic fetch of pieces of
+// pieceSize bytes.
+func (buf *longBuf) define(position int, dty C.ub2, pieceSize uint32, ocidef **C.OCIDefine, rset *Rset) error {
+	if buf.c == nil {
+		buf.c = (*C.longBuf)(C.calloc(1, C.sizeof_longBuf))
+		if buf.c == nil {
+			return er("Unable to allocate LONG buffer.")
+		}
+	}
+	if pieceSize < 1 {
+		pieceSize = 1
+	}
+	buf.c.pieceSize = C.ub4(pieceSize)
+	buf.c.ind = -1
+	r := C.OCIDEFINEBYPOS(
+		rset.ocistmt,                 //OCIStmt     *stmtp,
+		ocidef,                       //OCIDefine   **defnpp,
+		rset.stmt.ses.srv.env.ocierr, //OCIError    *errhp,
+		C.ub4(position),              //ub4         position,
+		nil,                          //void        *valuep,
+		C.LENGTH_TYPE(C.longMaxSize), //sb8         value_sz,
+		dty,                          //ub2         dty,
+		nil,                          //void        *indp,
+		nil,                          //ub2         *rlenp,
+		nil,                          //ub2         *rcodep,
+		C.OCI_DYNAMIC_FETCH)          //ub4         mode );
+	if r == C.OCI_ERROR {
+		return rset.stmt.ses.srv.env.ociError()
+	}
+	r = C.longBufDefine(*ocidef, rset.stmt.ses.srv.env.ocierr, buf.c)
+	if r == C.OCI_ERROR {
+		return rset.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+// isNull returns true when the fetched value is NULL.
+func (buf *longBuf) isNull() bool {
+	return buf.c.ind < 0
+}
+
+// bytes returns a copy of the fetched value.
+func (buf *longBuf) bytes() ([]byte, error) {
+	if buf.c.failed != 0 {
+		buf.c.failed = 0
+		return nil, er("Unable to allocate LONG buffer.")
+	}
+	length := int(buf.c.len) + int(buf.c.pieceLen)
+	if length == 0 {
+		return []byte{}, nil
+	}
+	return C.GoBytes(unsafe.Pointer(buf.c.buf), C.int(length)), nil
+}
+
+// free frees the C memory of the longBuf.
+func (buf *longBuf) free() {
+	if buf.c != nil {
+		C.free(unsafe.Pointer(buf.c.buf))
+		C.free(unsafe.Pointer(buf.c))
+		buf.c = nil
+	}
+}
+
+type defLong struct {
+	rset       *Rset
+	ocidef     *C.OCIDefine
+	isNullable bool
+	buf        longBuf
+}
+
+func (def *defLong) define(position int, pieceSize uint32, isNullable bool, rset *Rset) error {
+	def.rset = rset
+	def.isNullable = isNullable
+	return def.buf.define(position, C.SQLT_LNG, pieceSize, &def.ocidef, rset)
+}
+
+func (def *defLong) value() (value interface{}, err error) {
+	if def.buf.isNull() {
+		if def.isNullable {
+			return String{IsNull: true}, nil
+		}
+		return nil, nil
+	}
+	b, err := def.buf.bytes()
+	if err != nil {
+		return nil, err
+	}
+	if def.isNullable {
+		return String{Value: string(b)}, nil
+	}
+	return string(b), nil
+}
+
+func (def *defLong) alloc() error {
+	return nil
+}
+
+func (def *defLong) free() {
+}
+
+func (def *defLong) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	def.buf.free()
+	rset.putDef(defIdxLong, def)
+	return nil
+}
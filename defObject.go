@@ -0,0 +1,108 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"reflect"
+	"unsafe"
+)
+
+type defObject struct {
+	rset       *Rset
+	ocidef     *C.OCIDefine
+	typ        *objType
+	structType reflect.Type
+	instance   unsafe.Pointer
+	null       unsafe.Pointer // null structure of the instance
+}
+
+func (def *defObject) define(position int, typ *objType, structType reflect.Type, rset *Rset) error {
+	def.rset = rset
+	def.typ = typ
+	def.structType = structType
+	r := C.OCIDEFINEBYPOS(
+		def.rset.ocistmt,                 //OCIStmt     *stmtp,
+		&def.ocidef,                      //OCIDefine   **defnpp,
+		def.rset.stmt.ses.srv.env.ocierr, //OCIError    *errhp,
+		C.ub4(position),                  //ub4         position,
+		nil,                              //void        *valuep,
+		0,                                //sb8         value_sz,
+		C.SQLT_NTY,                       //ub2         dty,
+		nil,                              //void        *indp,
+		nil,                              //ub2         *rlenp,
+		nil,                              //ub2         *rcodep,
+		C.OCI_DEFAULT)                    //ub4         mode );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	r = C.OCIDefineObject(
+		def.ocidef,                       //OCIDefine       *defnp,
+		def.rset.stmt.ses.srv.env.ocierr, //OCIError        *errhp,
+		typ.tdo,                          //const OCIType   *type,
+		&def.instance,                    //void            **pgvpp,
+		nil,                              //ub4             *pvszsp,
+		&def.null,                        //void            **indpp,
+		nil)                              //ub4             *indszp );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+// value returns a pointer to a new struct of the type mapped by AddObj, or a
+// nil pointer for a NULL object.
+func (def *defObject) value() (value interface{}, err error) {
+	if def.instance == nil || def.null == nil || *(*C.OCIInd)(def.null) == C.OCI_IND_NULL {
+		return reflect.Zero(reflect.PtrTo(def.structType)).Interface(), nil
+	}
+	rv := reflect.New(def.structType)
+	err = def.typ.getStruct(def.rset.stmt.ses, def.instance, def.null, rv.Elem())
+	if err != nil {
+		return nil, err
+	}
+	return rv.Interface(), nil
+}
+
+func (def *defObject) alloc() error {
+	return nil
+}
+
+func (def *defObject) free() {
+	defer func() {
+		recover()
+	}()
+	if def.instance != nil {
+		C.OCIObjectFree(
+			def.rset.stmt.ses.srv.env.ocienv, //OCIEnv      *env,
+			def.rset.stmt.ses.srv.env.ocierr, //OCIError    *err,
+			def.instance,                     //void        *instance,
+			C.OCI_OBJECTFREE_FORCE)           //ub2         flags );
+		def.instance = nil
+		def.null = nil
+	}
+}
+
+func (def *defObject) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	def.typ = nil
+	def.structType = nil
+	def.instance = nil
+	def.null = nil
+	rset.putDef(defIdxObject, def)
+	return nil
+}
@@ -17,14 +17,18 @@ import (
 type defRowid struct {
 	rset   *Rset
 	ocidef *C.OCIDefine
+	gct    GoColumnType
+	null   C.sb2
 	buf    []byte
 }
 
-func (def *defRowid) define(position int, rset *Rset) error {
+func (def *defRowid) define(position int, gct GoColumnType, rset *Rset) error {
 	def.rset = rset
+	def.gct = gct
 	// using a character host variable of width between 19
 	// (18 bytes plus the null-terminator) and 4001 as the
-	// host bind variable for universal ROWID.
+	// host bind variable for universal ROWID. The rowid is
+	// defined as text rather than as an OCIRowid descriptor.
 	if len(def.buf) < 4001 {
 		def.buf = make([]byte, 4001)
 	}
@@ -36,7 +40,7 @@ func (def *defRowid) define(position int, rset *Rset) error {
 		unsafe.Pointer(&def.buf[0]),      //void        *valuep,
 		C.LENGTH_TYPE(len(def.buf)),      //sb8         value_sz,
 		C.SQLT_STR,                       //ub2         dty,
-		nil,                              //void        *indp,
+		unsafe.Pointer(&def.null),        //void        *indp,
 		nil,                              //ub2         *rlenp,
 		nil,                              //ub2         *rcodep,
 		C.OCI_DEFAULT)                    //ub4         mode );
@@ -47,10 +51,19 @@ func (def *defRowid) define(position int, rset *Rset) error {
 }
 
 func (def *defRowid) value() (value interface{}, err error) {
+	if def.null < 0 {
+		if def.gct == OraRowid {
+			return Rowid{IsNull: true}, nil
+		}
+		return nil, nil
+	}
 	n := bytes.Index(def.buf, []byte{0})
 	if n == -1 {
 		n = len(def.buf)
 	}
+	if def.gct == OraRowid {
+		return Rowid{Value: string(def.buf[:n])}, nil
+	}
 	value = string(def.buf[:n])
 	return value, err
 }
@@ -72,6 +85,8 @@ func (def *defRowid) close() (err error) {
 	rset := def.rset
 	def.rset = nil
 	def.ocidef = nil
+	def.gct = D
+	def.null = 0
 	clear(def.buf, 32)
 	rset.putDef(defIdxRowid, def)
 	return nil
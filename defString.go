@@ -17,11 +17,22 @@ type defString struct {
 	null       C.sb2
 	isNullable bool
 	buf        []byte
+	position   int
+	width      int
+	bufs       []byte // buffers of an array fetch
+	nulls      []C.sb2
+	rlens      []C.ACTUAL_LENGTH_TYPE
+	isArray    bool
 }
 
+// timeTextSize is the size of the define of a DATE or TIMESTAMP column fetched
+// as text, enough for a long NLS format with day and month names.
+const timeTextSize = 128
+
 func (def *defString) define(position int, columnSize int, isNullable bool, rset *Rset) error {
 	def.rset = rset
 	def.isNullable = isNullable
+	def.position = position
 	//Log.Infof("defString position=%d columnSize=%d", position, columnSize)
 	n := columnSize
 	// AL32UTF8: one db "char" can be 4 bytes on wire, esp. if the database's
@@ -39,6 +50,7 @@ func (def *defString) define(position int, columnSize int, isNullable bool, rset
 	if n%2 != 0 {
 		n++
 	}
+	def.width = n
 	if def.buf == nil || cap(def.buf) < n {
 		def.buf = make([]byte, n)
 	}
@@ -70,15 +82,50 @@ func (def *defString) value() (value interface{}, err error) {
 		}
 		value = oraStringValue
 	} else {
-		if def.null < C.sb2(0) {
-			value = ""
-		} else {
+		if def.null > C.sb2(-1) {
 			value = stringTrimmed(def.buf, 32)
 		}
 	}
 	return value, err
 }
 
+// defineArray redefines the column to fetch rows values per fetch.
+func (def *defString) defineArray(rows int) error {
+	if cap(def.bufs) < rows*def.width {
+		def.bufs = make([]byte, rows*def.width)
+	}
+	def.bufs = def.bufs[:rows*def.width]
+	if cap(def.nulls) < rows {
+		def.nulls = make([]C.sb2, rows)
+		def.rlens = make([]C.ACTUAL_LENGTH_TYPE, rows)
+	}
+	def.nulls, def.rlens = def.nulls[:rows], def.rlens[:rows]
+	r := C.OCIDEFINEBYPOS(
+		def.rset.ocistmt,                 //OCIStmt     *stmtp,
+		&def.ocidef,                      //OCIDefine   **defnpp,
+		def.rset.stmt.ses.srv.env.ocierr, //OCIError    *errhp,
+		C.ub4(def.position),              //ub4         position,
+		unsafe.Pointer(&def.bufs[0]),     //void        *valuep,
+		C.LENGTH_TYPE(def.width),         //sb8         value_sz,
+		C.SQLT_CHR,                       //ub2         dty,
+		unsafe.Pointer(&def.nulls[0]),    //void        *indp,
+		&def.rlens[0],                    //ub2         *rlenp,
+		nil,                              //ub2         *rcodep,
+		C.OCI_DEFAULT)                    //ub4         mode );
+	if r == C.OCI_ERROR {
+		return def.rset.stmt.ses.srv.env.ociError()
+	}
+	def.isArray = true
+	return nil
+}
+
+// setRow selects the row of an array fetch returned by value.
+func (def *defString) setRow(row int) {
+	def.null = def.nulls[row]
+	offset := row * def.width
+	def.buf = def.bufs[offset : offset+int(def.rlens[row])]
+}
+
 func (def *defString) alloc() error {
 	return nil
 }
@@ -97,7 +144,12 @@ func (def *defString) close() (err error) {
 	rset := def.rset
 	def.rset = nil
 	def.ocidef = nil
-	clear(def.buf, 32)
+	if def.isArray { // buf is a row of bufs
+		def.buf = nil
+		def.isArray = false
+	} else {
+		clear(def.buf, 32)
+	}
 	rset.putDef(defIdxString, def)
 	return nil
 }
@@ -53,7 +53,7 @@ func (def *defTime) value() (value interface{}, err error) {
 			oraTimeValue.Value, err = getTime(def.rset.stmt.ses.srv.env, def.ociDateTime)
 		}
 		value = oraTimeValue
-	} else {
+	} else if def.null > C.sb2(-1) {
 		value, err = getTime(def.rset.stmt.ses.srv.env, def.ociDateTime)
 	}
 	return value, err
@@ -161,7 +161,8 @@ func getTime(env *Env, ociDateTime *C.OCIDateTime) (result time.Time, err error)
 				}
 				seconds := math.Abs(float64(offsetHour)) * 60 * 60
 				seconds += math.Abs(float64(offsetMinute)) * 60
-				if offsetHour < 0 {
+				// a negative offset under an hour, such as -00:30, has a zero hour
+				if offsetHour < 0 || offsetMinute < 0 {
 					seconds *= -1
 				}
 				location = time.FixedZone(locName, int(seconds))
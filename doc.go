@@ -72,6 +72,13 @@ Placeholders within a SQL statement are bound by position. The actual name is no
 used by the ora package driver e.g., placeholder names :c1, :1, or :xyz are
 treated equally.
 
+Wrap parameters in ora.Named to bind them by name instead. A named
+placeholder appearing more than once is bound once:
+
+	stmt.Qry(ora.Named{Name: "x", Value: int64(2)}) // WHERE A = :x OR B = :x
+
+When any parameter is an ora.Named, all of the statement's parameters must be.
+
 Working With The Sql Package
 
 You may access an Oracle database through the database/sql package. The database/sql
@@ -83,6 +90,12 @@ The ora package implements interfaces in the database/sql/driver package enablin
 database/sql to communicate with an Oracle database. Using database/sql
 ensures you never have to call the ora package directly.
 
+Open a *sql.DB with sql.OpenDB and an ora.Connector so that the context of
+context-aware calls such as DB.PingContext also abandons a connection attempt,
+returning promptly rather than waiting on a slow name resolution:
+
+	db := sql.OpenDB(ora.NewConnector("user/passw@host:port/sid"))
+
 When using database/sql, the mapping between Go types and Oracle types may be
 changed slightly. The database/sql package has strict expectations on Go return
 types. The Go-to-Oracle type mapping for database/sql is:
@@ -448,6 +461,16 @@ A string pointer captures an out parameter from a stored procedure:
 	stmt, err = ses.Prep("CALL PROC1(:1)")
 	stmt.Exe(&str)
 
+An ora.Out wraps a pointer to also send its value for an IN OUT parameter, and
+optionally reports a returned NULL:
+
+	// given:
+	// CREATE OR REPLACE PROCEDURE PROC1 (P1 IN OUT NUMBER) AS BEGIN P1 := P1 + 1; END PROC1;
+	var num int64 = 41
+	var isNull bool
+	stmt, err = ses.Prep("CALL PROC1(:1)")
+	stmt.Exe(ora.Out{Value: &num, IsNull: &isNull})
+
 Slices may be used to insert multiple records with a single insert statement:
 
 	// insert one million rows with single insert statement
@@ -458,6 +481,22 @@ Slices may be used to insert multiple records with a single insert statement:
 	}
 	rowsAffected, err := ses.PrepAndExe("INSERT INTO T1 (C1) VALUES (:C1)", values)
 
+A *[]ora.Rowid receives the rowid of each row of an array insert, update or
+delete, aligned with the bound slices; each iteration must affect one row. A
+row which fails under StmtCfg.IsBatchErrors has a null Rowid:
+
+	var rowids []ora.Rowid
+	rowsAffected, err = ses.PrepAndExe("INSERT INTO T1 (C1) VALUES (:C1) RETURNING ROWID INTO :R", values, &rowids)
+
+A ROWID column is fetched as a string by default, or as an ora.Rowid with the
+OraRowid GoColumnType. An ora.Rowid holds the textual rowid, and is bound as text
+which the server converts. A Rowid bound in a WHERE clause identifies its row:
+
+	stmt, err := ses.Prep("SELECT ROWID, C1 FROM T1", ora.OraRowid, ora.I64)
+	...
+	rowid := rset.Row[0].(ora.Rowid)
+	rowsAffected, err = ses.PrepAndExe("UPDATE T1 SET C1 = 0 WHERE ROWID = :1", rowid)
+
 The ora package provides nullable Go types to support DML operations such as
 insert and select. The nullable Go types provided by the ora package are Int64,
 Int32, Int16, Int8, Uint64, Uint32, Uint16, Uint8, Float64, Float32, Time,
@@ -513,8 +552,9 @@ float32. For example, you may insert a uint16 and select numerics of various siz
 	rset, err := stmt.Qry()
 	row := rset.NextRow()
 
-If a non-nullable type is defined for a nullable column returning null, the Go
-type's zero value is returned.
+If a non-nullable type is defined for a nullable column returning null, the
+Row holds nil rather than the Go type's zero value; a NULL NUMBER isn't 0 and a
+NULL VARCHAR2 isn't "". A nullable type reports the null with its IsNull field.
 
 GoColumnTypes defined by the ora package are:
 
@@ -643,6 +683,22 @@ nullable Go types by default:
 	// any new SesCfg.StmtCfg, StmtCfg.Cfg will receive this StmtCfg
 	// any new Rset will receive the StmtCfg.Rset configuration
 
+DATE and TIMESTAMP columns may also be fetched as text formatted by the
+session's NLS_DATE_FORMAT and NLS_TIMESTAMP*_FORMAT parameters with S or OraS:
+
+	stmt.Cfg().Rset.SetDate(ora.S)
+	stmt.Cfg().Rset.SetTimestamp(ora.OraS)
+
+StmtCfg.CallTimeout limits each round trip of a statement with Oracle 18c or
+later client libraries; a call exceeding it returns an *ora.Error whose Timeout
+method returns true:
+
+	stmt.Cfg().CallTimeout = 5 * time.Second
+	_, err = stmt.Exe()
+	if oe, ok := err.(*ora.Error); ok && oe.Timeout() {
+		fmt.Println("statement timed out")
+	}
+
 Another scenario may be to configure the runes mapped to bool values:
 
 	// update StmtCfg to change the FalseRune and TrueRune inserted into the database
@@ -675,8 +731,8 @@ ora.IntervalDS, ora.Raw, ora.Lob and ora.Bfile. ora.Rset represents an Oracle
 SYS_REFCURSOR. ora.IntervalYM represents an Oracle INTERVAL YEAR TO MONTH.
 ora.IntervalDS represents an Oracle INTERVAL DAY TO SECOND. ora.Raw represents
 an Oracle RAW or LONG RAW. ora.Lob may represent an Oracle BLOB or Oracle CLOB.
-And ora.Bfile represents an Oracle BFILE. ROWID columns are returned as strings and
-don't have a unique Go type.
+And ora.Bfile represents an Oracle BFILE. ROWID columns are returned as strings, or
+as ora.Rowid with the OraRowid GoColumnType.
 
 Rset is used to obtain Go values from a SQL select statement. Methods Rset.Next,
 Rset.NextRow, and Rset.Len are available. Fields Rset.Row, Rset.Err,
@@ -686,6 +742,9 @@ or if an error occurs, Next returns false setting Row to nil. Any error in Next
 Calling Next increments Index and method Len returns the total number of rows processed. The NextRow
 method is convenient for returning a single row. NextRow calls Next and returns Row.
 ColumnNames returns the names of columns defined by the SQL select statement.
+Method Rset.Columns describes each column with its name, Oracle type name, size,
+precision, scale and nullability. The same metadata backs database/sql's
+Rows.ColumnTypes, whose ScanType is the Go type of the column's values.
 
 Rset has two usages. Rset may be returned from Stmt.Qry when prepared with a SQL select
 statement:
@@ -741,11 +800,54 @@ multiple Rsets:
 The types of values assigned to Row may be configured in StmtCfg.Rset. For configuration
 to take effect, assign StmtCfg.Rset prior to calling Stmt.Qry or Stmt.Exe.
 
+The ORA_ROWSCN pseudo-column is returned as a uint64 system change number (SCN)
+unless another GoColumnType is specified. Because the SCN changes whenever a row
+changes, it supports optimistic concurrency without a version column:
+
+	// given: CREATE TABLE T1 (C1 NUMBER, C2 VARCHAR2(48 CHAR)) ROWDEPENDENCIES
+	rset, err := ses.PrepAndQry("SELECT C2, ORA_ROWSCN FROM T1 WHERE C1 = 1")
+	rset.Next()
+	scn := rset.Row[1].(uint64)
+	// update only if the row hasn't changed since it was read
+	rowsAffected, err := ses.PrepAndExe("UPDATE T1 SET C2 = :1 WHERE C1 = 1 AND ORA_ROWSCN = :2", "GO", scn)
+
+By default Oracle tracks ORA_ROWSCN per block, so a change to any row in a block
+changes the SCN of every row in the block. Create the table with ROWDEPENDENCIES
+for row-level granularity; ROWDEPENDENCIES can't be added to an existing table.
+An alias of ORA_ROWSCN is returned as a NUMBER column; specify U64 in that case.
+
 Rset prefetching may be controlled by StmtCfg.PrefetchRowCount and
 StmtCfg.PrefetchMemorySize. PrefetchRowCount works in coordination with
 PrefetchMemorySize. When PrefetchRowCount is set to zero only PrefetchMemorySize is used;
 otherwise, the minimum of PrefetchRowCount and PrefetchMemorySize is used.
-The default uses a PrefetchMemorySize of 134MB.
+The default uses a PrefetchRowCount of 100 and a PrefetchMemorySize of 134MB.
+
+Prefetched rows are still copied to the Rset one row per OCI call. Set
+StmtCfg.FetchArraySize to copy a batch of rows per call when scanning large
+result sets. The batch is used only when every select-list column is a number
+or a string:
+
+	stmt.Cfg().SetFetchArraySize(1000)
+
+Queries of rarely changing tables may use Oracle's result cache. Set
+SesCfg.IsResultCached to add a result_cache optimizer hint to each SELECT
+prepared by the session, or hint a single query with ora.ResultCacheHint:
+
+	stmt, err := ses.Prep(ora.ResultCacheHint("SELECT CODE, NAME FROM COUNTRIES"))
+
+Set StmtCfg.IsScrollable to query with a scrollable cursor, which moves to any
+row without querying again:
+
+	stmt.Cfg().IsScrollable = true
+	rset, err := stmt.Qry()
+	if rset.Seek(49) { // the 50th row
+		fmt.Println(rset.Index, rset.Row)
+	}
+	if rset.Last() {
+		fmt.Println("rows:", rset.Len())
+	}
+
+Rset.First, Rset.Prev and Rset.Next move relative to the current row.
 
 Opening and closing Rsets is managed internally. Rset does not have an Open
 method or Close method.
@@ -934,6 +1036,55 @@ to Version requires an open Ses:
 		fmt.Println("Received version from server")
 	}
 
+The ClientVersion function returns the version of the Oracle client library the
+driver is linked against, and requires no connection.
+
+Srv.RegisterFailover registers a FailoverFunc called with the FailoverType and
+FailoverEvent of each step of a Transparent Application Failover. Returning true
+for a FailoverError event requests another failover attempt.
+
+Oracle Advanced Queuing queues with a RAW payload type are available with
+Ses.OpenQueue. Queue.Enqueue and Queue.Dequeue are part of the session's
+transaction unless VisibilityImmediate is specified:
+
+	queue, err := ses.OpenQueue("ORDERS_Q")
+	err = queue.Enqueue([]byte("order 1"), ora.EnqOptions{})
+	err = ses.PrepAndExe("COMMIT")
+	payload, ok, err := queue.Dequeue(ora.DeqOptions{Wait: 10 * time.Second})
+
+Oracle object types are mapped to Go structs with AddObj. Struct fields
+correspond to object attributes of the same name, or of the name in a "db"
+field tag. A select-list column of a mapped object type is returned as a
+pointer to the struct, and a struct of a mapped type may be bound as a
+parameter:
+
+	type Point struct {
+		X, Y  int64
+		Label *string `db:"NAME"` // nil for a NULL attribute
+	}
+	err := ora.AddObj(Point{}, "SCOTT.POINT")
+	stmt, err := ses.Prep("INSERT INTO SHAPES (ID, ORIGIN) VALUES (:1, :2)")
+	rowsAffected, err := stmt.Exe(int64(1), Point{X: 3, Y: 4})
+	rset, err := ses.PrepAndQry("SELECT ORIGIN FROM SHAPES")
+	for rset.Next() {
+		point := rset.Row[0].(*Point) // nil for a NULL object
+	}
+
+VARRAY and nested table values of NUMBER, VARCHAR2 and CHAR elements are
+bound and fetched as ora.Coll, which wraps a slice and names the collection
+type:
+
+	stmt, err := ses.Prep("BEGIN ADD_ORDERS(:1); END;")
+	rowsAffected, err := stmt.Exe(ora.Coll{TypeName: "ID_LIST", Value: []int64{1, 2, 3}})
+
+A slice passed to Stmt.Exe is bound as an array of rows. Wrap a []int64 or
+[]string with ora.PlsArr to bind a PL/SQL associative array instead, and pass
+a pointer to the slice for an OUT or IN OUT parameter:
+
+	stmt, err := ses.Prep("BEGIN PKG.DOUBLE(:1); END;")
+	values := []int64{1, 2, 3}
+	rowsAffected, err := stmt.Exe(ora.PlsArr{Value: &values, Cap: 100})
+
 Further code examples are available in the example file, test files and samples
 folder.
 
@@ -949,6 +1100,11 @@ ora driver methods. For example:
 	// enable logging of the Rset.Next method
 	ora.Cfg().Log.Rset.Next = true
 
+Slow statements may be logged at warning level by setting a threshold:
+
+	// warn when an Exe or Qry takes longer than a second
+	ora.Cfg().SlowQueryThreshold = time.Second
+
 To use the standard Go log package:
 
 	import (
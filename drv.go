@@ -11,6 +11,7 @@ package ora
 import "C"
 import (
 	"container/list"
+	"context"
 	"database/sql/driver"
 	"sync"
 	"time"
@@ -20,6 +21,15 @@ import (
 type DrvCfg struct {
 	Env *EnvCfg
 	Log LogDrvCfg
+
+	// SlowQueryThreshold determines the duration after which an Exe or Qry
+	// call is considered slow. A slow call is logged at warning level with the
+	// SQL, the number of binds and the elapsed time; see WarnLogger.
+	//
+	// The elapsed time is measured on the client around statement execution.
+	//
+	// The default is zero, which disables slow query logging.
+	SlowQueryThreshold time.Duration
 }
 
 // NewDrvCfg creates a DrvCfg with default values.
@@ -35,7 +45,7 @@ type LogDrvCfg struct {
 	// Logger writes log messages.
 	// Logger can be replaced with any type implementing the Logger interface.
 	//
-	// The default implementation uses the standard lib's log package.
+	// The default is EmpLgr, which discards messages.
 	//
 	// For a glog-based implementation, see gopkg.in/rana/ora.v2/glg.
 	// LogDrvCfg.Logger = glg.Log
@@ -74,6 +84,11 @@ type LogDrvCfg struct {
 	// The default is true.
 	AddTbl bool
 
+	// AddObj determines whether the ora.AddObj method is logged.
+	//
+	// The default is true.
+	AddObj bool
+
 	Env  LogEnvCfg
 	Srv  LogSrvCfg
 	Ses  LogSesCfg
@@ -81,6 +96,7 @@ type LogDrvCfg struct {
 	Tx   LogTxCfg
 	Con  LogConCfg
 	Rset LogRsetCfg
+	Pool LogPoolCfg
 }
 
 // NewLogDrvCfg creates a LogDrvCfg with default values.
@@ -93,6 +109,7 @@ func NewLogDrvCfg() LogDrvCfg {
 	c.Del = true
 	c.Sel = true
 	c.AddTbl = true
+	c.AddObj = true
 	c.Env = NewLogEnvCfg()
 	c.Srv = NewLogSrvCfg()
 	c.Ses = NewLogSesCfg()
@@ -100,6 +117,7 @@ func NewLogDrvCfg() LogDrvCfg {
 	c.Tx = NewLogTxCfg()
 	c.Con = NewLogConCfg()
 	c.Rset = NewLogRsetCfg()
+	c.Pool = NewLogPoolCfg()
 	return c
 }
 
@@ -114,6 +132,7 @@ type Drv struct {
 
 	envId  Id
 	srvId  Id
+	poolId Id
 	conId  Id
 	sesId  Id
 	txId   Id
@@ -151,3 +170,79 @@ func (drv *Drv) Open(conStr string) (driver.Conn, error) {
 	}
 	return con, nil
 }
+
+// OpenConnector returns a Connector opening connections with conStr in the
+// database/sql environment.
+//
+// This is intended to be called by the database/sql package only.
+//
+// OpenConnector is a member of the driver.DriverContext interface.
+func (drv *Drv) OpenConnector(conStr string) (driver.Connector, error) {
+	log(true)
+	return NewConnector(conStr), nil
+}
+
+// Connector opens connections to an Oracle server for the database/sql
+// package, abandoning a connection attempt when its context is done.
+//
+// Use a Connector with sql.OpenDB:
+//
+//	db := sql.OpenDB(ora.NewConnector("user/passw@host:port/sid"))
+//
+// Connector implements the driver.Connector interface.
+type Connector struct {
+	conStr string
+}
+
+// NewConnector returns a Connector opening connections with conStr, which has
+// the form of Env.OpenCon, in the database/sql environment.
+func NewConnector(conStr string) *Connector {
+	return &Connector{conStr: conStr}
+}
+
+// Connect opens a connection to an Oracle server, returning ctx.Err() when
+// ctx is done before the connection opens.
+//
+// The server attach and session begin run on a separate goroutine, so that a
+// done ctx returns promptly during a slow name resolution or network connect.
+// Cancellation only abandons the attempt: the attach isn't interrupted, and
+// the goroutine stays blocked in it until the attach completes or fails by its
+// own network timeouts. A connection opened regardless is closed once it opens.
+//
+// Connect is a member of the driver.Connector interface.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	log(true)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		con *Con
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		con, err := _drv.sqlPkgEnv.OpenCon(c.conStr)
+		done <- result{con: con, err: err}
+	}()
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, errE(res.err)
+		}
+		return res.con, nil
+	case <-ctx.Done():
+		go func() { // wait for the abandoned attach; clean up a connection opened after cancellation
+			if res := <-done; res.con != nil {
+				res.con.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// Driver returns the ora database driver.
+//
+// Driver is a member of the driver.Connector interface.
+func (c *Connector) Driver() driver.Driver {
+	return _drv
+}
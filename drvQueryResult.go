@@ -6,6 +6,7 @@ package ora
 
 import (
 	"database/sql/driver"
+	"reflect"
 )
 
 // DrvQueryResult contains methods to retrieve the results of a SQL select statement.
@@ -13,6 +14,7 @@ import (
 // DrvQueryResult implements the driver.Rows interface.
 type DrvQueryResult struct {
 	rset *Rset
+	con  *Con
 }
 
 // Next populates the specified slice with the next row of data.
@@ -24,11 +26,11 @@ func (qr *DrvQueryResult) Next(dest []driver.Value) (err error) {
 	err = qr.rset.beginRow()
 	defer qr.rset.endRow()
 	if err != nil {
-		return err
+		return qr.con.markBroken(err)
 	}
 	// Populate column values into destination slice
 	for n, define := range qr.rset.defs {
-		value, err := define.value()
+		value, err := qr.rset.columnValue(n, define)
 		if err != nil {
 			return err
 		}
@@ -50,3 +52,39 @@ func (qr *DrvQueryResult) Columns() []string {
 func (qr *DrvQueryResult) Close() error {
 	return nil
 }
+
+// ColumnTypeDatabaseTypeName returns the Oracle type name of a column, such as
+// NUMBER or VARCHAR2.
+//
+// ColumnTypeDatabaseTypeName is a member of the
+// driver.RowsColumnTypeDatabaseTypeName interface.
+func (qr *DrvQueryResult) ColumnTypeDatabaseTypeName(index int) string {
+	return qr.rset.columns[index].oraTypeName()
+}
+
+// ColumnTypeScanType returns the Go type of the values which Next returns for
+// a column.
+//
+// ColumnTypeScanType is a member of the driver.RowsColumnTypeScanType
+// interface.
+func (qr *DrvQueryResult) ColumnTypeScanType(index int) reflect.Type {
+	return qr.rset.scanType(index)
+}
+
+// ColumnTypeNullable reports whether a column may hold NULLs.
+//
+// ColumnTypeNullable is a member of the driver.RowsColumnTypeNullable
+// interface.
+func (qr *DrvQueryResult) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return qr.rset.columns[index].isNullable, true
+}
+
+// ColumnTypePrecisionScale returns the precision and scale of a NUMBER(p,s)
+// column. ok is false for other columns, including a NUMBER without a
+// precision and a FLOAT.
+//
+// ColumnTypePrecisionScale is a member of the
+// driver.RowsColumnTypePrecisionScale interface.
+func (qr *DrvQueryResult) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	return qr.rset.columns[index].precisionScale()
+}
@@ -16,6 +16,7 @@ import (
 // DrvStmt implements the driver.Stmt interface.
 type DrvStmt struct {
 	stmt *Stmt
+	con  *Con
 }
 
 // checkIsOpen validates that the server is open.
@@ -54,6 +55,10 @@ func (ds *DrvStmt) NumInput() int {
 // Exec executes an Oracle SQL statement on a server. Exec returns a driver.Result
 // and a possible error.
 //
+// An Oracle error of a lost connection, such as ORA-03113, is returned as is
+// and marks the connection as broken, so that database/sql discards it
+// without executing the statement a second time.
+//
 // Exec is a member of the driver.Stmt interface.
 func (ds *DrvStmt) Exec(values []driver.Value) (result driver.Result, err error) {
 	ds.log(true)
@@ -66,7 +71,7 @@ func (ds *DrvStmt) Exec(values []driver.Value) (result driver.Result, err error)
 	}
 	rowsAffected, lastInsertId, err := ds.stmt.exe(params)
 	if err != nil {
-		return nil, errE(err)
+		return nil, ds.con.markBroken(errE(err))
 	}
 	if rowsAffected == 0 {
 		result = driver.ResultNoRows
@@ -79,6 +84,8 @@ func (ds *DrvStmt) Exec(values []driver.Value) (result driver.Result, err error)
 // Query runs a SQL query on an Oracle server. Query returns driver.Rows and a
 // possible error.
 //
+// An Oracle error of a lost connection is handled as with Exec.
+//
 // Query is a member of the driver.Stmt interface.
 func (ds *DrvStmt) Query(values []driver.Value) (driver.Rows, error) {
 	ds.log(true)
@@ -91,9 +98,28 @@ func (ds *DrvStmt) Query(values []driver.Value) (driver.Rows, error) {
 	}
 	rset, err := ds.stmt.qry(params)
 	if err != nil {
-		return nil, errE(err)
+		return nil, ds.con.markBroken(errE(err))
+	}
+	return &DrvQueryResult{rset: rset, con: ds.con}, nil
+}
+
+// CheckNamedValue passes ora.Number and ora.Decimal parameters to the driver
+// unchanged, so that they're bound as an OCINumber rather than converted to a
+// string by driver.Valuer. Other values get the default conversion.
+//
+// CheckNamedValue is a member of the driver.NamedValueChecker interface.
+func (ds *DrvStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	return checkNamedValue(nv)
+}
+
+// checkNamedValue accepts the ora types bound as an OCINumber, and returns
+// driver.ErrSkip for any other value.
+func checkNamedValue(nv *driver.NamedValue) error {
+	switch nv.Value.(type) {
+	case Number, Decimal:
+		return nil
 	}
-	return &DrvQueryResult{rset: rset}, nil
+	return driver.ErrSkip
 }
 
 // sysName returns a string representing the DrvStmt.
@@ -7,6 +7,12 @@ package ora
 /*
 #include <oci.h>
 #include <stdlib.h>
+
+// OCI_ATTR_SPOOL_WAIT_TIMEOUT is available from 12.2 client libraries; older
+// clients reject the attribute at run time.
+#ifndef OCI_ATTR_SPOOL_WAIT_TIMEOUT
+#define OCI_ATTR_SPOOL_WAIT_TIMEOUT 506
+#endif
 */
 import "C"
 import (
@@ -14,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -21,6 +28,20 @@ import (
 type EnvCfg struct {
 	// StmtCfg configures new Stmts.
 	StmtCfg *StmtCfg
+
+	// Charset and NCharset name the client character set and the client
+	// national character set of NCHAR, NVARCHAR2 and NCLOB data. The names are
+	// converted to the character set ids passed to OCIEnvNlsCreate; the NLS_LANG
+	// and NLS_NCHAR environment variables are not consulted.
+	//
+	// Go strings are UTF-8, so only AL32UTF8 and UTF8 are accepted; OpenEnv
+	// returns an error for any other name. The database converts between its
+	// character sets and the client's, so AL32UTF8 exchanges Go strings
+	// unchanged with a database of any character set able to hold them.
+	//
+	// The default is empty, which uses AL32UTF8.
+	Charset  string
+	NCharset string
 }
 
 // NewEnvCfg creates a EnvCfg with default values.
@@ -46,6 +67,16 @@ type LogEnvCfg struct {
 	//
 	// The default is true.
 	OpenCon bool
+
+	// TrimIdle determines whether the Env.TrimIdle method is logged.
+	//
+	// The default is true.
+	TrimIdle bool
+
+	// OpenPool determines whether the Env.OpenPool method is logged.
+	//
+	// The default is true.
+	OpenPool bool
 }
 
 // NewLogEnvCfg creates a LogEnvCfg with default values.
@@ -54,6 +85,8 @@ func NewLogEnvCfg() LogEnvCfg {
 	c.Close = true
 	c.OpenSrv = true
 	c.OpenCon = true
+	c.TrimIdle = true
+	c.OpenPool = true
 	return c
 }
 
@@ -66,9 +99,13 @@ type Env struct {
 	ocierr *C.OCIError
 	errBuf [512]C.char
 
-	openSrvs *list.List
-	openCons *list.List
-	elem     *list.Element
+	attrMu           sync.Mutex
+	unsupportedAttrs map[C.ub4]bool // attributes the Oracle client rejects
+
+	openSrvs  *list.List
+	openCons  *list.List
+	openPools *list.List
+	elem      *list.Element
 }
 
 // Close disconnects from servers and resets optional fields.
@@ -91,6 +128,7 @@ func (env *Env) Close() (err error) {
 		env.elem = nil
 		env.openSrvs.Init()
 		env.openCons.Init()
+		env.openPools.Init()
 		_drv.envPool.Put(env)
 
 		multiErr := newMultiErrL(errs)
@@ -106,6 +144,16 @@ func (env *Env) Close() (err error) {
 			errs.PushBack(errE(err))
 		}
 	}
+	pools := make([]*Pool, 0, env.openPools.Len())
+	for e := env.openPools.Front(); e != nil; e = e.Next() {
+		pools = append(pools, e.Value.(*Pool))
+	}
+	for _, p := range pools { // close pools
+		err = p.Close()
+		if err != nil {
+			errs.PushBack(errE(err))
+		}
+	}
 	for e := env.openSrvs.Front(); e != nil; e = e.Next() { // close servers
 		err = e.Value.(*Srv).Close()
 		if err != nil {
@@ -139,13 +187,17 @@ func (env *Env) OpenSrv(cfg *SrvCfg) (srv *Srv, err error) {
 		return nil, errE(err)
 	}
 	// attach to server
-	cDblink := C.CString(cfg.Dblink)
+	dblink := cfg.Dblink
+	if cfg.IsDRCP {
+		dblink = drcpDblink(dblink)
+	}
+	cDblink := C.CString(dblink)
 	defer C.free(unsafe.Pointer(cDblink))
 	r := C.OCIServerAttach(
 		(*C.OCIServer)(ocisrv),                //OCIServer     *srvhp,
 		env.ocierr,                            //OCIError      *errhp,
 		(*C.OraText)(unsafe.Pointer(cDblink)), //const OraText *dblink,
-		C.sb4(len(cfg.Dblink)),                //sb4           dblink_len,
+		C.sb4(len(dblink)),                    //sb4           dblink_len,
 		C.OCI_DEFAULT)                         //ub4           mode);
 	if r == C.OCI_ERROR {
 		return nil, errE(env.ociError())
@@ -176,50 +228,99 @@ func (env *Env) OpenSrv(cfg *SrvCfg) (srv *Srv, err error) {
 	return srv, nil
 }
 
-var (
-	conCharset   = make(map[string]string, 2)
-	conCharsetMu sync.Mutex
-)
-
 // OpenCon starts an Oracle session on a server returning a *Con and possible error.
 //
 // The connection string has the form username/password@dblink e.g., scott/tiger@orcl
 // dblink is a connection identifier such as a net service name,
 // full connection identifier, or a simple connection identifier.
 // The dblink may be defined in the client machine's tnsnames.ora file.
+//
+// A username of the form proxy[client] e.g., appserver[scott]/secret@orcl
+// authenticates as the proxy user and connects as the client user; see
+// SesCfg.ProxyUsername. A trailing AS SYSDBA or AS SYSOPER e.g.,
+// sys/secret@orcl as sysdba connects with the administrative privilege; see
+// SesCfg.Privilege.
+//
+// A connection string of / or /@dblink e.g., /@orcl connects without a
+// password, authenticating with external credentials such as an operating
+// system account or a wallet.
+//
+// See ParseConStr for passwords with special characters and the forms of
+// dblink.
 func (env *Env) OpenCon(str string) (con *Con, err error) {
 	// do not lock; calls to env.OpenSrv will lock
 	env.log(_drv.cfg.Log.Env.OpenCon)
+	return env.openCon(str, nil, nil)
+}
+
+// OpenConWithCfg starts an Oracle session on a server returning a *Con and
+// possible error.
+//
+// The connection string has the same form as OpenCon. The specified sesCfg and
+// srvCfg are used to open the session and server, allowing options such as a
+// StmtCfg to be supplied at connect time. Either cfg may be nil, in which case
+// default values are used.
+//
+// The username, password and dblink from the connection string take precedence
+// over those of the specified cfgs when present. An empty connection string
+// uses the values of the specified cfgs. The specified cfgs are not modified.
+func (env *Env) OpenConWithCfg(str string, sesCfg *SesCfg, srvCfg *SrvCfg) (con *Con, err error) {
+	// do not lock; calls to env.OpenSrv will lock
+	env.log(_drv.cfg.Log.Env.OpenCon)
+	return env.openCon(str, sesCfg, srvCfg)
+}
+
+// openCon starts an Oracle session on a server returning a *Con and possible error.
+func (env *Env) openCon(str string, sesCfg *SesCfg, srvCfg *SrvCfg) (con *Con, err error) {
 	err = env.checkClosed()
 	if err != nil {
 		return nil, errE(err)
 	}
 	// parse connection string
-	var username string
-	var password string
-	var dblink string
-	str = strings.TrimSpace(str)
-	if strings.HasPrefix(str, "/@") {
-		dblink = str[2:]
+	if str == "" && sesCfg == nil && srvCfg == nil {
+		return nil, er("Parameter 'str' may not be empty.")
+	}
+	cs, err := ParseConStr(str)
+	if err != nil {
+		return nil, errE(err)
+	}
+	username, password, dblink, privilege := cs.Username, cs.Password, cs.Dblink, cs.Privilege
+	if srvCfg == nil {
+		srvCfg = NewSrvCfg()
 	} else {
-		str = strings.Replace(str, "/", " / ", 1)
-		str = strings.Replace(str, "@", " @ ", 1)
-		_, err := fmt.Sscanf(str, "%s / %s @ %s", &username, &password, &dblink)
-		if err != nil {
-			return nil, errE(err)
-		}
+		c := *srvCfg // copy by value so that the caller's cfg is unchanged
+		srvCfg = &c
 	}
-	srvCfg := NewSrvCfg()
-	srvCfg.Dblink = dblink
+	if dblink != "" {
+		srvCfg.Dblink = dblink
+	}
+	dblink = srvCfg.Dblink
 	srv, err := env.OpenSrv(srvCfg) // open Srv
 	if err != nil {
 		return nil, errE(err)
 	}
-	sesCfg := NewSesCfg()
-	sesCfg.Username = username
-	sesCfg.Password = password
-	sesCfg.StmtCfg = srv.env.cfg.StmtCfg // sqlPkg StmtCfg has been configured for database/sql package
-	ses, err := srv.OpenSes(sesCfg)      // open Ses
+	if sesCfg == nil {
+		sesCfg = NewSesCfg()
+		sesCfg.StmtCfg = srv.env.cfg.StmtCfg // sqlPkg StmtCfg has been configured for database/sql package
+	} else {
+		c := *sesCfg // copy by value so that the caller's cfg is unchanged
+		sesCfg = &c
+		if sesCfg.StmtCfg == nil {
+			sesCfg.StmtCfg = srv.env.cfg.StmtCfg
+		}
+	}
+	if n := strings.IndexByte(username, '['); n > 0 && strings.HasSuffix(username, "]") {
+		sesCfg.ProxyUsername = username[n+1 : len(username)-1] // proxy[client] username
+		username = username[:n]
+	}
+	if username != "" || password != "" {
+		sesCfg.Username = username
+		sesCfg.Password = password
+	}
+	if privilege != PrivilegeDefault {
+		sesCfg.Privilege = privilege
+	}
+	ses, err := srv.OpenSes(sesCfg) // open Ses
 	if err != nil {
 		return nil, errE(err)
 	}
@@ -228,29 +329,182 @@ func (env *Env) OpenCon(str string) (con *Con, err error) {
 	con.srv = srv
 	con.ses = ses
 	con.elem = env.openCons.PushBack(con)
+	con.isBroken = false
 	if con.id == 0 {
 		con.id = _drv.conId.nextId()
 	}
-	conCharsetMu.Lock()
-	defer conCharsetMu.Unlock()
-	if cs, ok := conCharset[dblink]; ok {
-		srv.dbIsUTF8 = cs == "AL32UTF8"
-		return con, nil
-	}
-	if rset, err := ses.PrepAndQry(
-		`SELECT property_value FROM database_properties WHERE property_name = 'NLS_CHARACTERSET'`,
-	); err != nil {
-		//Log.Errorf("E%vS%vS%v] Determine database characterset: %v",
-		//	env.id, con.id, ses.id, err)
-	} else if rset != nil && rset.Next() && len(rset.Row) == 1 {
-		//Log.Infof("E%vS%vS%v] Database characterset=%q",
-		//	env.id, con.id, ses.id, rset.Row[0])
-		if cs, ok := rset.Row[0].(string); ok {
-			conCharset[dblink] = cs
-			con.srv.dbIsUTF8 = cs == "AL32UTF8"
+	return con, nil
+}
+
+// ConStr is a connection string of the form username/password@dblink parsed
+// by ParseConStr.
+type ConStr struct {
+	// Username may have the proxy[client] form.
+	Username string
+	Password string
+	Dblink   string
+
+	// Privilege is set by a trailing AS SYSDBA or AS SYSOPER.
+	Privilege Privilege
+}
+
+// ParseConStr parses a connection string of the form
+// username/password@dblink [AS SYSDBA|AS SYSOPER] used by Env.OpenCon.
+//
+// The dblink follows the last @ of an unquoted password, so that a password
+// may contain a /. Enclose a password containing an @ in double quotes, e.g.
+// scott/"p@ss/word"@orcl. The dblink may be a net service name, an Easy
+// Connect string such as host:port/service, or a full connect descriptor such
+// as (DESCRIPTION=(ADDRESS=...)).
+//
+// A connection string of / or /@dblink has no username or password, for
+// external authentication.
+func ParseConStr(str string) (cs ConStr, err error) {
+	str = strings.TrimSpace(str)
+	if fields := strings.Fields(str); len(fields) > 2 && strings.EqualFold(fields[len(fields)-2], "AS") {
+		switch strings.ToUpper(fields[len(fields)-1]) { // trailing AS SYSDBA or AS SYSOPER
+		case "SYSDBA":
+			cs.Privilege = PrivilegeSysDBA
+		case "SYSOPER":
+			cs.Privilege = PrivilegeSysOper
+		}
+		if cs.Privilege != PrivilegeDefault {
+			str = strings.TrimSpace(str[:strings.LastIndex(strings.ToUpper(str), "AS")])
+		}
+	}
+	if str == "/" || strings.HasPrefix(str, "/@") { // external credentials
+		cs.Dblink = strings.TrimSpace(strings.TrimPrefix(str[1:], "@"))
+		return cs, nil
+	}
+	// the username ends at the first / or @
+	n := strings.IndexAny(str, "/@")
+	if n < 0 {
+		cs.Username = str
+		return cs, nil
+	}
+	cs.Username, str = strings.TrimSpace(str[:n]), str[n:]
+	if cs.Username == "" {
+		return cs, errF("Invalid connection string: missing username before %q.", str[:1])
+	}
+	if str[0] == '/' {
+		str = strings.TrimSpace(str[1:])
+		if strings.HasPrefix(str, `"`) { // a quoted password may contain / and @
+			end := strings.IndexByte(str[1:], '"')
+			if end < 0 {
+				return cs, errF("Invalid connection string: unterminated quoted password.")
+			}
+			cs.Password, str = str[1:end+1], strings.TrimSpace(str[end+2:])
+			if str != "" && str[0] != '@' {
+				return cs, errF("Invalid connection string: expected @ after the quoted password.")
+			}
+		} else if n = strings.LastIndexByte(str, '@'); n >= 0 {
+			cs.Password, str = str[:n], str[n:]
+		} else {
+			cs.Password, str = str, ""
 		}
 	}
-	return con, nil
+	if str != "" { // str begins with @
+		cs.Dblink = strings.TrimSpace(str[1:])
+		if cs.Dblink == "" {
+			return cs, errF("Invalid connection string: missing dblink after @.")
+		}
+	}
+	return cs, nil
+}
+
+// OpenPool creates an Oracle session pool returning a *Pool and possible error.
+//
+// The Pool opens cfg.Min sessions to the cfg.Dblink server authenticated with
+// cfg.Username and cfg.Password, and grows by cfg.Incr sessions up to cfg.Max
+// sessions as sessions are requested with Pool.Get.
+func (env *Env) OpenPool(cfg *PoolCfg) (p *Pool, err error) {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	env.log(_drv.cfg.Log.Env.OpenPool)
+	err = env.checkClosed()
+	if err != nil {
+		return nil, errE(err)
+	}
+	if cfg == nil {
+		return nil, er("Parameter 'cfg' may not be nil.")
+	}
+	// allocate session pool handle
+	ocispool, err := env.allocOciHandle(C.OCI_HTYPE_SPOOL)
+	if err != nil {
+		return nil, errE(err)
+	}
+	// create session pool
+	cDblink := C.CString(cfg.Dblink)
+	defer C.free(unsafe.Pointer(cDblink))
+	cUsername := C.CString(cfg.Username)
+	defer C.free(unsafe.Pointer(cUsername))
+	cPassword := C.CString(cfg.Password)
+	defer C.free(unsafe.Pointer(cPassword))
+	var name *C.OraText
+	var nameLen C.ub4
+	r := C.OCISessionPoolCreate(
+		env.ocienv,                              //OCIEnv        *envhp,
+		env.ocierr,                              //OCIError      *errhp,
+		(*C.OCISPool)(ocispool),                 //OCISPool      *spoolhp,
+		&name,                                   //OraText       **poolName,
+		&nameLen,                                //ub4           *poolNameLen,
+		(*C.OraText)(unsafe.Pointer(cDblink)),   //const OraText *connStr,
+		C.ub4(len(cfg.Dblink)),                  //ub4           connStrLen,
+		C.ub4(cfg.Min),                          //ub4           sessMin,
+		C.ub4(cfg.Max),                          //ub4           sessMax,
+		C.ub4(cfg.Incr),                         //ub4           sessIncr,
+		(*C.OraText)(unsafe.Pointer(cUsername)), //OraText       *userid,
+		C.ub4(len(cfg.Username)),                //ub4           useridLen,
+		(*C.OraText)(unsafe.Pointer(cPassword)), //OraText       *password,
+		C.ub4(len(cfg.Password)),                //ub4           passwordLen,
+		C.OCI_SPC_HOMOGENEOUS)                   //ub4           mode );
+	if r == C.OCI_ERROR {
+		err = env.ociError()
+		env.freeOciHandle(ocispool, C.OCI_HTYPE_SPOOL)
+		return nil, errE(err)
+	}
+	// set the get mode determining whether Pool.Get waits for a session
+	getMode := C.ub1(C.OCI_SPOOL_ATTRVAL_WAIT)
+	if cfg.WaitTimeout < 0 {
+		getMode = C.OCI_SPOOL_ATTRVAL_NOWAIT
+	} else if cfg.WaitTimeout > 0 {
+		getMode = C.OCI_SPOOL_ATTRVAL_TIMEDWAIT
+	}
+	err = env.setAttr(ocispool, C.OCI_HTYPE_SPOOL, unsafe.Pointer(&getMode), C.ub4(0), C.OCI_ATTR_SPOOL_GETMODE)
+	if err == nil && cfg.WaitTimeout > 0 {
+		waitTimeout := C.ub4(cfg.WaitTimeout / time.Millisecond)
+		err = env.setAttr(ocispool, C.OCI_HTYPE_SPOOL, unsafe.Pointer(&waitTimeout), C.ub4(0), C.OCI_ATTR_SPOOL_WAIT_TIMEOUT)
+		if oe, ok := err.(*Error); ok && oe.Code == illegalAttrCode {
+			err = errF("A positive PoolCfg.WaitTimeout (%v) requires an Oracle 12.2 or later client.", cfg.WaitTimeout)
+		}
+	}
+	if err != nil {
+		C.OCISessionPoolDestroy((*C.OCISPool)(ocispool), env.ocierr, C.OCI_SPD_FORCE)
+		env.freeOciHandle(ocispool, C.OCI_HTYPE_SPOOL)
+		return nil, errE(err)
+	}
+
+	p = &Pool{openSrvs: list.New()} // set *Pool
+	p.env = env
+	p.ocispool = (*C.OCISPool)(ocispool)
+	p.name = name
+	p.nameLen = nameLen
+	p.elem = env.openPools.PushBack(p)
+	p.id = _drv.poolId.nextId()
+	p.cfg = *cfg
+	if p.cfg.StmtCfg == nil && env.cfg.StmtCfg != nil {
+		p.cfg.StmtCfg = &(*env.cfg.StmtCfg) // copy by value so that user may change independently
+	}
+	// determine the database character set with a pooled session
+	ses, err := p.Get()
+	if err != nil {
+		p.Close()
+		return nil, errE(err)
+	}
+	ses.srv.loadDbCharset(ses)
+	p.dbCharset = ses.srv.dbCharset
+	p.Put(ses)
+	return p, nil
 }
 
 // NumSrv returns the number of open Oracle servers.
@@ -267,6 +521,90 @@ func (env *Env) NumCon() int {
 	return env.openCons.Len()
 }
 
+// TrimIdle releases idle resources of the Env's open sessions, returning the
+// number of open Stmts before and after trimming.
+//
+// Idle Stmts cached by Ses.PrepCached are closed, releasing their server
+// cursors; cached Stmts in use are closed when their Close method is called.
+// Open Sessions and Srvs are not closed. Idle connections of the database/sql
+// package are released with sql.DB.SetMaxIdleConns.
+func (env *Env) TrimIdle() (before, after int, err error) {
+	env.mu.Lock()
+	srvs := make([]*Srv, 0, env.openSrvs.Len())
+	for e := env.openSrvs.Front(); e != nil; e = e.Next() {
+		srvs = append(srvs, e.Value.(*Srv))
+	}
+	env.mu.Unlock()
+	var sess []*Ses
+	for _, srv := range srvs {
+		srv.mu.Lock()
+		for e := srv.openSess.Front(); e != nil; e = e.Next() {
+			sess = append(sess, e.Value.(*Ses))
+		}
+		srv.mu.Unlock()
+	}
+	errs := _drv.listPool.Get().(*list.List)
+	defer func() {
+		errs.Init()
+		_drv.listPool.Put(errs)
+	}()
+	for _, ses := range sess {
+		before += ses.NumStmt()
+		if err = ses.ClearPrepCache(); err != nil {
+			errs.PushBack(errE(err))
+		}
+		after += ses.NumStmt()
+	}
+	env.logF(_drv.cfg.Log.Env.TrimIdle, "Stmts %v before, %v after", before, after)
+	if multiErr := newMultiErrL(errs); multiErr != nil {
+		return before, after, errE(*multiErr)
+	}
+	return before, after, nil
+}
+
+// Sessions returns a snapshot of the Env's open sessions, including sessions
+// obtained from its Pools, for diagnostics such as detecting leaked or long
+// idle sessions.
+//
+// Sessions is safe for concurrent use and doesn't make calls to the Oracle
+// server.
+func (env *Env) Sessions() []SesInfo {
+	env.mu.Lock()
+	srvs := make([]*Srv, 0, env.openSrvs.Len())
+	for e := env.openSrvs.Front(); e != nil; e = e.Next() {
+		srvs = append(srvs, e.Value.(*Srv))
+	}
+	pools := make([]*Pool, 0, env.openPools.Len())
+	for e := env.openPools.Front(); e != nil; e = e.Next() {
+		pools = append(pools, e.Value.(*Pool))
+	}
+	env.mu.Unlock()
+	var sess []*Ses
+	for _, srv := range srvs {
+		srv.mu.Lock()
+		for e := srv.openSess.Front(); e != nil; e = e.Next() {
+			sess = append(sess, e.Value.(*Ses))
+		}
+		srv.mu.Unlock()
+	}
+	for _, p := range pools {
+		p.mu.Lock()
+		for e := p.openSrvs.Front(); e != nil; e = e.Next() {
+			if f := e.Value.(*Srv).openSess.Front(); f != nil {
+				sess = append(sess, f.Value.(*Ses))
+			}
+		}
+		p.mu.Unlock()
+	}
+	infos := make([]SesInfo, 0, len(sess))
+	for _, ses := range sess {
+		if info, ok := ses.info(); ok { // skip sessions closed since the snapshot
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
 // SetCfg applies the specified cfg to the Env.
 //
 // Open Srvs do not observe the specified cfg.
@@ -379,6 +717,134 @@ func (env *Env) setAttr(
 	return nil
 }
 
+// attr gets an attribute value of a handle or descriptor. No locking occurs.
+func (env *Env) attr(
+	target unsafe.Pointer,
+	targetType C.ub4,
+	attribute unsafe.Pointer,
+	attributeType C.ub4) (err error) {
+
+	r := C.OCIAttrGet(
+		target,        //const void  *trgthndlp,
+		targetType,    //ub4         trghndltyp,
+		attribute,     //void        *attributep,
+		nil,           //ub4         *sizep,
+		attributeType, //ub4         attrtype,
+		env.ocierr)    //OCIError    *errhp );
+	if r == C.OCI_ERROR {
+		return errE(env.ociError())
+	}
+	return nil
+}
+
+// passwordExpiredCode is the ORA-28001 error code returned when beginning a
+// session whose password has expired.
+const passwordExpiredCode = 28001
+
+// illegalAttrCode is the ORA-24315 illegal attribute type error code returned
+// by an Oracle client that doesn't support an attribute.
+const illegalAttrCode = 24315
+
+// userCancelCode is the ORA-01013 error code returned by an OCI call
+// interrupted with OCIBreak.
+const userCancelCode = 1013
+
+// setOptionalAttr sets an attribute which an older Oracle client may not
+// support, such as a LOB prefetch attribute. An attribute rejected by the
+// client as illegal is logged once and skipped on subsequent calls rather than
+// returning an error. No locking occurs.
+func (env *Env) setOptionalAttr(
+	target unsafe.Pointer,
+	targetType C.ub4,
+	attribute unsafe.Pointer,
+	attributeSize C.ub4,
+	attributeType C.ub4,
+	attributeName string) (err error) {
+
+	env.attrMu.Lock()
+	isUnsupported := env.unsupportedAttrs[attributeType]
+	env.attrMu.Unlock()
+	if isUnsupported {
+		return nil
+	}
+	r := C.OCIAttrSet(
+		target,        //void        *trgthndlp,
+		targetType,    //ub4         trghndltyp,
+		attribute,     //void        *attributep,
+		attributeSize, //ub4         size,
+		attributeType, //ub4         attrtype,
+		env.ocierr)    //OCIError    *errhp );
+	if r == C.OCI_ERROR {
+		code, err := env.ociErrorCode()
+		if code != illegalAttrCode {
+			return errE(err)
+		}
+		env.attrMu.Lock()
+		if env.unsupportedAttrs == nil {
+			env.unsupportedAttrs = make(map[C.ub4]bool)
+		}
+		env.unsupportedAttrs[attributeType] = true
+		env.attrMu.Unlock()
+		_drv.cfg.Log.Logger.Infof("%v %v is unsupported by the Oracle client and is skipped", env.sysName(), attributeName)
+	}
+	return nil
+}
+
+// optionalAttr gets an attribute which an older Oracle client may not
+// support. ok is false when the client rejects the attribute as illegal; the
+// attribute is then logged once and not requested again. No locking occurs.
+func (env *Env) optionalAttr(
+	target unsafe.Pointer,
+	targetType C.ub4,
+	attribute unsafe.Pointer,
+	attributeSize *C.ub4,
+	attributeType C.ub4,
+	attributeName string) (ok bool, err error) {
+
+	env.attrMu.Lock()
+	isUnsupported := env.unsupportedAttrs[attributeType]
+	env.attrMu.Unlock()
+	if isUnsupported {
+		return false, nil
+	}
+	r := C.OCIAttrGet(
+		target,        //const void  *trgthndlp,
+		targetType,    //ub4         trghndltyp,
+		attribute,     //void        *attributep,
+		attributeSize, //ub4         *sizep,
+		attributeType, //ub4         attrtype,
+		env.ocierr)    //OCIError    *errhp );
+	if r == C.OCI_ERROR {
+		code, err := env.ociErrorCode()
+		if code != illegalAttrCode {
+			return false, errE(err)
+		}
+		env.attrMu.Lock()
+		if env.unsupportedAttrs == nil {
+			env.unsupportedAttrs = make(map[C.ub4]bool)
+		}
+		env.unsupportedAttrs[attributeType] = true
+		env.attrMu.Unlock()
+		_drv.cfg.Log.Logger.Infof("%v %v is unsupported by the Oracle client and is skipped", env.sysName(), attributeName)
+		return false, nil
+	}
+	return true, nil
+}
+
+// ociErrorCode gets the Oracle error code and error returned by an Oracle
+// server. No locking occurs.
+func (env *Env) ociErrorCode() (int, error) {
+	var errcode C.sb4
+	C.OCIErrorGet(
+		unsafe.Pointer(env.ocierr),
+		1, nil,
+		&errcode,
+		(*C.OraText)(unsafe.Pointer(&env.errBuf[0])),
+		C.ub4(len(env.errBuf)),
+		C.OCI_HTYPE_ERROR)
+	return int(errcode), errOci(int(errcode), C.GoString(&env.errBuf[0]))
+}
+
 // getOciError gets an error returned by an Oracle server. No locking occurs.
 func (env *Env) ociError() error {
 	var errcode C.sb4
@@ -389,5 +855,74 @@ func (env *Env) ociError() error {
 		(*C.OraText)(unsafe.Pointer(&env.errBuf[0])),
 		C.ub4(len(env.errBuf)),
 		C.OCI_HTYPE_ERROR)
-	return er(C.GoString(&env.errBuf[0]))
+	return errOci(int(errcode), C.GoString(&env.errBuf[0]))
+}
+
+// numberNlsParams fixes the decimal character used during OCINumber text
+// conversions regardless of session NLS settings.
+const numberNlsParams = "NLS_NUMERIC_CHARACTERS='.,'"
+
+// numberFromText converts a decimal string to an OCINumber. No locking occurs.
+func (env *Env) numberFromText(text string, number *C.OCINumber) error {
+	text = strings.TrimPrefix(text, "+")
+	// build a format model matching the digits and decimal point of the text
+	format := make([]byte, 0, len(text))
+	for n, c := range []byte(text) {
+		switch {
+		case c >= '0' && c <= '9':
+			format = append(format, '9')
+		case c == '.':
+			format = append(format, '.')
+		case c == '-' && n == 0:
+		default:
+			return errF("Invalid decimal number (%v).", text)
+		}
+	}
+	if len(format) == 0 {
+		return errF("Invalid decimal number (%v).", text)
+	}
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+	cFormat := C.CString(string(format))
+	defer C.free(unsafe.Pointer(cFormat))
+	cNlsParams := C.CString(numberNlsParams)
+	defer C.free(unsafe.Pointer(cNlsParams))
+	r := C.OCINumberFromText(
+		env.ocierr,                               //OCIError        *err,
+		(*C.oratext)(unsafe.Pointer(cText)),      //const oratext   *str,
+		C.ub4(len(text)),                         //ub4             str_length,
+		(*C.oratext)(unsafe.Pointer(cFormat)),    //const oratext   *fmt,
+		C.ub4(len(format)),                       //ub4             fmt_length,
+		(*C.oratext)(unsafe.Pointer(cNlsParams)), //const oratext   *nls_params,
+		C.ub4(len(numberNlsParams)),              //ub4             nls_p_length,
+		number)                                   //OCINumber       *number );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+// numberToText converts an OCINumber to a minimal decimal string. No locking
+// occurs.
+func (env *Env) numberToText(number *C.OCINumber) (string, error) {
+	const format = "TM9"
+	cFormat := C.CString(format)
+	defer C.free(unsafe.Pointer(cFormat))
+	cNlsParams := C.CString(numberNlsParams)
+	defer C.free(unsafe.Pointer(cNlsParams))
+	var buf [64]byte
+	bufSize := C.ub4(len(buf))
+	r := C.OCINumberToText(
+		env.ocierr,                               //OCIError        *err,
+		number,                                   //const OCINumber *number,
+		(*C.oratext)(unsafe.Pointer(cFormat)),    //const oratext   *fmt,
+		C.ub4(len(format)),                       //ub4             fmt_length,
+		(*C.oratext)(unsafe.Pointer(cNlsParams)), //const oratext   *nls_params,
+		C.ub4(len(numberNlsParams)),              //ub4             nls_p_length,
+		&bufSize,                                 //ub4             *buf_size,
+		(*C.oratext)(unsafe.Pointer(&buf[0])))    //oratext         *buf );
+	if r == C.OCI_ERROR {
+		return "", env.ociError()
+	}
+	return string(buf[:bufSize]), nil
 }
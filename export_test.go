@@ -0,0 +1,8 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+// Failover exposes the marshaling of the OCI failover callback to tests.
+var Failover = failover
@@ -0,0 +1,169 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+
+extern sb4 goFailover(void *svcctx, void *envctx, void *fo_ctx, ub4 fo_type, ub4 fo_event);
+*/
+import "C"
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// FailoverType is the kind of Transparent Application Failover configured for
+// a connection with the FAILOVER_MODE of its connect descriptor.
+type FailoverType uint32
+
+// failover types
+const (
+	// FailoverNone fails over no work; the user must reconnect.
+	FailoverNone FailoverType = C.OCI_FO_NONE
+	// FailoverSession reconnects the session only.
+	FailoverSession FailoverType = C.OCI_FO_SESSION
+	// FailoverSelect reconnects the session and resumes open queries.
+	FailoverSelect FailoverType = C.OCI_FO_SELECT
+	// FailoverTransactional reconnects the session and resumes transactions.
+	FailoverTransactional FailoverType = C.OCI_FO_TXNAL
+)
+
+// String returns the name of the FailoverType.
+func (t FailoverType) String() string {
+	switch t {
+	case FailoverNone:
+		return "NONE"
+	case FailoverSession:
+		return "SESSION"
+	case FailoverSelect:
+		return "SELECT"
+	case FailoverTransactional:
+		return "TRANSACTIONAL"
+	}
+	return fmt.Sprintf("FailoverType(%d)", uint32(t))
+}
+
+// FailoverEvent is a step of a Transparent Application Failover.
+type FailoverEvent uint32
+
+// failover events
+const (
+	// FailoverBegin reports a lost connection and the start of failover.
+	FailoverBegin FailoverEvent = C.OCI_FO_BEGIN
+	// FailoverEnd reports a completed failover.
+	FailoverEnd FailoverEvent = C.OCI_FO_END
+	// FailoverAbort reports a failover which failed and won't be retried.
+	FailoverAbort FailoverEvent = C.OCI_FO_ABORT
+	// FailoverReauth reports a session reauthenticated after failover.
+	FailoverReauth FailoverEvent = C.OCI_FO_REAUTH
+	// FailoverError reports a failover attempt which failed; the FailoverFunc
+	// may request another attempt.
+	FailoverError FailoverEvent = C.OCI_FO_ERROR
+)
+
+// String returns the name of the FailoverEvent.
+func (e FailoverEvent) String() string {
+	switch e {
+	case FailoverBegin:
+		return "BEGIN"
+	case FailoverEnd:
+		return "END"
+	case FailoverAbort:
+		return "ABORT"
+	case FailoverReauth:
+		return "REAUTH"
+	case FailoverError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("FailoverEvent(%d)", uint32(e))
+}
+
+// FailoverFunc is called by the Oracle client during a Transparent
+// Application Failover of a Srv.
+//
+// Returning true for a FailoverError event requests another failover
+// attempt; the FailoverFunc should sleep briefly before doing so. The return
+// value is ignored for other events.
+//
+// A FailoverFunc runs during an OCI call of the Srv and must not call methods
+// of the Srv or of its Sess.
+type FailoverFunc func(srv *Srv, failoverType FailoverType, event FailoverEvent) (retry bool)
+
+// failoverFuncs holds the registered FailoverFunc of each server handle,
+// which the OCI callback finds through its context pointer.
+var failoverFuncs = struct {
+	sync.Mutex
+	m map[unsafe.Pointer]failoverReg
+}{m: make(map[unsafe.Pointer]failoverReg)}
+
+type failoverReg struct {
+	srv *Srv
+	fn  FailoverFunc
+}
+
+// RegisterFailover registers a FailoverFunc called during Transparent
+// Application Failover of the Srv. A nil fn unregisters the Srv's
+// FailoverFunc.
+//
+// Failover occurs only for a Dblink whose connect descriptor specifies a
+// FAILOVER_MODE, or whose service is configured for failover.
+func (srv *Srv) RegisterFailover(fn FailoverFunc) (err error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.log(_drv.cfg.Log.Srv.RegisterFailover)
+	err = srv.checkClosed()
+	if err != nil {
+		return errE(err)
+	}
+	var fo C.OCIFocbkStruct
+	if fn != nil {
+		fo.callback_function = C.OCICallbackFailover(C.goFailover)
+		fo.fo_ctx = unsafe.Pointer(srv.ocisrv)
+	}
+	err = srv.env.setAttr(unsafe.Pointer(srv.ocisrv), C.OCI_HTYPE_SERVER, unsafe.Pointer(&fo), 0, C.OCI_ATTR_FOCBK)
+	if err != nil {
+		return errE(err)
+	}
+	failoverFuncs.Lock()
+	if fn == nil {
+		delete(failoverFuncs.m, unsafe.Pointer(srv.ocisrv))
+	} else {
+		failoverFuncs.m[unsafe.Pointer(srv.ocisrv)] = failoverReg{srv: srv, fn: fn}
+	}
+	failoverFuncs.Unlock()
+	return nil
+}
+
+// unregisterFailover removes the Srv's FailoverFunc, if any. No locking of the
+// Srv occurs.
+func (srv *Srv) unregisterFailover() {
+	failoverFuncs.Lock()
+	delete(failoverFuncs.m, unsafe.Pointer(srv.ocisrv))
+	failoverFuncs.Unlock()
+}
+
+// failover calls fn for an OCI failover callback, returning the OCI_FO_RETRY
+// code when fn requests a retry of a FailoverError event; otherwise, zero.
+func failover(fn FailoverFunc, srv *Srv, failoverType, event uint32) int32 {
+	retry := fn(srv, FailoverType(failoverType), FailoverEvent(event))
+	if retry && FailoverEvent(event) == FailoverError {
+		return C.OCI_FO_RETRY
+	}
+	return 0
+}
+
+//export goFailover
+func goFailover(svcctx, envctx, foCtx unsafe.Pointer, foType, foEvent C.ub4) C.sb4 {
+	failoverFuncs.Lock()
+	reg, ok := failoverFuncs.m[foCtx]
+	failoverFuncs.Unlock()
+	if !ok {
+		return 0
+	}
+	reg.srv.log(_drv.cfg.Log.Srv.Failover, FailoverType(foType), " ", FailoverEvent(foEvent))
+	return C.sb4(failover(reg.fn, reg.srv, uint32(foType), uint32(foEvent)))
+}
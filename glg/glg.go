@@ -19,6 +19,12 @@ func (l gLgr) Infof(format string, v ...interface{}) {
 func (l gLgr) Infoln(v ...interface{}) {
 	glog.InfoDepth(2, v...)
 }
+func (l gLgr) Warnf(format string, v ...interface{}) {
+	glog.WarningDepth(2, fmt.Sprintf(format, v...))
+}
+func (l gLgr) Warnln(v ...interface{}) {
+	glog.WarningDepth(2, v...)
+}
 func (l gLgr) Errorf(format string, v ...interface{}) {
 	glog.ErrorDepth(2, fmt.Sprintf(format, v...))
 }
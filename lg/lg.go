@@ -24,6 +24,14 @@ func (s Std) Infoln(v ...interface{}) {
 	s.L.SetPrefix("ORA I ")
 	s.L.Output(2, fmt.Sprintln(v...))
 }
+func (s Std) Warnf(format string, v ...interface{}) {
+	s.L.SetPrefix("ORA W ")
+	s.L.Output(2, fmt.Sprintf(format, v...))
+}
+func (s Std) Warnln(v ...interface{}) {
+	s.L.SetPrefix("ORA W ")
+	s.L.Output(2, fmt.Sprintln(v...))
+}
 func (s Std) Errorf(format string, v ...interface{}) {
 	s.L.SetPrefix("ORA E ")
 	s.L.Output(2, fmt.Sprintf(format, v...))
@@ -19,6 +19,8 @@ type lgr struct {
 
 func (s lgr) Infof(format string, args ...interface{})  { s.Debug(fmt.Sprintf(format, args...)) }
 func (s lgr) Infoln(args ...interface{})                { s.Debug(strings.Join(asStrings(args), " ")) }
+func (s lgr) Warnf(format string, args ...interface{})  { s.Warn(fmt.Sprintf(format, args...)) }
+func (s lgr) Warnln(args ...interface{})                { s.Warn(strings.Join(asStrings(args), " ")) }
 func (s lgr) Errorf(format string, args ...interface{}) { s.Error(fmt.Sprintf(format, args...)) }
 func (s lgr) Errorln(args ...interface{})               { s.Error(strings.Join(asStrings(args), " ")) }
 
@@ -5,6 +5,10 @@
 package ora
 
 // Logger interface is for logging.
+//
+// Set LogDrvCfg.Logger to route the package's log messages to any
+// implementation; which calls are logged is set by the other LogDrvCfg
+// fields.
 type Logger interface {
 	Infof(format string, args ...interface{})
 	Infoln(args ...interface{})
@@ -12,9 +16,30 @@ type Logger interface {
 	Errorln(args ...interface{})
 }
 
+// WarnLogger is optionally implemented by a Logger which logs at warning
+// level, such as for slow queries. Warnings are logged with Infof by a Logger
+// which doesn't implement it.
+type WarnLogger interface {
+	Warnf(format string, args ...interface{})
+	Warnln(args ...interface{})
+}
+
+// logWarnf writes a warning with LogDrvCfg.Logger, at warning level when the
+// Logger implements WarnLogger.
+func logWarnf(format string, v ...interface{}) {
+	if w, ok := _drv.cfg.Log.Logger.(WarnLogger); ok {
+		w.Warnf(format, v...)
+		return
+	}
+	_drv.cfg.Log.Logger.Infof(format, v...)
+}
+
+// EmpLgr is a Logger discarding all messages, and is the default Logger.
 type EmpLgr struct{}
 
 func (e EmpLgr) Infof(format string, v ...interface{})  {}
 func (e EmpLgr) Infoln(v ...interface{})                {}
+func (e EmpLgr) Warnf(format string, v ...interface{})  {}
+func (e EmpLgr) Warnln(v ...interface{})                {}
 func (e EmpLgr) Errorf(format string, v ...interface{}) {}
 func (e EmpLgr) Errorln(v ...interface{})               {}
@@ -0,0 +1,256 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Number is an Oracle NUMBER held in its exact decimal text form, for example
+// "-1234.56".
+//
+// Number implements sql.Scanner and driver.Valuer, so that it survives a round
+// trip through the database/sql package with this or another driver without
+// conversion through a binary float. Scan a nullable column into a **Number
+// or a Decimal.
+//
+// A Number is normalized, without a leading plus sign, leading zeros or
+// trailing fractional zeros, so that equal Numbers have equal text. The zero
+// value is 0. Arithmetic and comparison methods panic for text which isn't a
+// decimal number; create Numbers with ParseNumber or Scan.
+type Number string
+
+// ParseNumber returns the normalized Number of decimal text such as
+// "-1234.5600" or "1.5E+3".
+func ParseNumber(text string) (Number, error) {
+	unscaled, scale, ok := parseDecimal(text)
+	if !ok {
+		return "", errF("Invalid decimal number (%v).", text)
+	}
+	return formatDecimal(unscaled, scale), nil
+}
+
+// Scan sets the Number from a NUMBER column value.
+//
+// Scan is a member of the sql.Scanner interface.
+func (n *Number) Scan(src interface{}) (err error) {
+	switch value := src.(type) {
+	case nil:
+		return errNew("converting NULL to Number is unsupported; scan into a **Number")
+	case Number:
+		*n, err = ParseNumber(string(value))
+	case Decimal:
+		if value.IsNull {
+			return errNew("converting NULL to Number is unsupported; scan into a **Number")
+		}
+		*n, err = ParseNumber(value.Value)
+	case string:
+		*n, err = ParseNumber(value)
+	case []byte:
+		*n, err = ParseNumber(string(value))
+	case int64:
+		*n = Number(strconv.FormatInt(value, 10))
+	case uint64:
+		*n = Number(strconv.FormatUint(value, 10))
+	case float64:
+		*n, err = ParseNumber(strconv.FormatFloat(value, 'f', -1, 64))
+	case float32:
+		*n, err = ParseNumber(strconv.FormatFloat(float64(value), 'f', -1, 32))
+	default:
+		return errF("Unsupported Number scan source type (%T).", src)
+	}
+	return err
+}
+
+// Value returns the decimal text of the Number.
+//
+// The ora driver binds a Number parameter as an OCINumber without calling
+// Value; see DrvStmt.CheckNamedValue.
+//
+// Value is a member of the driver.Valuer interface.
+func (n Number) Value() (driver.Value, error) {
+	return n.String(), nil
+}
+
+// String returns the decimal text of the Number.
+func (n Number) String() string {
+	if n == "" {
+		return "0"
+	}
+	return string(n)
+}
+
+// MarshalJSON encodes the Number as a JSON numeric literal.
+func (n Number) MarshalJSON() ([]byte, error) {
+	if _, _, ok := parseDecimal(n.String()); !ok {
+		return nil, errF("Invalid decimal number (%v).", string(n))
+	}
+	return []byte(n.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON numeric literal, or a string holding decimal
+// text, into the Number.
+func (n *Number) UnmarshalJSON(data []byte) (err error) {
+	text := strings.TrimSpace(string(data))
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		text, err = strconv.Unquote(text)
+		if err != nil {
+			return errE(err)
+		}
+	}
+	*n, err = ParseNumber(text)
+	return err
+}
+
+// Add returns the sum n+other.
+func (n Number) Add(other Number) Number {
+	x, xScale := n.decimal()
+	y, yScale := other.decimal()
+	x, y, scale := alignDecimals(x, xScale, y, yScale)
+	return formatDecimal(x.Add(x, y), scale)
+}
+
+// Sub returns the difference n-other.
+func (n Number) Sub(other Number) Number {
+	x, xScale := n.decimal()
+	y, yScale := other.decimal()
+	x, y, scale := alignDecimals(x, xScale, y, yScale)
+	return formatDecimal(x.Sub(x, y), scale)
+}
+
+// Mul returns the product n*other.
+func (n Number) Mul(other Number) Number {
+	x, xScale := n.decimal()
+	y, yScale := other.decimal()
+	return formatDecimal(x.Mul(x, y), xScale+yScale)
+}
+
+// Neg returns the negation -n.
+func (n Number) Neg() Number {
+	x, scale := n.decimal()
+	return formatDecimal(x.Neg(x), scale)
+}
+
+// Cmp compares n and other, returning -1 when n < other, 0 when n == other
+// and +1 when n > other.
+func (n Number) Cmp(other Number) int {
+	x, xScale := n.decimal()
+	y, yScale := other.decimal()
+	x, y, _ = alignDecimals(x, xScale, y, yScale)
+	return x.Cmp(y)
+}
+
+// Sign returns -1 when n < 0, 0 when n == 0 and +1 when n > 0.
+func (n Number) Sign() int {
+	x, _ := n.decimal()
+	return x.Sign()
+}
+
+// Float64 returns the nearest float64 of the Number.
+func (n Number) Float64() (float64, error) {
+	value, err := strconv.ParseFloat(n.String(), 64)
+	if err != nil {
+		return 0, errE(err)
+	}
+	return value, nil
+}
+
+// Int64 returns the int64 of an integral Number, or an error when the Number
+// has a fraction or overflows an int64.
+func (n Number) Int64() (int64, error) {
+	value, err := strconv.ParseInt(n.String(), 10, 64)
+	if err != nil {
+		return 0, errE(err)
+	}
+	return value, nil
+}
+
+// decimal returns the unscaled value and scale of the Number, panicking for
+// invalid decimal text.
+func (n Number) decimal() (*big.Int, int) {
+	unscaled, scale, ok := parseDecimal(n.String())
+	if !ok {
+		panic(errF("Invalid decimal number (%v).", string(n)))
+	}
+	return unscaled, scale
+}
+
+// parseDecimal parses decimal text with an optional sign, fraction and
+// exponent into an unscaled integer and a scale, the number of fractional
+// digits.
+func parseDecimal(text string) (unscaled *big.Int, scale int, ok bool) {
+	text = strings.TrimSpace(text)
+	mantissa, exponent := text, 0
+	if index := strings.IndexAny(text, "eE"); index >= 0 {
+		exp, err := strconv.Atoi(strings.TrimPrefix(text[index+1:], "+"))
+		if err != nil {
+			return nil, 0, false
+		}
+		mantissa, exponent = text[:index], exp
+	}
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") || strings.HasPrefix(mantissa, "+") {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+	digits := mantissa
+	if index := strings.IndexByte(mantissa, '.'); index >= 0 {
+		digits = mantissa[:index] + mantissa[index+1:]
+		scale = len(mantissa) - index - 1
+	}
+	if digits == "" {
+		return nil, 0, false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, 0, false
+		}
+	}
+	unscaled, ok = new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return nil, 0, false
+	}
+	scale -= exponent
+	if scale < 0 {
+		unscaled.Mul(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+	return unscaled, scale, true
+}
+
+// alignDecimals scales two unscaled integers to their larger scale.
+func alignDecimals(x *big.Int, xScale int, y *big.Int, yScale int) (*big.Int, *big.Int, int) {
+	ten := big.NewInt(10)
+	if xScale < yScale {
+		x.Mul(x, new(big.Int).Exp(ten, big.NewInt(int64(yScale-xScale)), nil))
+		return x, y, yScale
+	}
+	if yScale < xScale {
+		y.Mul(y, new(big.Int).Exp(ten, big.NewInt(int64(xScale-yScale)), nil))
+	}
+	return x, y, xScale
+}
+
+// formatDecimal returns the normalized Number of an unscaled integer and scale.
+func formatDecimal(unscaled *big.Int, scale int) Number {
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	digits := new(big.Int).Abs(unscaled).String()
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = strings.TrimRight(digits[:len(digits)-scale]+"."+digits[len(digits)-scale:], "0")
+		digits = strings.TrimSuffix(digits, ".")
+	}
+	if digits == "0" {
+		sign = ""
+	}
+	return Number(sign + digits)
+}
@@ -0,0 +1,494 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+
+// objGetAttr gets the null status and value pointer of a named attribute of
+// an object instance.
+static sword objGetAttr(OCIEnv *env, OCIError *err, void *instance,
+	void *nullStruct, OCIType *tdo, char *name, ub4 nameLen,
+	OCIInd *attrNull, void **attrValue) {
+	const oratext *names[1];
+	ub4 lengths[1];
+	void *attrNullStruct = NULL;
+	OCIType *attrTdo = NULL;
+	names[0] = (const oratext *)name;
+	lengths[0] = nameLen;
+	return OCIObjectGetAttr(env, err, instance, nullStruct, tdo, names,
+		lengths, 1, NULL, 0, attrNull, &attrNullStruct, attrValue, &attrTdo);
+}
+
+// objSetAttr sets the null status and value of a named attribute of an
+// object instance.
+static sword objSetAttr(OCIEnv *env, OCIError *err, void *instance,
+	void *nullStruct, OCIType *tdo, char *name, ub4 nameLen,
+	OCIInd nullStatus, void *attrValue) {
+	const oratext *names[1];
+	ub4 lengths[1];
+	names[0] = (const oratext *)name;
+	lengths[0] = nameLen;
+	return OCIObjectSetAttr(env, err, instance, nullStruct, tdo, names,
+		lengths, 1, NULL, 0, nullStatus, NULL, attrValue);
+}
+*/
+import "C"
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// objs maps Oracle object type names to Go struct types registered with
+// AddObj.
+var objs = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{byName: make(map[string]reflect.Type), byType: make(map[reflect.Type]string)}
+
+// AddObj maps an Oracle object type, such as "POINT" or "SCOTT.POINT", to the
+// struct type of v.
+//
+// A select-list column of a mapped object type is returned as a pointer to a
+// new struct, which is nil for a NULL object. A struct, or pointer to a
+// struct, of a mapped type may be bound as a parameter; a nil pointer is bound
+// as a NULL object.
+//
+// Each exported struct field corresponds to the object attribute of the same
+// name, ignoring case, or to the attribute named by a `db:"NAME"` field tag.
+// A field tagged `db:"-"` is ignored. NUMBER attributes map to Go integer,
+// float and string fields, VARCHAR2, CHAR and VARCHAR attributes to string
+// fields, and DATE attributes to time.Time fields. A pointer field is nil for
+// a NULL attribute; other fields are set to their zero value.
+//
+// AddObj is usually called during program initialization.
+func AddObj(v interface{}, typeName string) (err error) {
+	typ, err := finalType(v)
+	if err != nil {
+		return errE(err)
+	}
+	if typ.Kind() != reflect.Struct {
+		return errF("Unable to map object type %v to non-struct type %v.", typeName, typ)
+	}
+	if typeName == "" {
+		return er("Parameter 'typeName' may not be empty.")
+	}
+	typeName = strings.ToUpper(typeName)
+	logF(_drv.cfg.Log.AddObj, "%v to %v", typ.Name(), typeName)
+	objs.Lock()
+	objs.byName[typeName] = typ
+	objs.byType[typ] = typeName
+	objs.Unlock()
+	return nil
+}
+
+// objStructType returns the Go struct type mapped to an object type by
+// AddObj, matching the schema-qualified name first.
+func objStructType(schemaName, typeName string) (reflect.Type, bool) {
+	objs.RLock()
+	defer objs.RUnlock()
+	if typ, ok := objs.byName[schemaName+"."+typeName]; ok {
+		return typ, true
+	}
+	typ, ok := objs.byName[typeName]
+	return typ, ok
+}
+
+// objTypeName returns the object type name mapped by AddObj to the struct
+// type of v, or of the struct v points to.
+func objTypeName(v interface{}) (string, bool) {
+	typ := reflect.TypeOf(v)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	objs.RLock()
+	defer objs.RUnlock()
+	typeName, ok := objs.byType[typ]
+	return typeName, ok
+}
+
+// objType describes an Oracle object or collection type for a Ses.
+type objType struct {
+	tdo      *C.OCIType
+	name     string // schema-qualified
+	typeCode C.OCITypeCode
+	attrs    []objAttr
+
+	// a collection's OCI_TYPECODE_VARRAY or OCI_TYPECODE_TABLE, and element
+	collTypeCode C.OCITypeCode
+	elem         objAttr
+}
+
+// objAttr is an attribute of an object type, or the element of a collection
+// type.
+type objAttr struct {
+	name      string
+	typeCode  C.OCITypeCode
+	precision C.ub1
+	scale     C.sb1
+}
+
+// objType returns the described object or collection type of the specified
+// name, which is cached for the lifetime of the Ses.
+func (ses *Ses) objType(name string) (*objType, error) {
+	ses.objTypesMu.Lock()
+	defer ses.objTypesMu.Unlock()
+	if typ, ok := ses.objTypes[name]; ok {
+		return typ, nil
+	}
+	typ, err := ses.describeObjType(name)
+	if err != nil {
+		return nil, err
+	}
+	if ses.objTypes == nil {
+		ses.objTypes = make(map[string]*objType)
+	}
+	ses.objTypes[name] = typ
+	return typ, nil
+}
+
+// describeObjType describes the attributes of an object type, or the element
+// of a collection type. No locking occurs.
+func (ses *Ses) describeObjType(name string) (*objType, error) {
+	env := ses.srv.env
+	dschp, err := env.allocOciHandle(C.OCI_HTYPE_DESCRIBE)
+	if err != nil {
+		return nil, err
+	}
+	defer env.freeOciHandle(dschp, C.OCI_HTYPE_DESCRIBE)
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	r := C.OCIDescribeAny(
+		ses.srv.ocisvcctx,       //OCISvcCtx     *svchp,
+		env.ocierr,              //OCIError      *errhp,
+		unsafe.Pointer(cName),   //void          *objptr,
+		C.ub4(len(name)),        //ub4           objptr_len,
+		C.OCI_OTYPE_NAME,        //ub1           objptr_typ,
+		C.OCI_DEFAULT,           //ub1           info_level,
+		C.OCI_PTYPE_TYPE,        //ub1           objtyp,
+		(*C.OCIDescribe)(dschp)) //OCIDescribe   *dschp );
+	if r == C.OCI_ERROR {
+		return nil, env.ociError()
+	}
+	var param, attrList unsafe.Pointer
+	err = env.attr(dschp, C.OCI_HTYPE_DESCRIBE, unsafe.Pointer(&param), C.OCI_ATTR_PARAM)
+	if err != nil {
+		return nil, err
+	}
+	var schemaName, typeName *C.char
+	var schemaNameLen, typeNameLen C.ub4
+	r = C.OCIAttrGet(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&schemaName), &schemaNameLen, C.OCI_ATTR_SCHEMA_NAME, env.ocierr)
+	if r == C.OCI_ERROR {
+		return nil, env.ociError()
+	}
+	r = C.OCIAttrGet(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&typeName), &typeNameLen, C.OCI_ATTR_NAME, env.ocierr)
+	if r == C.OCI_ERROR {
+		return nil, env.ociError()
+	}
+	schema := C.GoStringN(schemaName, C.int(schemaNameLen))
+	typ := &objType{name: schema + "." + C.GoStringN(typeName, C.int(typeNameLen))}
+	var typeCode C.OCITypeCode
+	err = env.attr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&typeCode), C.OCI_ATTR_TYPECODE)
+	if err != nil {
+		return nil, err
+	}
+	typ.typeCode = typeCode
+	if typeCode == C.OCI_TYPECODE_NAMEDCOLLECTION {
+		err = env.attr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&typ.collTypeCode), C.OCI_ATTR_COLLECTION_TYPECODE)
+		if err != nil {
+			return nil, err
+		}
+		var elemParam unsafe.Pointer
+		err = env.attr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&elemParam), C.OCI_ATTR_COLLECTION_ELEMENT)
+		if err != nil {
+			return nil, err
+		}
+		err = env.attr(elemParam, C.OCI_DTYPE_PARAM, unsafe.Pointer(&typ.elem.typeCode), C.OCI_ATTR_TYPECODE)
+		if err != nil {
+			return nil, err
+		}
+		// explicitly described numeric precision and scale are one byte
+		err = env.attr(elemParam, C.OCI_DTYPE_PARAM, unsafe.Pointer(&typ.elem.precision), C.OCI_ATTR_PRECISION)
+		if err != nil {
+			return nil, err
+		}
+		err = env.attr(elemParam, C.OCI_DTYPE_PARAM, unsafe.Pointer(&typ.elem.scale), C.OCI_ATTR_SCALE)
+		if err != nil {
+			return nil, err
+		}
+		typ.tdo, err = ses.typeByName(schema, C.GoStringN(typeName, C.int(typeNameLen)))
+		if err != nil {
+			return nil, err
+		}
+		return typ, nil
+	}
+	if typeCode != C.OCI_TYPECODE_OBJECT {
+		return nil, errF("Type %v isn't an object or collection type (typecode %v).", typ.name, typeCode)
+	}
+	var numAttrs C.ub2
+	err = env.attr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&numAttrs), C.OCI_ATTR_NUM_TYPE_ATTRS)
+	if err != nil {
+		return nil, err
+	}
+	err = env.attr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&attrList), C.OCI_ATTR_LIST_TYPE_ATTRS)
+	if err != nil {
+		return nil, err
+	}
+	typ.attrs = make([]objAttr, numAttrs)
+	for n := range typ.attrs {
+		var attrParam unsafe.Pointer
+		r = C.OCIParamGet(attrList, C.OCI_DTYPE_PARAM, env.ocierr, &attrParam, C.ub4(n+1))
+		if r == C.OCI_ERROR {
+			return nil, env.ociError()
+		}
+		var attrName *C.char
+		var attrNameLen C.ub4
+		r = C.OCIAttrGet(attrParam, C.OCI_DTYPE_PARAM, unsafe.Pointer(&attrName), &attrNameLen, C.OCI_ATTR_NAME, env.ocierr)
+		if r == C.OCI_ERROR {
+			return nil, env.ociError()
+		}
+		typ.attrs[n].name = C.GoStringN(attrName, C.int(attrNameLen))
+		err = env.attr(attrParam, C.OCI_DTYPE_PARAM, unsafe.Pointer(&typ.attrs[n].typeCode), C.OCI_ATTR_TYPECODE)
+		if err != nil {
+			return nil, err
+		}
+	}
+	typ.tdo, err = ses.typeByName(schema, C.GoStringN(typeName, C.int(typeNameLen)))
+	if err != nil {
+		return nil, err
+	}
+	return typ, nil
+}
+
+// objField is a struct field corresponding to an object attribute.
+type objField struct {
+	attr  objAttr
+	index int
+}
+
+// fields returns the fields of a struct type corresponding to the attributes
+// of the object type.
+func (typ *objType) fields(structType reflect.Type) ([]objField, error) {
+	var fields []objField
+	for n := 0; n < structType.NumField(); n++ {
+		f := structType.Field(n)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("db"); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = strings.Split(tag, ",")[0]
+		}
+		name = strings.ToUpper(name)
+		found := false
+		for _, attr := range typ.attrs {
+			if attr.name == name {
+				fields = append(fields, objField{attr: attr, index: n})
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errF("Object type %v has no attribute for field %v.%v.", typ.name, structType.Name(), f.Name)
+		}
+	}
+	return fields, nil
+}
+
+// getStruct sets the fields of a struct from the attributes of an object
+// instance. No locking occurs.
+func (typ *objType) getStruct(ses *Ses, instance, nullStruct unsafe.Pointer, rv reflect.Value) error {
+	fields, err := typ.fields(rv.Type())
+	if err != nil {
+		return err
+	}
+	env := ses.srv.env
+	for _, field := range fields {
+		cName := C.CString(field.attr.name)
+		var null C.OCIInd
+		var attrValue unsafe.Pointer
+		r := C.objGetAttr(env.ocienv, env.ocierr, instance, nullStruct, typ.tdo,
+			cName, C.ub4(len(field.attr.name)), &null, &attrValue)
+		C.free(unsafe.Pointer(cName))
+		if r == C.OCI_ERROR {
+			return env.ociError()
+		}
+		fv := rv.Field(field.index)
+		if null == C.OCI_IND_NULL || attrValue == nil {
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			fv = fv.Elem()
+		}
+		switch field.attr.typeCode {
+		case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT,
+			C.OCI_TYPECODE_DECIMAL, C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE:
+			text, err := env.numberToText((*C.OCINumber)(attrValue))
+			if err != nil {
+				return err
+			}
+			err = setObjNumber(fv, text)
+			if err != nil {
+				return errF("Unable to set field %v from attribute %v: %v", rv.Type().Field(field.index).Name, field.attr.name, err)
+			}
+		case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_CHAR, C.OCI_TYPECODE_VARCHAR:
+			if fv.Kind() != reflect.String {
+				return errF("Unable to set %v field %v from string attribute %v.", fv.Type(), rv.Type().Field(field.index).Name, field.attr.name)
+			}
+			ociString := *(**C.OCIString)(attrValue)
+			fv.SetString(C.GoStringN(
+				(*C.char)(unsafe.Pointer(C.OCIStringPtr(env.ocienv, ociString))),
+				C.int(C.OCIStringSize(env.ocienv, ociString))))
+		case C.OCI_TYPECODE_DATE:
+			if fv.Type() != reflect.TypeOf(time.Time{}) {
+				return errF("Unable to set %v field %v from DATE attribute %v.", fv.Type(), rv.Type().Field(field.index).Name, field.attr.name)
+			}
+			ociDate := (*C.OCIDate)(attrValue)
+			fv.Set(reflect.ValueOf(time.Date(
+				int(ociDate.OCIDateYYYY),
+				time.Month(int(ociDate.OCIDateMM)),
+				int(ociDate.OCIDateDD),
+				int(ociDate.OCIDateTime.OCITimeHH),
+				int(ociDate.OCIDateTime.OCITimeMI),
+				int(ociDate.OCIDateTime.OCITimeSS),
+				0,
+				time.Local)))
+		default:
+			return errF("Unsupported attribute %v of object type %v (typecode %v). Only NUMBER, VARCHAR2, CHAR, VARCHAR and DATE attributes are supported.", field.attr.name, typ.name, field.attr.typeCode)
+		}
+	}
+	return nil
+}
+
+// setObjNumber sets a numeric or string field from NUMBER text.
+func setObjNumber(fv reflect.Value, text string) error {
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(text, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(text, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(text, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(value)
+	case reflect.String:
+		fv.SetString(text)
+	default:
+		return errF("unsupported field type %v for a NUMBER", fv.Type())
+	}
+	return nil
+}
+
+// setStruct sets the attributes of an object instance from the fields of a
+// struct. Strings assigned to attributes are appended to ociStrings, which the
+// caller frees after the instance is used. No locking occurs.
+func (typ *objType) setStruct(ses *Ses, instance, nullStruct unsafe.Pointer, rv reflect.Value, ociStrings *[]*C.OCIString) error {
+	fields, err := typ.fields(rv.Type())
+	if err != nil {
+		return err
+	}
+	env := ses.srv.env
+	for _, field := range fields {
+		fv := rv.Field(field.index)
+		null := C.OCIInd(C.OCI_IND_NOTNULL)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				null = C.OCI_IND_NULL
+			} else {
+				fv = fv.Elem()
+			}
+		}
+		var attrValue unsafe.Pointer
+		if null == C.OCI_IND_NOTNULL {
+			switch field.attr.typeCode {
+			case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT,
+				C.OCI_TYPECODE_DECIMAL, C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE:
+				var text string
+				switch fv.Kind() {
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+					text = strconv.FormatInt(fv.Int(), 10)
+				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+					text = strconv.FormatUint(fv.Uint(), 10)
+				case reflect.Float32, reflect.Float64:
+					text = strconv.FormatFloat(fv.Float(), 'f', -1, fv.Type().Bits())
+				case reflect.String:
+					text = fv.String()
+				default:
+					return errF("Unable to set NUMBER attribute %v from %v field %v.", field.attr.name, fv.Type(), rv.Type().Field(field.index).Name)
+				}
+				number := (*C.OCINumber)(C.malloc(C.sizeof_OCINumber))
+				defer C.free(unsafe.Pointer(number))
+				err = env.numberFromText(text, number)
+				if err != nil {
+					return err
+				}
+				attrValue = unsafe.Pointer(number)
+			case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_CHAR, C.OCI_TYPECODE_VARCHAR:
+				if fv.Kind() != reflect.String {
+					return errF("Unable to set string attribute %v from %v field %v.", field.attr.name, fv.Type(), rv.Type().Field(field.index).Name)
+				}
+				value := fv.String()
+				cValue := C.CString(value)
+				var ociString *C.OCIString
+				r := C.OCIStringAssignText(
+					env.ocienv,                           //OCIEnv          *env,
+					env.ocierr,                           //OCIError        *err,
+					(*C.oratext)(unsafe.Pointer(cValue)), //const oratext   *rhs,
+					C.ub4(len(value)),                    //ub4             rhs_len,
+					&ociString)                           //OCIString       **lhs );
+				C.free(unsafe.Pointer(cValue))
+				if r == C.OCI_ERROR {
+					return env.ociError()
+				}
+				*ociStrings = append(*ociStrings, ociString)
+				attrValue = unsafe.Pointer(ociString)
+			case C.OCI_TYPECODE_DATE:
+				value, ok := fv.Interface().(time.Time)
+				if !ok {
+					return errF("Unable to set DATE attribute %v from %v field %v.", field.attr.name, fv.Type(), rv.Type().Field(field.index).Name)
+				}
+				ociDate := (*C.OCIDate)(C.malloc(C.sizeof_OCIDate))
+				defer C.free(unsafe.Pointer(ociDate))
+				ociDate.OCIDateYYYY = C.sb2(value.Year())
+				ociDate.OCIDateMM = C.ub1(value.Month())
+				ociDate.OCIDateDD = C.ub1(value.Day())
+				ociDate.OCIDateTime.OCITimeHH = C.ub1(value.Hour())
+				ociDate.OCIDateTime.OCITimeMI = C.ub1(value.Minute())
+				ociDate.OCIDateTime.OCITimeSS = C.ub1(value.Second())
+				attrValue = unsafe.Pointer(ociDate)
+			default:
+				return errF("Unsupported attribute %v of object type %v (typecode %v). Only NUMBER, VARCHAR2, CHAR, VARCHAR and DATE attributes are supported.", field.attr.name, typ.name, field.attr.typeCode)
+			}
+		}
+		cName := C.CString(field.attr.name)
+		r := C.objSetAttr(env.ocienv, env.ocierr, instance, nullStruct, typ.tdo,
+			cName, C.ub4(len(field.attr.name)), null, attrValue)
+		C.free(unsafe.Pointer(cName))
+		if r == C.OCI_ERROR {
+			return env.ociError()
+		}
+	}
+	return nil
+}
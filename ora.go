@@ -12,6 +12,7 @@ import "C"
 import (
 	"container/list"
 	"database/sql"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -36,7 +37,7 @@ func init() {
 
 	// init general pools
 	_drv.listPool = newPool(func() interface{} { return list.New() })
-	_drv.envPool = newPool(func() interface{} { return &Env{openSrvs: list.New(), openCons: list.New()} })
+	_drv.envPool = newPool(func() interface{} { return &Env{openSrvs: list.New(), openCons: list.New(), openPools: list.New()} })
 	_drv.conPool = newPool(func() interface{} { return &Con{} })
 	_drv.srvPool = newPool(func() interface{} { return &Srv{openSess: list.New()} })
 	_drv.sesPool = newPool(func() interface{} { return &Ses{openStmts: list.New(), openTxs: list.New()} })
@@ -94,8 +95,15 @@ func init() {
 	_drv.bndPools[bndIdxIntervalYMSlice] = newPool(func() interface{} { return &bndIntervalYMSlice{} })
 	_drv.bndPools[bndIdxIntervalDS] = newPool(func() interface{} { return &bndIntervalDS{} })
 	_drv.bndPools[bndIdxIntervalDSSlice] = newPool(func() interface{} { return &bndIntervalDSSlice{} })
+	_drv.bndPools[bndIdxBigInt] = newPool(func() interface{} { return &bndBigInt{} })
+	_drv.bndPools[bndIdxDecimal] = newPool(func() interface{} { return &bndDecimal{} })
+	_drv.bndPools[bndIdxAnyData] = newPool(func() interface{} { return &bndAnyData{} })
+	_drv.bndPools[bndIdxObject] = newPool(func() interface{} { return &bndObject{} })
+	_drv.bndPools[bndIdxColl] = newPool(func() interface{} { return &bndColl{} })
+	_drv.bndPools[bndIdxPlsArr] = newPool(func() interface{} { return &bndPlsArr{} })
 	_drv.bndPools[bndIdxRset] = newPool(func() interface{} { return &bndRset{} })
 	_drv.bndPools[bndIdxBfile] = newPool(func() interface{} { return &bndBfile{} })
+	_drv.bndPools[bndIdxRowidSlicePtr] = newPool(func() interface{} { return &bndRowidSlicePtr{} })
 	_drv.bndPools[bndIdxNil] = newPool(func() interface{} { return &bndNil{} })
 
 	// init def pools
@@ -116,9 +124,15 @@ func init() {
 	_drv.defPools[defIdxLob] = newPool(func() interface{} { return &defLob{} })
 	_drv.defPools[defIdxRaw] = newPool(func() interface{} { return &defRaw{} })
 	_drv.defPools[defIdxLongRaw] = newPool(func() interface{} { return &defLongRaw{} })
+	_drv.defPools[defIdxLong] = newPool(func() interface{} { return &defLong{} })
 	_drv.defPools[defIdxBfile] = newPool(func() interface{} { return &defBfile{} })
 	_drv.defPools[defIdxIntervalYM] = newPool(func() interface{} { return &defIntervalYM{} })
 	_drv.defPools[defIdxIntervalDS] = newPool(func() interface{} { return &defIntervalDS{} })
+	_drv.defPools[defIdxBigInt] = newPool(func() interface{} { return &defBigInt{} })
+	_drv.defPools[defIdxDecimal] = newPool(func() interface{} { return &defDecimal{} })
+	_drv.defPools[defIdxAnyData] = newPool(func() interface{} { return &defAnyData{} })
+	_drv.defPools[defIdxObject] = newPool(func() interface{} { return &defObject{} })
+	_drv.defPools[defIdxColl] = newPool(func() interface{} { return &defColl{} })
 	_drv.defPools[defIdxRowid] = newPool(func() interface{} { return &defRowid{} })
 }
 
@@ -159,34 +173,47 @@ func OpenEnv(cfg *EnvCfg) (env *Env, err error) {
 		tmp := *_drv.cfg.Env // copy by value to ensure independent cfgs
 		cfg = &tmp
 	}
-	var csIDAl32UTF8 C.ub2
-	if csIDAl32UTF8 == 0 { // Get the code for AL32UTF8
-		var ocienv *C.OCIEnv
-		r := C.OCIEnvCreate(&ocienv, C.OCI_DEFAULT|C.OCI_THREADED, nil, nil, nil, nil, 0, nil)
-		if r == C.OCI_ERROR {
-			return nil, errF("Unable to create environment handle (Return code = %d).", r)
+	for _, charset := range []string{cfg.Charset, cfg.NCharset} {
+		if charset != "" && !strings.EqualFold(charset, "AL32UTF8") && !strings.EqualFold(charset, "UTF8") {
+			return nil, errF("Unsupported character set (%v); Go strings require AL32UTF8 or UTF8.", charset)
 		}
-		csName := []byte("AL32UTF8\x00") // http://docs.oracle.com/cd/B10501_01/server.920/a96529/ch8.htm#14284
-		csIDAl32UTF8 = C.OCINlsCharSetNameToId(unsafe.Pointer(ocienv), (*C.oratext)(&csName[0]))
-		C.OCIHandleFree(unsafe.Pointer(ocienv), C.OCI_HTYPE_ENV)
+	}
+	// Get the codes for the character sets
+	var ocienv *C.OCIEnv
+	r := C.OCIEnvCreate(&ocienv, C.OCI_DEFAULT|C.OCI_THREADED, nil, nil, nil, nil, 0, nil)
+	if r == C.OCI_ERROR {
+		return nil, errF("Unable to create environment handle (Return code = %d).", r)
+	}
+	charsetID := func(charset string) C.ub2 {
+		if charset == "" {
+			charset = "AL32UTF8" // http://docs.oracle.com/cd/B10501_01/server.920/a96529/ch8.htm#14284
+		}
+		cCharset := C.CString(charset)
+		defer C.free(unsafe.Pointer(cCharset))
+		return C.OCINlsCharSetNameToId(unsafe.Pointer(ocienv), (*C.oratext)(unsafe.Pointer(cCharset)))
+	}
+	csID, ncsID := charsetID(cfg.Charset), charsetID(cfg.NCharset)
+	C.OCIHandleFree(unsafe.Pointer(ocienv), C.OCI_HTYPE_ENV)
+	if csID == 0 || ncsID == 0 {
+		return nil, errF("Unknown character sets (%v, %v).", cfg.Charset, cfg.NCharset)
 	}
 	// OCI_DEFAULT  - The default value, which is non-UTF-16 encoding.
 	// OCI_THREADED - Uses threaded environment. Internal data structures not exposed to the user are protected from concurrent accesses by multiple threads.
 	// OCI_OBJECT   - Uses object features such as OCINumber, OCINumberToInt, OCINumberFromInt. These are used in oracle-go type conversions.
 	env = _drv.envPool.Get().(*Env) // set *Env
-	r := C.OCIEnvNlsCreate(
+	r = C.OCIEnvNlsCreate(
 		&env.ocienv, //OCIEnv        **envhpp,
 		C.OCI_DEFAULT|C.OCI_OBJECT|C.OCI_THREADED, //ub4           mode,
-		nil,          //void          *ctxp,
-		nil,          //void          *(*malocfp)
-		nil,          //void          *(*ralocfp)
-		nil,          //void          (*mfreefp)
-		0,            //size_t        xtramemsz,
-		nil,          //void          **usrmempp
-		csIDAl32UTF8, //ub2           charset,
-		csIDAl32UTF8) //ub2           ncharset );
-	if r == C.OCI_ERROR {
-		return nil, errF("Unable to create environment handle (Return code = %d).", r)
+		nil,   //void          *ctxp,
+		nil,   //void          *(*malocfp)
+		nil,   //void          *(*ralocfp)
+		nil,   //void          (*mfreefp)
+		0,     //size_t        xtramemsz,
+		nil,   //void          **usrmempp
+		csID,  //ub2           charset,
+		ncsID) //ub2           ncharset );
+	if r == C.OCI_ERROR || r == C.OCI_INVALID_HANDLE {
+		return nil, errF("Unable to create environment handle with character sets (%v, %v) (Return code = %d).", csID, ncsID, r)
 	}
 	ocierr, err := env.allocOciHandle(C.OCI_HTYPE_ERROR) // alloc oci error handle
 	if err != nil {
@@ -209,6 +236,14 @@ func NumEnv() int {
 	return _drv.openEnvs.Len()
 }
 
+// ClientVersion returns the version of the Oracle client library the driver
+// is linked against. No connection is required.
+func ClientVersion() (major, minor, update, patch, portUpdate int) {
+	var maj, min, upd, pat, port C.sword
+	C.OCIClientVersion(&maj, &min, &upd, &pat, &port)
+	return int(maj), int(min), int(upd), int(pat), int(port)
+}
+
 // SetCfg applies the specified cfg to the ora database driver and any open Envs.
 func SetCfg(cfg DrvCfg) {
 	_drv.mu.Lock()
@@ -0,0 +1,367 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// PoolCfg configures a new Pool.
+type PoolCfg struct {
+	// Dblink specifies an Oracle database server. Dblink is a connect string
+	// or a service point.
+	Dblink string
+
+	// Username and Password authenticate every session of the Pool.
+	Username string
+	Password string
+
+	// Min is the number of sessions opened when the Pool is created and
+	// below which the Pool doesn't shrink.
+	Min uint32
+
+	// Max is the maximum number of sessions the Pool opens.
+	Max uint32
+
+	// Incr is the number of sessions opened when the Pool grows.
+	Incr uint32
+
+	// WaitTimeout determines how long Pool.Get waits for a session when all
+	// Max sessions are in use.
+	//
+	// Zero waits until a session is returned to the Pool. A positive
+	// WaitTimeout returns an error when no session is returned within the
+	// timeout; an Oracle 12.2 or later client is required. A negative
+	// WaitTimeout returns an error immediately.
+	WaitTimeout time.Duration
+
+	// StmtCfg configures new Stmts.
+	StmtCfg *StmtCfg
+
+	// PrepCacheSize, Rewrite and IsResultCached are applied to each Ses
+	// returned by Pool.Get as described for the SesCfg fields of the same
+	// names.
+	//
+	// The Oracle sessions of a Pool are shared, so the session settings of
+	// SesCfg which alter an Oracle session, such as TimeZone, Language,
+	// Territory and Edition, aren't supported by a Pool.
+	PrepCacheSize  int
+	Rewrite        func(sql string) string
+	IsResultCached bool
+}
+
+// NewPoolCfg creates a PoolCfg with default values.
+func NewPoolCfg() *PoolCfg {
+	c := &PoolCfg{}
+	c.Min = 1
+	c.Max = 4
+	c.Incr = 1
+	c.StmtCfg = NewStmtCfg()
+	return c
+}
+
+// LogPoolCfg represents Pool logging configuration values.
+type LogPoolCfg struct {
+	// Close determines whether the Pool.Close method is logged.
+	//
+	// The default is true.
+	Close bool
+
+	// Get determines whether the Pool.Get method is logged.
+	//
+	// The default is true.
+	Get bool
+
+	// Put determines whether the Pool.Put method is logged.
+	//
+	// The default is true.
+	Put bool
+}
+
+// NewLogPoolCfg creates a LogPoolCfg with default values.
+func NewLogPoolCfg() LogPoolCfg {
+	c := LogPoolCfg{}
+	c.Close = true
+	c.Get = true
+	c.Put = true
+	return c
+}
+
+// Pool represents an Oracle session pool.
+//
+// A Pool maintains homogeneous sessions, authenticated with the same username
+// and password, which are shared by many goroutines. Get a *Ses with Pool.Get
+// and return it with Pool.Put or Ses.Close. Pool is safe for concurrent use.
+//
+// Open a Pool with Env.OpenPool.
+type Pool struct {
+	id        uint64
+	cfg       PoolCfg
+	mu        sync.Mutex
+	env       *Env
+	ocispool  *C.OCISPool
+	name      *C.OraText
+	nameLen   C.ub4
+	dbCharset string
+
+	openSrvs  *list.List // Srvs of the sessions obtained from the Pool
+	elem      *list.Element
+	isClosing bool           // set by Close; Get returns an error
+	gets      sync.WaitGroup // Get calls using the OCI session pool
+}
+
+// Close destroys the Pool.
+//
+// Sessions obtained from the Pool and not yet returned are closed.
+//
+// Calling Close will cause Pool.IsOpen to return false. Once closed, a Pool
+// cannot be re-opened. Call Env.OpenPool to open a new Pool.
+func (p *Pool) Close() (err error) {
+	p.mu.Lock()
+	p.log(_drv.cfg.Log.Pool.Close)
+	err = p.checkClosed()
+	if err != nil {
+		p.mu.Unlock()
+		return errE(err)
+	}
+	p.isClosing = true
+	sess := make([]*Ses, 0, p.openSrvs.Len())
+	for e := p.openSrvs.Front(); e != nil; e = e.Next() {
+		if f := e.Value.(*Srv).openSess.Front(); f != nil {
+			sess = append(sess, f.Value.(*Ses))
+		}
+	}
+	p.mu.Unlock()
+
+	errs := _drv.listPool.Get().(*list.List)
+	defer func() {
+		if value := recover(); value != nil {
+			errs.PushBack(errR(value))
+		}
+		p.mu.Lock()
+		p.env.openPools.Remove(p.elem)
+		p.env = nil
+		p.ocispool = nil
+		p.name = nil
+		p.nameLen = 0
+		p.openSrvs.Init()
+		p.elem = nil
+		p.isClosing = false
+		p.mu.Unlock()
+
+		multiErr := newMultiErrL(errs)
+		if multiErr != nil {
+			err = errE(*multiErr)
+		}
+		errs.Init()
+		_drv.listPool.Put(errs)
+	}()
+
+	// return sessions to the pool; the sessions remove themselves from openSrvs
+	for _, ses := range sess {
+		err = ses.Close()
+		if err != nil {
+			errs.PushBack(errE(err))
+		}
+	}
+	// wait for Get calls in OCISessionGet, which may be waiting for the
+	// sessions just returned, before the OCI session pool is destroyed
+	p.gets.Wait()
+	// destroy pool
+	r := C.OCISessionPoolDestroy(
+		p.ocispool,      //OCISPool      *spoolhp,
+		p.env.ocierr,    //OCIError      *errhp,
+		C.OCI_SPD_FORCE) //ub4           mode );
+	if r == C.OCI_ERROR {
+		errs.PushBack(errE(p.env.ociError()))
+	}
+	err = p.env.freeOciHandle(unsafe.Pointer(p.ocispool), C.OCI_HTYPE_SPOOL)
+	if err != nil {
+		errs.PushBack(errE(err))
+	}
+	return nil
+}
+
+// Get returns a *Ses from the Pool.
+//
+// When all PoolCfg.Max sessions are in use, Get waits for a session to be
+// returned to the Pool as determined by PoolCfg.WaitTimeout.
+//
+// Return the Ses to the Pool with Pool.Put or Ses.Close once done.
+func (p *Pool) Get() (ses *Ses, err error) {
+	p.mu.Lock()
+	p.log(_drv.cfg.Log.Pool.Get)
+	err = p.checkClosed()
+	if err != nil {
+		p.mu.Unlock()
+		return nil, errE(err)
+	}
+	env, name, nameLen := p.env, p.name, p.nameLen
+	p.gets.Add(1)
+	p.mu.Unlock()
+
+	// get a session from the pool; the pool lock isn't held while waiting
+	// so that other goroutines may return sessions, and Close waits for the
+	// call before destroying the pool
+	var ocisvcctx *C.OCISvcCtx
+	r := C.OCISessionGet(
+		env.ocienv,          //OCIEnv           *envhp,
+		env.ocierr,          //OCIError         *errhp,
+		&ocisvcctx,          //OCISvcCtx        **svchp,
+		nil,                 //OCIAuthInfo      *authInfop,
+		name,                //OraText          *dbName,
+		nameLen,             //ub4              dbName_len,
+		nil,                 //const OraText    *tagInfo,
+		0,                   //ub4              tagInfo_len,
+		nil,                 //OraText          **retTagInfo,
+		nil,                 //ub4              *retTagInfo_len,
+		nil,                 //boolean          *found,
+		C.OCI_SESSGET_SPOOL) //ub4              mode );
+	if r == C.OCI_ERROR {
+		err = errE(env.ociError())
+		p.gets.Done()
+		return nil, err
+	}
+	var ocisrv unsafe.Pointer
+	var ocises unsafe.Pointer
+	err = env.attr(unsafe.Pointer(ocisvcctx), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&ocisrv), C.OCI_ATTR_SERVER)
+	if err == nil {
+		err = env.attr(unsafe.Pointer(ocisvcctx), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&ocises), C.OCI_ATTR_SESSION)
+	}
+	if err != nil {
+		C.OCISessionRelease(ocisvcctx, env.ocierr, nil, 0, C.OCI_DEFAULT)
+		p.gets.Done()
+		return nil, errE(err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.gets.Done()
+	// the Pool may have been closed while waiting
+	err = p.checkClosed()
+	if err != nil {
+		C.OCISessionRelease(ocisvcctx, env.ocierr, nil, 0, C.OCI_DEFAULT)
+		return nil, errE(err)
+	}
+	srv := _drv.srvPool.Get().(*Srv) // set *Srv
+	srv.env = env
+	srv.pool = p
+	srv.ocisrv = (*C.OCIServer)(ocisrv)
+	srv.ocisvcctx = ocisvcctx
+	srv.setDbCharset(p.dbCharset)
+	srv.elem = p.openSrvs.PushBack(srv)
+	if srv.id == 0 {
+		srv.id = _drv.srvId.nextId()
+	}
+	srv.cfg = SrvCfg{Dblink: p.cfg.Dblink, StmtCfg: p.cfg.StmtCfg}
+
+	ses = _drv.sesPool.Get().(*Ses) // set *Ses
+	ses.srv = srv
+	ses.ocises = (*C.OCISession)(ocises)
+	ses.elem = srv.openSess.PushBack(ses)
+	if ses.id == 0 {
+		ses.id = _drv.sesId.nextId()
+	}
+	ses.openedAt = time.Now()
+	ses.touch()
+	ses.cfg = SesCfg{
+		Username:       p.cfg.Username,
+		Password:       p.cfg.Password,
+		StmtCfg:        p.cfg.StmtCfg,
+		PrepCacheSize:  p.cfg.PrepCacheSize,
+		Rewrite:        p.cfg.Rewrite,
+		IsResultCached: p.cfg.IsResultCached,
+	}
+	return ses, nil
+}
+
+// Put returns a *Ses obtained from Pool.Get to the Pool.
+//
+// Put closes the Ses's open Stmts and Rsets, as Ses.Close does, and keeps the
+// Oracle session open for subsequent calls to Get.
+func (p *Pool) Put(ses *Ses) error {
+	p.log(_drv.cfg.Log.Pool.Put)
+	if ses == nil {
+		return er("Parameter 'ses' may not be nil.")
+	}
+	ses.mu.Lock()
+	isPooled := ses.srv != nil && ses.srv.pool == p
+	ses.mu.Unlock()
+	if !isPooled {
+		return er("Ses was not obtained from the Pool.")
+	}
+	return ses.Close()
+}
+
+// NumSes returns the number of sessions obtained from the Pool which haven't
+// been returned.
+func (p *Pool) NumSes() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.openSrvs.Len()
+}
+
+// IsOpen returns true when the Pool is open; otherwise, false.
+//
+// Calling Close will cause IsOpen to return false. Once closed, a Pool cannot
+// be re-opened. Call Env.OpenPool to open a new Pool.
+func (p *Pool) IsOpen() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.ocispool != nil
+}
+
+// release removes the Srv of a session returned to the Pool.
+func (p *Pool) release(srv *Srv) {
+	p.mu.Lock()
+	p.openSrvs.Remove(srv.elem)
+	p.mu.Unlock()
+	srv.openSess.Init()
+	srv.env = nil
+	srv.pool = nil
+	srv.ocisrv = nil
+	srv.ocisvcctx = nil
+	srv.setDbCharset("")
+	srv.callTimeout = 0
+	srv.elem = nil
+	_drv.srvPool.Put(srv)
+}
+
+// checkClosed returns an error if Pool is closed or closing. No locking
+// occurs.
+func (p *Pool) checkClosed() error {
+	if p == nil || p.ocispool == nil || p.isClosing {
+		return er("Pool is closed.")
+	}
+	return p.env.checkClosed()
+}
+
+// sysName returns a string representing the Pool.
+func (p *Pool) sysName() string {
+	if p == nil || p.env == nil {
+		return "E_P_"
+	}
+	return p.env.sysName() + fmt.Sprintf("P%v", p.id)
+}
+
+// log writes a message with a Pool system name and caller info.
+func (p *Pool) log(enabled bool, v ...interface{}) {
+	if enabled {
+		if len(v) == 0 {
+			_drv.cfg.Log.Logger.Infof("%v %v", p.sysName(), callInfo(1))
+		} else {
+			_drv.cfg.Log.Logger.Infof("%v %v %v", p.sysName(), callInfo(1), fmt.Sprint(v...))
+		}
+	}
+}
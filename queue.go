@@ -0,0 +1,286 @@
+// Copyright 2014 Rana Ian. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+
+// queueWaitForever is OCI_DEQ_WAIT_FOREVER, -1, as the ub4 of OCI_ATTR_WAIT.
+#define queueWaitForever ((ub4)OCI_DEQ_WAIT_FOREVER)
+
+// queueEnq enqueues a RAW payload; OCIAQEnq takes the addresses of the
+// payload and indicator pointers.
+static sword queueEnq(OCISvcCtx *svchp, OCIError *errhp, OraText *name,
+	OCIAQEnqOptions *enqopt, OCIAQMsgProperties *msgprop, OCIType *tdo,
+	OCIRaw *raw, OCIInd ind) {
+	OCIInd *indp = &ind;
+	return OCIAQEnq(svchp, errhp, name, enqopt, msgprop, tdo,
+		(void **)&raw, (void **)&indp, NULL, OCI_DEFAULT);
+}
+
+// queueDeq dequeues a RAW payload into an OCIRaw allocated by OCI.
+static sword queueDeq(OCISvcCtx *svchp, OCIError *errhp, OraText *name,
+	OCIAQDeqOptions *deqopt, OCIAQMsgProperties *msgprop, OCIType *tdo,
+	OCIRaw **raw, OCIInd *ind) {
+	OCIInd *indp = NULL;
+	sword r = OCIAQDeq(svchp, errhp, name, deqopt, msgprop, tdo,
+		(void **)raw, (void **)&indp, NULL, OCI_DEFAULT);
+	*ind = indp == NULL ? OCI_IND_NULL : *indp;
+	return r;
+}
+*/
+import "C"
+import (
+	"time"
+	"unsafe"
+)
+
+// deqTimeoutCode is the ORA-25228 error code returned when no message is
+// dequeued before the wait elapses.
+const deqTimeoutCode = 25228
+
+// DeqWaitForever is a DeqOptions.Wait which waits until a message arrives.
+const DeqWaitForever time.Duration = -1
+
+// EnqOptions are options of Queue.Enqueue.
+type EnqOptions struct {
+	// Visibility determines whether the message is enqueued as part of the
+	// session's transaction.
+	Visibility Visibility
+}
+
+// DeqOptions are options of Queue.Dequeue.
+type DeqOptions struct {
+	// Visibility determines whether the message is dequeued as part of the
+	// session's transaction.
+	Visibility Visibility
+
+	// Navigation determines which message is dequeued.
+	Navigation Navigation
+
+	// Wait is how long Dequeue waits for a message, rounded down to whole
+	// seconds. Zero doesn't wait and DeqWaitForever waits until a message
+	// arrives.
+	Wait time.Duration
+
+	// Consumer names the subscriber dequeuing from a multiple consumer
+	// queue. Leave it empty for a single consumer queue.
+	Consumer string
+}
+
+// Queue is an Oracle Advanced Queuing queue with a RAW payload type, created
+// with DBMS_AQADM.CREATE_QUEUE_TABLE(queue_payload_type => 'RAW').
+//
+// A Queue is opened with Ses.OpenQueue and is usable while its Ses is open.
+type Queue struct {
+	ses  *Ses
+	name string
+	tdo  *C.OCIType
+}
+
+// OpenQueue returns the Queue of the specified name, such as "SCOTT.ORDERS_Q".
+func (ses *Ses) OpenQueue(name string) (queue *Queue, err error) {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	ses.log(_drv.cfg.Log.Ses.OpenQueue, name)
+	err = ses.checkClosed()
+	if err != nil {
+		return nil, errE(err)
+	}
+	if name == "" {
+		return nil, er("Parameter 'name' may not be empty.")
+	}
+	tdo, err := ses.typeByName("SYS", "RAW")
+	if err != nil {
+		return nil, errE(err)
+	}
+	return &Queue{ses: ses, name: name, tdo: tdo}, nil
+}
+
+// Name returns the name of the Queue.
+func (queue *Queue) Name() string {
+	return queue.name
+}
+
+// Enqueue enqueues a message with the specified payload.
+//
+// With the default VisibilityOnCommit, the message is dequeuable once the
+// session's transaction commits.
+func (queue *Queue) Enqueue(payload []byte, opts EnqOptions) (err error) {
+	ses := queue.ses
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	ses.log(_drv.cfg.Log.Ses.Enqueue, queue.name)
+	err = ses.checkClosed()
+	if err != nil {
+		return errE(err)
+	}
+	env := ses.srv.env
+	enqOpts, err := queue.allocDescriptor(C.OCI_DTYPE_AQENQ_OPTIONS)
+	if err != nil {
+		return errE(err)
+	}
+	defer C.OCIDescriptorFree(enqOpts, C.OCI_DTYPE_AQENQ_OPTIONS)
+	visibility := C.ub4(C.OCI_ENQ_ON_COMMIT)
+	if opts.Visibility == VisibilityImmediate {
+		visibility = C.OCI_ENQ_IMMEDIATE
+	}
+	err = env.setAttr(enqOpts, C.OCI_DTYPE_AQENQ_OPTIONS, unsafe.Pointer(&visibility), 0, C.OCI_ATTR_VISIBILITY)
+	if err != nil {
+		return err
+	}
+	msgProps, err := queue.allocDescriptor(C.OCI_DTYPE_AQMSG_PROPERTIES)
+	if err != nil {
+		return errE(err)
+	}
+	defer C.OCIDescriptorFree(msgProps, C.OCI_DTYPE_AQMSG_PROPERTIES)
+
+	// an empty RAW is NULL
+	ind := C.OCIInd(C.OCI_IND_NOTNULL)
+	if len(payload) == 0 {
+		ind = C.OCI_IND_NULL
+	}
+	var empty C.ub1
+	rhs := &empty
+	if len(payload) > 0 {
+		rhs = (*C.ub1)(unsafe.Pointer(&payload[0]))
+	}
+	var raw *C.OCIRaw
+	r := C.OCIRawAssignBytes(
+		env.ocienv,          //OCIEnv          *env,
+		env.ocierr,          //OCIError        *err,
+		rhs,                 //const ub1       *rhs,
+		C.ub4(len(payload)), //ub4             rhs_len,
+		&raw)                //OCIRaw          **lhs );
+	if r == C.OCI_ERROR {
+		return errE(env.ociError())
+	}
+	defer C.OCIRawResize(env.ocienv, env.ocierr, 0, &raw)
+	cName := C.CString(queue.name)
+	defer C.free(unsafe.Pointer(cName))
+	r = C.queueEnq(
+		ses.srv.ocisvcctx,
+		env.ocierr,
+		(*C.OraText)(unsafe.Pointer(cName)),
+		(*C.OCIAQEnqOptions)(enqOpts),
+		(*C.OCIAQMsgProperties)(msgProps),
+		queue.tdo,
+		raw,
+		ind)
+	if r == C.OCI_ERROR {
+		return errE(env.ociError())
+	}
+	return nil
+}
+
+// Dequeue dequeues a message, returning its payload and true. Dequeue returns
+// false when no message arrives before DeqOptions.Wait elapses.
+//
+// With the default VisibilityOnCommit, the message is removed from the Queue
+// once the session's transaction commits.
+func (queue *Queue) Dequeue(opts DeqOptions) (payload []byte, ok bool, err error) {
+	ses := queue.ses
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	ses.log(_drv.cfg.Log.Ses.Dequeue, queue.name)
+	err = ses.checkClosed()
+	if err != nil {
+		return nil, false, errE(err)
+	}
+	env := ses.srv.env
+	deqOpts, err := queue.allocDescriptor(C.OCI_DTYPE_AQDEQ_OPTIONS)
+	if err != nil {
+		return nil, false, errE(err)
+	}
+	defer C.OCIDescriptorFree(deqOpts, C.OCI_DTYPE_AQDEQ_OPTIONS)
+	visibility := C.ub4(C.OCI_DEQ_ON_COMMIT)
+	if opts.Visibility == VisibilityImmediate {
+		visibility = C.OCI_DEQ_IMMEDIATE
+	}
+	err = env.setAttr(deqOpts, C.OCI_DTYPE_AQDEQ_OPTIONS, unsafe.Pointer(&visibility), 0, C.OCI_ATTR_VISIBILITY)
+	if err != nil {
+		return nil, false, err
+	}
+	navigation := C.ub4(C.OCI_DEQ_NEXT_MSG)
+	switch opts.Navigation {
+	case NavigationFirstMsg:
+		navigation = C.OCI_DEQ_FIRST_MSG
+	case NavigationNextTransaction:
+		navigation = C.OCI_DEQ_NEXT_TRANSACTION
+	}
+	err = env.setAttr(deqOpts, C.OCI_DTYPE_AQDEQ_OPTIONS, unsafe.Pointer(&navigation), 0, C.OCI_ATTR_NAVIGATION)
+	if err != nil {
+		return nil, false, err
+	}
+	wait := C.ub4(opts.Wait / time.Second)
+	if opts.Wait < 0 {
+		wait = C.queueWaitForever
+	}
+	err = env.setAttr(deqOpts, C.OCI_DTYPE_AQDEQ_OPTIONS, unsafe.Pointer(&wait), 0, C.OCI_ATTR_WAIT)
+	if err != nil {
+		return nil, false, err
+	}
+	if opts.Consumer != "" {
+		cConsumer := C.CString(opts.Consumer)
+		defer C.free(unsafe.Pointer(cConsumer))
+		err = env.setAttr(deqOpts, C.OCI_DTYPE_AQDEQ_OPTIONS, unsafe.Pointer(cConsumer), C.ub4(len(opts.Consumer)), C.OCI_ATTR_CONSUMER_NAME)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	msgProps, err := queue.allocDescriptor(C.OCI_DTYPE_AQMSG_PROPERTIES)
+	if err != nil {
+		return nil, false, errE(err)
+	}
+	defer C.OCIDescriptorFree(msgProps, C.OCI_DTYPE_AQMSG_PROPERTIES)
+
+	cName := C.CString(queue.name)
+	defer C.free(unsafe.Pointer(cName))
+	var raw *C.OCIRaw
+	var ind C.OCIInd
+	r := C.queueDeq(
+		ses.srv.ocisvcctx,
+		env.ocierr,
+		(*C.OraText)(unsafe.Pointer(cName)),
+		(*C.OCIAQDeqOptions)(deqOpts),
+		(*C.OCIAQMsgProperties)(msgProps),
+		queue.tdo,
+		&raw,
+		&ind)
+	if raw != nil {
+		defer C.OCIRawResize(env.ocienv, env.ocierr, 0, &raw)
+	}
+	if r == C.OCI_ERROR {
+		code, err := env.ociErrorCode()
+		if code == deqTimeoutCode {
+			return nil, false, nil
+		}
+		return nil, false, errE(err)
+	}
+	if ind == C.OCI_IND_NULL || raw == nil {
+		return nil, true, nil
+	}
+	payload = C.GoBytes(unsafe.Pointer(C.OCIRawPtr(env.ocienv, raw)), C.int(C.OCIRawSize(env.ocienv, raw)))
+	return payload, true, nil
+}
+
+// allocDescriptor allocates an AQ options or message properties descriptor.
+// No locking occurs.
+func (queue *Queue) allocDescriptor(descriptorType C.ub4) (unsafe.Pointer, error) {
+	var descriptor unsafe.Pointer
+	r := C.OCIDescriptorAlloc(
+		unsafe.Pointer(queue.ses.srv.env.ocienv), //CONST dvoid   *parenth,
+		&descriptor,                              //dvoid         **descpp,
+		descriptorType,                           //ub4           type,
+		0,                                        //size_t        xtramem_sz,
+		nil)                                      //dvoid         **usrmempp);
+	if r == C.OCI_ERROR {
+		return nil, queue.ses.srv.env.ociError()
+	} else if r == C.OCI_INVALID_HANDLE {
+		return nil, er("Unable to allocate oci descriptor.")
+	}
+	return descriptor, nil
+}
@@ -6,12 +6,18 @@ package ora
 
 /*
 #include <oci.h>
+#include "version.h"
 */
 import "C"
 import (
 	"container/list"
+	"context"
 	"fmt"
 	"io"
+	"math/big"
+	"reflect"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -37,6 +43,26 @@ type LogRsetCfg struct {
 	// The default is false.
 	Next bool
 
+	// Seek determines whether the Rset.Seek method is logged.
+	//
+	// The default is false.
+	Seek bool
+
+	// First determines whether the Rset.First method is logged.
+	//
+	// The default is false.
+	First bool
+
+	// Last determines whether the Rset.Last method is logged.
+	//
+	// The default is false.
+	Last bool
+
+	// Prev determines whether the Rset.Prev method is logged.
+	//
+	// The default is false.
+	Prev bool
+
 	// Open determines whether the Rset.open method is logged.
 	//
 	// The default is true.
@@ -46,6 +72,12 @@ type LogRsetCfg struct {
 	//
 	// The default is true.
 	OpenDefs bool
+
+	// Describe determines whether the Rset.describe method is logged when
+	// it describes the select-list rather than reusing a kept describe.
+	//
+	// The default is false.
+	Describe bool
 }
 
 // NewLogTxCfg creates a LogRsetCfg with default values.
@@ -55,8 +87,13 @@ func NewLogRsetCfg() LogRsetCfg {
 	c.BeginRow = false
 	c.EndRow = false
 	c.Next = false
+	c.Seek = false
+	c.First = false
+	c.Last = false
+	c.Prev = false
 	c.Open = true
 	c.OpenDefs = true
+	c.Describe = false
 	return c
 }
 
@@ -65,12 +102,23 @@ func NewLogRsetCfg() LogRsetCfg {
 // Opening and closing a Rset is managed internally. Rset doesn't have an Open
 // method or Close method.
 type Rset struct {
-	id        uint64
-	stmt      *Stmt
-	ocistmt   *C.OCIStmt
-	defs      []def
-	autoClose bool
-	genByPool bool
+	id          uint64
+	stmt        *Stmt
+	ocistmt     *C.OCIStmt
+	defs        []def
+	autoClose   bool
+	genByPool   bool
+	isRefCursor bool // owns a statement handle bound to a REF CURSOR parameter
+	mapKeys     []string
+	ctx         context.Context
+	busy        int32  // set atomically while fetching
+	fallbacks   []bool // columns returned as strings after a failed conversion
+	fetchRows   int    // rows per fetch call of an array fetch; zero fetches one row
+	bufRow      int    // current row of the array fetch buffers
+	bufRows     int    // rows held by the array fetch buffers
+	isFetchDone bool   // the last array fetch reached the end of the result set
+	isScroll    bool   // executed as a scrollable cursor
+	columns     []column
 
 	Row         []interface{}
 	ColumnNames []string
@@ -121,11 +169,27 @@ func (rset *Rset) close() (err error) {
 			}
 		}
 	}
+	if rset.isRefCursor {
+		err0 := rset.stmt.ses.srv.env.freeOciHandle(unsafe.Pointer(rset.ocistmt), C.OCI_HTYPE_STMT)
+		if err0 != nil {
+			errs.PushBack(err0)
+		}
+		rset.isRefCursor = false
+	}
 	rset.stmt = nil
 	rset.ocistmt = nil
 	rset.defs = nil
+	rset.fallbacks = nil
+	rset.fetchRows = 0
+	rset.bufRow = 0
+	rset.bufRows = 0
+	rset.isFetchDone = false
+	rset.isScroll = false
+	rset.columns = nil
 	rset.Row = nil
 	rset.ColumnNames = nil
+	rset.mapKeys = nil
+	rset.ctx = nil
 	// do not clear error in case of autoClose when error exists
 	// clear error when rset in initialized
 	//rset.Err = nil
@@ -138,9 +202,21 @@ func (rset *Rset) close() (err error) {
 	return err
 }
 
-// beginRow allocates a handle for each column and fetches one row.
+// beginRow allocates a handle for each column and fetches the next row.
 func (rset *Rset) beginRow() (err error) {
+	return rset.beginRowAt(C.OCI_FETCH_NEXT, 0)
+}
+
+// beginRowAt allocates a handle for each column and fetches the row at the
+// orientation and offset of a scrollable cursor.
+func (rset *Rset) beginRowAt(orientation C.ub2, offset C.sb4) (err error) {
 	rset.log(_drv.cfg.Log.Rset.BeginRow)
+	defer func() {
+		if err != nil && err != io.EOF {
+			rset.forgetDescribe()
+		}
+	}()
+	index := rset.Index
 	rset.Index++
 	// check is open
 	if rset.ocistmt == nil {
@@ -155,22 +231,72 @@ func (rset *Rset) beginRow() (err error) {
 			return err
 		}
 	}
+	if rset.fetchRows > 0 {
+		return rset.nextArrayRow()
+	}
 	// fetch one row
 	r := C.OCIStmtFetch2(
 		rset.ocistmt,                 //OCIStmt     *stmthp,
 		rset.stmt.ses.srv.env.ocierr, //OCIError    *errhp,
 		C.ub4(1),                     //ub4         nrows,
-		C.OCI_FETCH_NEXT,             //ub2         orientation,
-		C.sb4(0),                     //sb4         fetchOffset,
+		orientation,                  //ub2         orientation,
+		offset,                       //sb4         fetchOffset,
 		C.OCI_DEFAULT)                //ub4         mode );
 	if r == C.OCI_ERROR {
 		return rset.stmt.ses.srv.env.ociError()
 	} else if r == C.OCI_NO_DATA {
 		// Adjust Index so that Len() returns correct value when all rows read
-		rset.Index--
+		rset.Index = index
 		// return io.EOF to conform with database/sql/driver
 		return io.EOF
 	}
+	if rset.isScroll {
+		// the current position is 1-based
+		var position C.ub4
+		if err := rset.attr(unsafe.Pointer(&position), 4, C.OCI_ATTR_CURRENT_POSITION); err != nil {
+			return err
+		}
+		rset.Index = int(position) - 1
+	}
+	return nil
+}
+
+// nextArrayRow loads the next row from the array fetch buffers, fetching the
+// next batch of rows when the buffers are exhausted.
+func (rset *Rset) nextArrayRow() error {
+	rset.bufRow++
+	if rset.bufRow >= rset.bufRows {
+		if rset.isFetchDone {
+			rset.Index--
+			return io.EOF
+		}
+		r := C.OCIStmtFetch2(
+			rset.ocistmt,                 //OCIStmt     *stmthp,
+			rset.stmt.ses.srv.env.ocierr, //OCIError    *errhp,
+			C.ub4(rset.fetchRows),        //ub4         nrows,
+			C.OCI_FETCH_NEXT,             //ub2         orientation,
+			C.sb4(0),                     //sb4         fetchOffset,
+			C.OCI_DEFAULT)                //ub4         mode );
+		if r == C.OCI_ERROR {
+			return rset.stmt.ses.srv.env.ociError()
+		}
+		// the final batch is partial; OCI returns OCI_NO_DATA with the rows
+		// fetched before the end of the result set
+		rset.isFetchDone = r == C.OCI_NO_DATA
+		var rows C.ub4
+		if err := rset.attr(unsafe.Pointer(&rows), 4, C.OCI_ATTR_ROWS_FETCHED); err != nil {
+			return err
+		}
+		if rows == 0 {
+			rset.isFetchDone = true
+			rset.Index--
+			return io.EOF
+		}
+		rset.bufRow, rset.bufRows = 0, int(rows)
+	}
+	for _, define := range rset.defs {
+		define.(arrayDef).setRow(rset.bufRow)
+	}
 	return nil
 }
 
@@ -189,8 +315,61 @@ func (rset *Rset) endRow() {
 // on each call to Next. Rset.Row is set to nil when Next returns false.
 //
 // When Next returns false check Rset.Err for any error that may have occured.
+// For an Rset returned by Stmt.QryContext, Next returns false once the context
+// is done and Rset.Err is set to the context's error.
+//
+// Next returns false, leaving Rset.Row and Rset.Err to the goroutine using
+// the Rset, when called while another goroutine is loading a row; the
+// concurrent use error is logged.
 func (rset *Rset) Next() bool {
 	rset.log(_drv.cfg.Log.Rset.Next)
+	return rset.load(C.OCI_FETCH_NEXT, 0)
+}
+
+// Seek loads the row at the zero-based index of a scrollable Rset, so that
+// Rset.Index is index when Seek returns true. False is returned when no row
+// is at index or an error occurs; check Rset.Err for the error.
+//
+// Set StmtCfg.IsScrollable before calling Stmt.Qry to open a scrollable Rset.
+func (rset *Rset) Seek(index int) bool {
+	rset.log(_drv.cfg.Log.Rset.Seek)
+	return rset.load(C.OCI_FETCH_ABSOLUTE, C.sb4(index+1))
+}
+
+// First loads the first row of a scrollable Rset.
+func (rset *Rset) First() bool {
+	rset.log(_drv.cfg.Log.Rset.First)
+	return rset.load(C.OCI_FETCH_FIRST, 0)
+}
+
+// Last loads the last row of a scrollable Rset. Rset.Len returns the number
+// of rows of the result set once Last returns true.
+func (rset *Rset) Last() bool {
+	rset.log(_drv.cfg.Log.Rset.Last)
+	return rset.load(C.OCI_FETCH_LAST, 0)
+}
+
+// Prev loads the row preceding the current row of a scrollable Rset.
+func (rset *Rset) Prev() bool {
+	rset.log(_drv.cfg.Log.Rset.Prev)
+	return rset.load(C.OCI_FETCH_PRIOR, 0)
+}
+
+// load fetches the row at the orientation and offset into Rset.Row.
+//
+// Rset fields are written only by the goroutine holding the busy flag; a
+// concurrent caller gets false and the logged error leaves the Rset as is.
+func (rset *Rset) load(orientation C.ub2, offset C.sb4) bool {
+	if !atomic.CompareAndSwapInt32(&rset.busy, 0, 1) {
+		er("Concurrent use of Rset.")
+		return false
+	}
+	defer atomic.StoreInt32(&rset.busy, 0)
+	if orientation != C.OCI_FETCH_NEXT && rset.IsOpen() && !rset.isScroll {
+		rset.Err = er("Rset is not scrollable; set StmtCfg.IsScrollable.")
+		rset.Row = nil
+		return false
+	}
 	if err := rset.checkIsOpen(); err != nil {
 		rset.Err = err
 		rset.Row = nil
@@ -199,12 +378,25 @@ func (rset *Rset) Next() bool {
 		}
 		return false
 	}
-	err := rset.beginRow()
+	if err := rset.checkCtx(); err != nil {
+		rset.Err = err
+		rset.Row = nil
+		if rset.autoClose {
+			rset.stmt.Close()
+		}
+		return false
+	}
+	err := rset.beginRowAt(orientation, offset)
 	defer rset.endRow()
 	if err != nil {
 		// io.EOF means no more data; return nil err
 		if err == io.EOF {
 			err = nil
+			if rset.isScroll { // a scrollable Rset remains usable after its end
+				rset.Err = nil
+				rset.Row = nil
+				return false
+			}
 		}
 		rset.Err = err
 		rset.Row = nil
@@ -215,7 +407,7 @@ func (rset *Rset) Next() bool {
 	}
 	// populate column values
 	for n, define := range rset.defs {
-		value, err := define.value()
+		value, err := rset.columnValue(n, define)
 		if err != nil {
 			rset.Err = err
 			rset.Row = nil
@@ -229,15 +421,307 @@ func (rset *Rset) Next() bool {
 	return true
 }
 
+// forgetDescribe discards the describe information kept by the Stmt after a
+// define or fetch error, which may be caused by a changed select-list, so that
+// the next execution describes the statement again.
+func (rset *Rset) forgetDescribe() {
+	if rset.stmt == nil || rset.ocistmt != rset.stmt.ocistmt {
+		return
+	}
+	rset.stmt.mu.Lock()
+	rset.stmt.columns, rset.stmt.columnsKey = nil, ""
+	rset.stmt.mu.Unlock()
+}
+
+// columnValue gets the Go value of column n of the current row, falling back
+// to a string when configured by RsetCfg.IsFallingBackToString.
+func (rset *Rset) columnValue(n int, define def) (value interface{}, err error) {
+	defer func() {
+		if err != nil {
+			rset.forgetDescribe()
+		}
+	}()
+	if rset.fallbacks != nil && rset.fallbacks[n] {
+		return define.(stringDef).stringValue()
+	}
+	value, err = define.value()
+	if err == nil || !rset.stmt.cfg.Rset.IsFallingBackToString {
+		return value, err
+	}
+	strDef, ok := define.(stringDef)
+	if !ok {
+		return value, err
+	}
+	value, strErr := strDef.stringValue()
+	if strErr != nil {
+		return nil, err
+	}
+	if rset.fallbacks == nil {
+		rset.fallbacks = make([]bool, len(rset.defs))
+	}
+	rset.fallbacks[n] = true
+	logWarnf("%v column %v (%v) falls back to string at row %v: %v",
+		rset.sysName(), n, rset.ColumnNames[n], rset.Index, err)
+	return value, nil
+}
+
+// scanType returns the Go type of values which columnValue gets for column n.
+func (rset *Rset) scanType(n int) reflect.Type {
+	if rset.fallbacks != nil && rset.fallbacks[n] {
+		return reflect.TypeOf("")
+	}
+	nullable := func(isNullable bool, value, nullableValue interface{}) reflect.Type {
+		if isNullable {
+			return reflect.TypeOf(nullableValue)
+		}
+		return reflect.TypeOf(value)
+	}
+	switch def := rset.defs[n].(type) {
+	case *defInt64:
+		return nullable(def.isNullable, int64(0), Int64{})
+	case *defInt32:
+		return nullable(def.isNullable, int32(0), Int32{})
+	case *defInt16:
+		return nullable(def.isNullable, int16(0), Int16{})
+	case *defInt8:
+		return nullable(def.isNullable, int8(0), Int8{})
+	case *defUint64:
+		return nullable(def.isNullable, uint64(0), Uint64{})
+	case *defUint32:
+		return nullable(def.isNullable, uint32(0), Uint32{})
+	case *defUint16:
+		return nullable(def.isNullable, uint16(0), Uint16{})
+	case *defUint8:
+		return nullable(def.isNullable, uint8(0), Uint8{})
+	case *defFloat64:
+		return nullable(def.isNullable, float64(0), Float64{})
+	case *defFloat32:
+		return nullable(def.isNullable, float32(0), Float32{})
+	case *defBigInt:
+		return reflect.TypeOf((*big.Int)(nil))
+	case *defDecimal:
+		return reflect.TypeOf(Decimal{})
+	case *defTime:
+		return nullable(def.isNullable, time.Time{}, Time{})
+	case *defString:
+		return nullable(def.isNullable, "", String{})
+	case *defRowid:
+		if def.gct == OraRowid {
+			return reflect.TypeOf(Rowid{})
+		}
+		return reflect.TypeOf("")
+	case *defBool:
+		return nullable(def.isNullable, false, Bool{})
+	case *defRaw:
+		return nullable(def.isNullable, []byte(nil), Raw{})
+	case *defLongRaw:
+		return nullable(def.isNullable, []byte(nil), Raw{})
+	case *defLong:
+		return nullable(def.isNullable, "", String{})
+	case *defIntervalYM:
+		return reflect.TypeOf(IntervalYM{})
+	case *defIntervalDS:
+		return reflect.TypeOf(IntervalDS{})
+	case *defBfile:
+		return reflect.TypeOf(Bfile{})
+	case *defAnyData:
+		return reflect.TypeOf(AnyData{})
+	case *defObject:
+		return reflect.PtrTo(def.structType)
+	case *defColl:
+		return reflect.TypeOf(Coll{})
+	case *defLob:
+		if rset.stmt.cfg.IsMaterializingLobs {
+			switch def.gct {
+			case OraS:
+				return reflect.TypeOf(String{})
+			case Bin:
+				return reflect.TypeOf([]byte(nil))
+			case OraBin:
+				return reflect.TypeOf(Raw{})
+			}
+			return reflect.TypeOf("")
+		}
+		if def.gct == Bin {
+			return reflect.TypeOf((*io.Reader)(nil)).Elem()
+		}
+		return reflect.TypeOf(Lob{})
+	}
+	return reflect.TypeOf((*interface{})(nil)).Elem()
+}
+
+// numberString gets the decimal text of an OCINumber as a String, or a string
+// for a non-nullable define.
+func (rset *Rset) numberString(number *C.OCINumber, null C.sb2, isNullable bool) (interface{}, error) {
+	if null < C.sb2(0) {
+		if isNullable {
+			return String{IsNull: true}, nil
+		}
+		return nil, nil
+	}
+	text, err := rset.stmt.ses.srv.env.numberToText(number)
+	if err != nil {
+		return nil, err
+	}
+	if isNullable {
+		return String{Value: text}, nil
+	}
+	return text, nil
+}
+
+// Column describes a select-list column of an Rset.
+type Column struct {
+	// Name is the column name or alias.
+	Name string
+
+	// Type is the Oracle type name, e.g. NUMBER, VARCHAR2, DATE or CLOB. An
+	// object type is named with its schema, e.g. SYS.ANYDATA.
+	Type string
+
+	// Size is the maximum size in bytes of the column's values.
+	Size int
+
+	// Precision and Scale are those of a NUMBER or FLOAT column; otherwise,
+	// zero. A NUMBER without a precision has a Precision of zero and a Scale
+	// of -127.
+	Precision int
+	Scale     int
+
+	// IsNullable reports whether the column may hold NULLs.
+	IsNullable bool
+}
+
+// Columns returns the description of each select-list column of the Rset.
+func (rset *Rset) Columns() []Column {
+	columns := make([]Column, len(rset.columns))
+	for n := range rset.columns {
+		c := &rset.columns[n]
+		columns[n] = Column{
+			Name:       c.name,
+			Type:       c.oraTypeName(),
+			Size:       int(c.size),
+			Precision:  int(c.precision),
+			Scale:      int(c.scale),
+			IsNullable: c.isNullable,
+		}
+	}
+	return columns
+}
+
+// CurrentRowid returns the rowid of the row most recently loaded by Next from
+// a SELECT ... FOR UPDATE statement.
+//
+// The rowid enables a positioned update or delete of the current row with
+// "where rowid = :1", the client-side equivalent of PL/SQL's WHERE CURRENT OF.
+// Prefetching is disabled for SELECT ... FOR UPDATE statements so that the
+// rowid corresponds to Rset.Row while the Rset is being read.
+func (rset *Rset) CurrentRowid() (string, error) {
+	if !atomic.CompareAndSwapInt32(&rset.busy, 0, 1) {
+		return "", er("Concurrent use of Rset.")
+	}
+	defer atomic.StoreInt32(&rset.busy, 0)
+	if err := rset.checkIsOpen(); err != nil {
+		return "", errE(err)
+	}
+	if rset.Row == nil {
+		return "", er("Rset has no current row.")
+	}
+	env := rset.stmt.ses.srv.env
+	var ocirowid unsafe.Pointer
+	r := C.OCIDescriptorAlloc(
+		unsafe.Pointer(env.ocienv), //CONST dvoid   *parenth,
+		&ocirowid,                  //dvoid         **descpp,
+		C.OCI_DTYPE_ROWID,          //ub4           type,
+		0,                          //size_t        xtramem_sz,
+		nil)                        //dvoid         **usrmempp);
+	if r == C.OCI_ERROR {
+		return "", errE(env.ociError())
+	} else if r == C.OCI_INVALID_HANDLE {
+		return "", er("Unable to allocate oci rowid handle.")
+	}
+	defer C.OCIDescriptorFree(ocirowid, C.OCI_DTYPE_ROWID)
+	// the statement handle writes the rowid of the row last fetched into the descriptor
+	err := rset.attr(ocirowid, 0, C.OCI_ATTR_ROWID)
+	if err != nil {
+		return "", errE(err)
+	}
+	var buf [4001]byte
+	bufLen := C.ub2(len(buf))
+	r = C.OCIRowidToChar(
+		(*C.OCIRowid)(ocirowid),            //OCIRowid    *rowidDesc,
+		(*C.OraText)(unsafe.Pointer(&buf)), //OraText     *outbfp,
+		&bufLen,                            //ub2         *outbflp,
+		env.ocierr)                         //OCIError    *errhp );
+	if r == C.OCI_ERROR {
+		return "", errE(env.ociError())
+	}
+	return string(buf[:bufLen]), nil
+}
+
+// checkCtx returns an error when the context of a Stmt.QryContext call is
+// done.
+func (rset *Rset) checkCtx() error {
+	if rset.ctx == nil {
+		return nil
+	}
+	return rset.ctx.Err()
+}
+
 // NextRow attempts to load a row from the Oracle buffer and return the row.
 // Nil is returned when there's no data.
 //
 // When NextRow returns nil check Rset.Err for any error that may have occured.
 func (rset *Rset) NextRow() []interface{} {
-	rset.Next()
+	if !rset.Next() {
+		return nil
+	}
 	return rset.Row
 }
 
+// NextMap attempts to load a row from the Oracle buffer and return the row as
+// a map of column name to value. Nil is returned when there's no data.
+//
+// Duplicate column names are made unique by suffixing an underscore and an
+// ordinal, for example a second C1 column is keyed as C1_2.
+//
+// When NextMap returns nil check Rset.Err for any error that may have occured.
+func (rset *Rset) NextMap() map[string]interface{} {
+	if !rset.Next() {
+		return nil
+	}
+	if rset.mapKeys == nil {
+		rset.mapKeys = uniqueColumnNames(rset.ColumnNames)
+	}
+	row := make(map[string]interface{}, len(rset.Row))
+	for n, value := range rset.Row {
+		row[rset.mapKeys[n]] = value
+	}
+	return row
+}
+
+// uniqueColumnNames returns column names with duplicates suffixed by an
+// ordinal.
+func uniqueColumnNames(columnNames []string) []string {
+	keys := make([]string, len(columnNames))
+	used := make(map[string]bool, len(columnNames))
+	for _, name := range columnNames {
+		used[name] = true
+	}
+	seen := make(map[string]bool, len(columnNames))
+	for n, name := range columnNames {
+		key := name
+		if seen[name] {
+			for ordinal := 2; used[key]; ordinal++ {
+				key = fmt.Sprintf("%v_%d", name, ordinal)
+			}
+			used[key] = true
+		}
+		seen[name] = true
+		keys[n] = key
+	}
+	return keys
+}
+
 // gets a define struct from a driver slice
 func (rset *Rset) getDef(idx int) interface{} {
 	return _drv.defPools[idx].Get()
@@ -255,83 +739,26 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 	rset.Index = -1
 	rset.Err = nil
 	rset.log(_drv.cfg.Log.Rset.Open) // call log after rset.stmt is set
-	// get the implcit select-list describe information; no server round-trip
-	r := C.OCIStmtExecute(
-		rset.stmt.ses.srv.ocisvcctx,  //OCISvcCtx           *svchp,
-		rset.ocistmt,                 //OCIStmt             *stmtp,
-		rset.stmt.ses.srv.env.ocierr, //OCIError            *errhp,
-		C.ub4(1),                     //ub4                 iters,
-		C.ub4(0),                     //ub4                 rowoff,
-		nil,                          //const OCISnapshot   *snap_in,
-		nil,                          //OCISnapshot         *snap_out,
-		C.OCI_DESCRIBE_ONLY)          //ub4                 mode );
-	if r == C.OCI_ERROR {
-		return rset.stmt.ses.srv.env.ociError()
-	}
-	// get the parameter count
-	var paramCount C.ub4
-	err := rset.attr(unsafe.Pointer(&paramCount), 4, C.OCI_ATTR_PARAM_COUNT)
+	columns, err := rset.describe()
 	if err != nil {
 		return err
 	}
+	rset.columns = columns
 	// make defines slice
-	rset.defs = make([]def, int(paramCount))
-	rset.ColumnNames = make([]string, int(paramCount))
-	rset.Row = make([]interface{}, int(paramCount))
-	//fmt.Printf("rset.open (paramCount %v)\n", paramCount)
+	rset.defs = make([]def, len(columns))
+	rset.ColumnNames = make([]string, len(columns))
+	rset.Row = make([]interface{}, len(columns))
 
-	// create parameters for each select-list column
+	// create defines for each select-list column
 	var gct GoColumnType
 	for n := range rset.defs {
-		// Create oci parameter handle; may be freed by OCIDescriptorFree()
-		// parameter position is 1-based
-		var ocipar *C.OCIParam
-		r := C.OCIParamGet(
-			unsafe.Pointer(rset.ocistmt),               //const void        *hndlp,
-			C.OCI_HTYPE_STMT,                           //ub4               htype,
-			rset.stmt.ses.srv.env.ocierr,               //OCIError          *errhp,
-			(*unsafe.Pointer)(unsafe.Pointer(&ocipar)), //void              **parmdpp,
-			C.ub4(n+1))                                 //ub4               pos );
-		if r == C.OCI_ERROR {
-			return rset.stmt.ses.srv.env.ociError()
-		}
-		// Get column size in bytes
-		var columnSize uint32
-		err = rset.paramAttr(ocipar, unsafe.Pointer(&columnSize), 0, C.OCI_ATTR_DATA_SIZE)
-		if err != nil {
-			return err
-		}
-		// Get oci data type code
-		var ociTypeCode C.ub2
-		err = rset.paramAttr(ocipar, unsafe.Pointer(&ociTypeCode), 0, C.OCI_ATTR_DATA_TYPE)
-		if err != nil {
-			return err
-		}
-		// Get column name
-		var columnName *C.char
-		err := rset.paramAttr(ocipar, unsafe.Pointer(&columnName), 0, C.OCI_ATTR_NAME)
-		if err != nil {
-			return err
-		}
-		rset.ColumnNames[n] = C.GoString(columnName)
-		//fmt.Printf("Rset.open: ociTypeCode (%v)\n", ociTypeCode)
-		//Log.Infof("Rset.open: ociTypeCode=%d name=%s size=%d", ociTypeCode, rset.ColumnNames[n], columnSize)
-		//log(true, "ociTypeCode=", int(ociTypeCode), ", name=", rset.ColumnNames[n], ", size=", columnSize)
+		column := &columns[n]
+		columnSize, ociTypeCode := column.size, column.typeCode
+		rset.ColumnNames[n] = column.name
 		switch ociTypeCode {
 		case C.SQLT_NUM:
 			// NUMBER
-			// Get precision
-			var precision C.sb2
-			err = rset.paramAttr(ocipar, unsafe.Pointer(&precision), 0, C.OCI_ATTR_PRECISION)
-			if err != nil {
-				return err
-			}
-			// Get scale (the number of decimal places)
-			var numericScale C.sb1
-			err = rset.paramAttr(ocipar, unsafe.Pointer(&numericScale), 0, C.OCI_ATTR_SCALE)
-			if err != nil {
-				return err
-			}
+			precision, numericScale := column.precision, column.scale
 			// If the precision is nonzero and scale is -127, then it is a FLOAT;
 			// otherwise, it's a NUMBER(precision, scale).
 			if precision != 0 && (numericScale > 0 || numericScale == -127) {
@@ -348,13 +775,21 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 					}
 					gct = stmt.gcts[n]
 				}
-				err := rset.defineNumeric(n, gct)
+				if gct == Dec {
+					err = rset.defineDecimal(n, int(numericScale))
+				} else {
+					err = rset.defineNumeric(n, gct)
+				}
 				if err != nil {
 					return err
 				}
 			} else {
 				if stmt.gcts == nil || n >= len(stmt.gcts) || stmt.gcts[n] == D {
 					gct = rset.stmt.cfg.Rset.numberInt
+					// a system change number is unsigned
+					if rset.ColumnNames[n] == "ORA_ROWSCN" {
+						gct = U64
+					}
 				} else {
 					err = checkNumericColumn(stmt.gcts[n], rset.ColumnNames[n])
 					if err != nil {
@@ -417,6 +852,17 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 				}
 				gct = stmt.gcts[n]
 			}
+			if gct == S || gct == OraS {
+				// text formatted by the session's NLS_DATE_FORMAT or
+				// NLS_TIMESTAMP*_FORMAT
+				def := rset.getDef(defIdxString).(*defString)
+				rset.defs[n] = def
+				err = def.define(n+1, timeTextSize, gct == OraS, rset)
+				if err != nil {
+					return err
+				}
+				break
+			}
 			isNullable := false
 			if gct == OraT {
 				isNullable = true
@@ -501,8 +947,10 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 				gct = stmt.gcts[n]
 			}
 
-			// longBufferSize: Use a moderate default buffer size; 2GB max buffer may not be feasible on all clients
-			err = rset.defineString(n, stmt.cfg.longBufferSize, gct)
+			// fetched in pieces of longBufferSize bytes
+			def := rset.getDef(defIdxLong).(*defLong)
+			rset.defs[n] = def
+			err = def.define(n+1, stmt.cfg.longBufferSize, gct == OraS, rset)
 			if err != nil {
 				return err
 			}
@@ -517,15 +965,9 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 				}
 				gct = stmt.gcts[n]
 			}
-			// Get character set form
-			var charsetForm C.ub1
-			err = rset.paramAttr(ocipar, unsafe.Pointer(&charsetForm), 0, C.OCI_ATTR_CHARSET_FORM)
-			if err != nil {
-				return err
-			}
 			def := rset.getDef(defIdxLob).(*defLob)
 			rset.defs[n] = def
-			err = def.define(n+1, charsetForm, C.SQLT_CLOB, gct, rset)
+			err = def.define(n+1, column.charsetForm, C.SQLT_CLOB, gct, rset)
 			if err != nil {
 				return err
 			}
@@ -597,9 +1039,14 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 				return err
 			}
 		case C.SQLT_INTERVAL_DS:
+			if stmt.gcts == nil || n >= len(stmt.gcts) || stmt.gcts[n] != Dur {
+				gct = D
+			} else {
+				gct = Dur
+			}
 			def := rset.getDef(defIdxIntervalDS).(*defIntervalDS)
 			rset.defs[n] = def
-			err = def.define(n+1, rset)
+			err = def.define(n+1, gct, rset)
 			if err != nil {
 				return err
 			}
@@ -611,11 +1058,55 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 			if err != nil {
 				return err
 			}
+		case C.SQLT_NTY:
+			// SYS.ANYDATA, a VARRAY or nested table, or an object type mapped
+			// to a struct by AddObj
+			if column.schemaName == "SYS" && column.typeName == "ANYDATA" {
+				def := rset.getDef(defIdxAnyData).(*defAnyData)
+				rset.defs[n] = def
+				err = def.define(n+1, rset)
+				if err != nil {
+					return err
+				}
+				break
+			}
+			typ, err := stmt.ses.objType(column.schemaName + "." + column.typeName)
+			if err != nil {
+				return err
+			}
+			if typ.typeCode == C.OCI_TYPECODE_NAMEDCOLLECTION {
+				def := rset.getDef(defIdxColl).(*defColl)
+				rset.defs[n] = def
+				err = def.define(n+1, typ, rset)
+				if err != nil {
+					return err
+				}
+				break
+			}
+			structType, ok := objStructType(column.schemaName, column.typeName)
+			if !ok {
+				return errF("unsupported select-list column object type (%v.%v); map it to a struct with AddObj", column.schemaName, column.typeName)
+			}
+			def := rset.getDef(defIdxObject).(*defObject)
+			rset.defs[n] = def
+			err = def.define(n+1, typ, structType, rset)
+			if err != nil {
+				return err
+			}
 		case C.SQLT_RDD:
 			// ROWID, UROWID
+			if stmt.gcts == nil || n >= len(stmt.gcts) || stmt.gcts[n] == D {
+				gct = rset.stmt.cfg.Rset.rowid
+			} else {
+				err = checkRowidColumn(stmt.gcts[n])
+				if err != nil {
+					return err
+				}
+				gct = stmt.gcts[n]
+			}
 			def := rset.getDef(defIdxRowid).(*defRowid)
 			rset.defs[n] = def
-			err = def.define(n+1, rset)
+			err = def.define(n+1, gct, rset)
 			if err != nil {
 				return err
 			}
@@ -625,9 +1116,308 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 		}
 	}
 	rset.logF(_drv.cfg.Log.Rset.OpenDefs, "%#v", rset.defs)
+	return rset.defineArrays()
+}
+
+// column is the describe information of a select-list column.
+type column struct {
+	name        string
+	typeCode    C.ub2
+	size        uint32
+	precision   C.sb2 // NUMBER only
+	scale       C.sb1 // NUMBER only
+	charsetForm C.ub1 // character and CLOB types only
+	isNullable  bool
+	schemaName  string
+	typeName    string
+}
+
+// precisionScale returns the precision and scale of a NUMBER(p,s) column.
+func (column *column) precisionScale() (precision, scale int64, ok bool) {
+	if column.typeCode != C.SQLT_NUM || column.precision == 0 || column.scale == -127 {
+		return 0, 0, false
+	}
+	return int64(column.precision), int64(column.scale), true
+}
+
+// oraTypeName returns the Oracle type name of the column, such as NUMBER.
+func (column *column) oraTypeName() string {
+	nchar := column.charsetForm == C.SQLCS_NCHAR
+	switch column.typeCode {
+	case C.SQLT_NUM:
+		if column.precision != 0 && column.scale == -127 {
+			return "FLOAT"
+		}
+		return "NUMBER"
+	case C.SQLT_IBDOUBLE:
+		return "BINARY_DOUBLE"
+	case C.SQLT_IBFLOAT:
+		return "BINARY_FLOAT"
+	case C.SQLT_DAT:
+		return "DATE"
+	case C.SQLT_TIMESTAMP:
+		return "TIMESTAMP"
+	case C.SQLT_TIMESTAMP_TZ:
+		return "TIMESTAMP WITH TIME ZONE"
+	case C.SQLT_TIMESTAMP_LTZ:
+		return "TIMESTAMP WITH LOCAL TIME ZONE"
+	case C.SQLT_CHR:
+		if nchar {
+			return "NVARCHAR2"
+		}
+		return "VARCHAR2"
+	case C.SQLT_AFC:
+		if nchar {
+			return "NCHAR"
+		}
+		return "CHAR"
+	case C.SQLT_LNG:
+		return "LONG"
+	case C.SQLT_CLOB:
+		if nchar {
+			return "NCLOB"
+		}
+		return "CLOB"
+	case C.SQLT_BLOB:
+		return "BLOB"
+	case C.SQLT_BIN:
+		return "RAW"
+	case C.SQLT_LBI:
+		return "LONG RAW"
+	case C.SQLT_INTERVAL_YM:
+		return "INTERVAL YEAR TO MONTH"
+	case C.SQLT_INTERVAL_DS:
+		return "INTERVAL DAY TO SECOND"
+	case C.SQLT_FILE:
+		return "BFILE"
+	case C.SQLT_NTY:
+		return column.schemaName + "." + column.typeName
+	case C.SQLT_RDD:
+		return "ROWID"
+	}
+	return fmt.Sprintf("SQLT(%v)", column.typeCode)
+}
+
+// describe gets the describe information of the select-list columns.
+//
+// The describe information of a Stmt's own statement handle is kept by the
+// Stmt, so that executing the Stmt again with parameters of the same types
+// skips the describe. The kept describe is discarded when the executed
+// statement's columns differ from it, as after DDL on a queried table, and on
+// a define or fetch error. A REF CURSOR is described each time it's opened.
+func (rset *Rset) describe() ([]column, error) {
+	isStmtHandle := rset.ocistmt == rset.stmt.ocistmt
+	if isStmtHandle && rset.stmt.columns != nil {
+		isCurrent, err := rset.isDescribeCurrent(rset.stmt.columns)
+		if err != nil {
+			return nil, err
+		}
+		if isCurrent {
+			return rset.stmt.columns, nil
+		}
+		rset.stmt.columns = nil
+	}
+	rset.log(_drv.cfg.Log.Rset.Describe)
+	// get the implcit select-list describe information; no server round-trip
+	r := C.OCIStmtExecute(
+		rset.stmt.ses.srv.ocisvcctx,  //OCISvcCtx           *svchp,
+		rset.ocistmt,                 //OCIStmt             *stmtp,
+		rset.stmt.ses.srv.env.ocierr, //OCIError            *errhp,
+		C.ub4(1),                     //ub4                 iters,
+		C.ub4(0),                     //ub4                 rowoff,
+		nil,                          //const OCISnapshot   *snap_in,
+		nil,                          //OCISnapshot         *snap_out,
+		C.OCI_DESCRIBE_ONLY)          //ub4                 mode );
+	if r == C.OCI_ERROR {
+		return nil, rset.stmt.ses.srv.env.ociError()
+	}
+	// get the parameter count
+	var paramCount C.ub4
+	err := rset.attr(unsafe.Pointer(&paramCount), 4, C.OCI_ATTR_PARAM_COUNT)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]column, int(paramCount))
+	for n := range columns {
+		ocipar, err := rset.param(n)
+		if err != nil {
+			return nil, err
+		}
+		column := &columns[n]
+		// Get column size in bytes
+		err = rset.paramAttr(ocipar, unsafe.Pointer(&column.size), 0, C.OCI_ATTR_DATA_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		// Get oci data type code
+		err = rset.paramAttr(ocipar, unsafe.Pointer(&column.typeCode), 0, C.OCI_ATTR_DATA_TYPE)
+		if err != nil {
+			return nil, err
+		}
+		// Get column name
+		var columnName *C.char
+		err = rset.paramAttr(ocipar, unsafe.Pointer(&columnName), 0, C.OCI_ATTR_NAME)
+		if err != nil {
+			return nil, err
+		}
+		column.name = C.GoString(columnName)
+		// Get nullability
+		var isNull C.ub1
+		err = rset.paramAttr(ocipar, unsafe.Pointer(&isNull), 0, C.OCI_ATTR_IS_NULL)
+		if err != nil {
+			return nil, err
+		}
+		column.isNullable = isNull != 0
+		switch column.typeCode {
+		case C.SQLT_NUM:
+			// Get precision
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&column.precision), 0, C.OCI_ATTR_PRECISION)
+			if err != nil {
+				return nil, err
+			}
+			// Get scale (the number of decimal places)
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&column.scale), 0, C.OCI_ATTR_SCALE)
+			if err != nil {
+				return nil, err
+			}
+		case C.SQLT_CHR, C.SQLT_AFC, C.SQLT_CLOB:
+			// Get character set form
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&column.charsetForm), 0, C.OCI_ATTR_CHARSET_FORM)
+			if err != nil {
+				return nil, err
+			}
+		case C.SQLT_NTY:
+			var schemaName, typeName *C.char
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&schemaName), 0, C.OCI_ATTR_SCHEMA_NAME)
+			if err != nil {
+				return nil, err
+			}
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&typeName), 0, C.OCI_ATTR_TYPE_NAME)
+			if err != nil {
+				return nil, err
+			}
+			column.schemaName, column.typeName = C.GoString(schemaName), C.GoString(typeName)
+		}
+	}
+	if isStmtHandle {
+		rset.stmt.columns = columns
+	}
+	return columns, nil
+}
+
+// isDescribeCurrent returns true when the executed statement has the kept
+// columns, comparing the column count and each column's type, size, precision
+// and scale. The attributes are read from the describe the client received
+// with the execution; no server round-trip occurs.
+func (rset *Rset) isDescribeCurrent(columns []column) (bool, error) {
+	var paramCount C.ub4
+	err := rset.attr(unsafe.Pointer(&paramCount), 4, C.OCI_ATTR_PARAM_COUNT)
+	if err != nil {
+		return false, err
+	}
+	if int(paramCount) != len(columns) {
+		return false, nil
+	}
+	for n := range columns {
+		ocipar, err := rset.param(n)
+		if err != nil {
+			return false, err
+		}
+		var current column
+		err = rset.paramAttr(ocipar, unsafe.Pointer(&current.typeCode), 0, C.OCI_ATTR_DATA_TYPE)
+		if err != nil {
+			return false, err
+		}
+		err = rset.paramAttr(ocipar, unsafe.Pointer(&current.size), 0, C.OCI_ATTR_DATA_SIZE)
+		if err != nil {
+			return false, err
+		}
+		if current.typeCode != columns[n].typeCode || current.size != columns[n].size {
+			return false, nil
+		}
+		if current.typeCode == C.SQLT_NUM {
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&current.precision), 0, C.OCI_ATTR_PRECISION)
+			if err != nil {
+				return false, err
+			}
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&current.scale), 0, C.OCI_ATTR_SCALE)
+			if err != nil {
+				return false, err
+			}
+			if current.precision != columns[n].precision || current.scale != columns[n].scale {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// param gets the parameter descriptor of select-list column n, which is
+// zero-based.
+func (rset *Rset) param(n int) (*C.OCIParam, error) {
+	// Create oci parameter handle; may be freed by OCIDescriptorFree()
+	// parameter position is 1-based
+	var ocipar *C.OCIParam
+	r := C.OCIParamGet(
+		unsafe.Pointer(rset.ocistmt),               //const void        *hndlp,
+		C.OCI_HTYPE_STMT,                           //ub4               htype,
+		rset.stmt.ses.srv.env.ocierr,               //OCIError          *errhp,
+		(*unsafe.Pointer)(unsafe.Pointer(&ocipar)), //void              **parmdpp,
+		C.ub4(n+1))                                 //ub4               pos );
+	if r == C.OCI_ERROR {
+		return nil, rset.stmt.ses.srv.env.ociError()
+	}
+	return ocipar, nil
+}
+
+// defineArrays redefines the columns for an array fetch when configured by
+// StmtCfg.FetchArraySize and every column supports it.
+func (rset *Rset) defineArrays() error {
+	rows := int(rset.stmt.cfg.fetchArraySize)
+	if rows < 2 || rset.isScroll || isForUpdate(rset.stmt.sql) {
+		return nil
+	}
+	for _, define := range rset.defs {
+		if _, ok := define.(arrayDef); !ok {
+			return nil
+		}
+	}
+	for _, define := range rset.defs {
+		if err := define.(arrayDef).defineArray(rows); err != nil {
+			return err
+		}
+	}
+	rset.fetchRows = rows
+	rset.bufRow, rset.bufRows = -1, 0
 	return nil
 }
 
+// defineNumbers defines a number column with a buffer of rows OCINumbers,
+// reusing numbers and nulls when large enough.
+func (rset *Rset) defineNumbers(position int, ocidef **C.OCIDefine, rows int, numbers []C.OCINumber, nulls []C.sb2) ([]C.OCINumber, []C.sb2, error) {
+	if cap(numbers) < rows {
+		numbers = make([]C.OCINumber, rows)
+		nulls = make([]C.sb2, rows)
+	}
+	numbers, nulls = numbers[:rows], nulls[:rows]
+	r := C.OCIDEFINEBYPOS(
+		rset.ocistmt,                      //OCIStmt     *stmtp,
+		ocidef,                            //OCIDefine   **defnpp,
+		rset.stmt.ses.srv.env.ocierr,      //OCIError    *errhp,
+		C.ub4(position),                   //ub4         position,
+		unsafe.Pointer(&numbers[0]),       //void        *valuep,
+		C.LENGTH_TYPE(C.sizeof_OCINumber), //sb8         value_sz,
+		C.SQLT_VNU,                        //ub2         dty,
+		unsafe.Pointer(&nulls[0]),         //void        *indp,
+		nil,                               //ub2         *rlenp,
+		nil,                               //ub2         *rcodep,
+		C.OCI_DEFAULT)                     //ub4         mode );
+	if r == C.OCI_ERROR {
+		return numbers, nulls, rset.stmt.ses.srv.env.ociError()
+	}
+	return numbers, nulls, nil
+}
+
 func (rset *Rset) defineString(n int, columnSize uint32, gct GoColumnType) (err error) {
 	isNullable := false
 	if gct == OraS {
@@ -721,10 +1511,22 @@ func (rset *Rset) defineNumeric(n int, gct GoColumnType) (err error) {
 		def := rset.getDef(defIdxFloat32).(*defFloat32)
 		rset.defs[n] = def
 		err = def.define(n+1, true, rset)
+	case BigInt:
+		def := rset.getDef(defIdxBigInt).(*defBigInt)
+		rset.defs[n] = def
+		err = def.define(n+1, rset)
+	case Dec:
+		err = rset.defineDecimal(n, 0)
 	}
 	return err
 }
 
+func (rset *Rset) defineDecimal(n int, scale int) (err error) {
+	def := rset.getDef(defIdxDecimal).(*defDecimal)
+	rset.defs[n] = def
+	return def.define(n+1, scale, rset)
+}
+
 // paramAttr gets an attribute from the parameter handle.
 func (rset *Rset) paramAttr(ocipar *C.OCIParam, attrup unsafe.Pointer, attrSize C.ub4, attrType C.ub4) error {
 	r := C.OCIAttrGet(
@@ -24,11 +24,22 @@ type RsetCfg struct {
 	blob         GoColumnType
 	raw          GoColumnType
 	longRaw      GoColumnType
+	rowid        GoColumnType
 
 	// TrueRune is rune a Go bool true value from SQL select-list character column.
 	//
 	// The is default is '1'.
 	TrueRune rune
+
+	// IsFallingBackToString determines whether a NUMBER column whose value
+	// can't be converted to the column's Go type, such as a value out of the
+	// range of an int64, is returned as a string instead of failing the fetch.
+	// Once a value of a column falls back, the column's values are returned
+	// as a string, or an ora.String for a nullable type, for the remainder of
+	// the Rset; the fallback is logged at warning level.
+	//
+	// The default is false.
+	IsFallingBackToString bool
 }
 
 // NewRsetCfg returns a RsetCfg with default values.
@@ -51,6 +62,7 @@ func NewRsetCfg() RsetCfg {
 	c.blob = Bin
 	c.raw = Bin
 	c.longRaw = Bin
+	c.rowid = S
 
 	c.TrueRune = '1'
 	return c
@@ -60,7 +72,11 @@ func NewRsetCfg() RsetCfg {
 // NUMBER column defined with scale zero.
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
-// OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32.
+// OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32, BigInt,
+// Dec.
+//
+// Specify BigInt to fetch NUMBER(38) values beyond the range of int64
+// without loss of precision.
 //
 // Returns an error if a non-numeric GoColumnType is specified.
 func (c *RsetCfg) SetNumberInt(gct GoColumnType) (err error) {
@@ -89,7 +105,10 @@ func (c *RsetCfg) NumberInt() GoColumnType {
 // NUMBER column defined with a scale greater than zero.
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
-// OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32.
+// OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32, Dec.
+//
+// Specify Dec to fetch NUMBER values as exact decimal text retaining the
+// column scale, avoiding the rounding of a binary float.
 //
 // Returns an error if a non-numeric GoColumnType is specified.
 func (c *RsetCfg) SetNumberFloat(gct GoColumnType) (err error) {
@@ -206,9 +225,10 @@ func (c *RsetCfg) Float() GoColumnType {
 // SetDate sets a GoColumnType associated to an Oracle select-list
 // DATE column.
 //
-// Valid values are T and OraT.
+// Valid values are T, OraT, S and OraS. S and OraS fetch the text formatted
+// by the session's NLS date or timestamp format.
 //
-// Returns an error if a non-time GoColumnType is specified.
+// Returns an error if a non-time, non-string GoColumnType is specified.
 func (c *RsetCfg) SetDate(gct GoColumnType) (err error) {
 	err = checkTimeColumn(gct)
 	if err == nil {
@@ -234,9 +254,10 @@ func (c *RsetCfg) Date() GoColumnType {
 // SetTimestamp sets a GoColumnType associated to an Oracle select-list
 // TIMESTAMP column.
 //
-// Valid values are T and OraT.
+// Valid values are T, OraT, S and OraS. S and OraS fetch the text formatted
+// by the session's NLS date or timestamp format.
 //
-// Returns an error if a non-time GoColumnType is specified.
+// Returns an error if a non-time, non-string GoColumnType is specified.
 func (c *RsetCfg) SetTimestamp(gct GoColumnType) (err error) {
 	err = checkTimeColumn(gct)
 	if err == nil {
@@ -262,9 +283,10 @@ func (c *RsetCfg) Timestamp() GoColumnType {
 // SetTimestampTz sets a GoColumnType associated to an Oracle select-list
 // TIMESTAMP WITH TIME ZONE column.
 //
-// Valid values are T and OraT.
+// Valid values are T, OraT, S and OraS. S and OraS fetch the text formatted
+// by the session's NLS date or timestamp format.
 //
-// Returns an error if a non-time GoColumnType is specified.
+// Returns an error if a non-time, non-string GoColumnType is specified.
 func (c *RsetCfg) SetTimestampTz(gct GoColumnType) (err error) {
 	err = checkTimeColumn(gct)
 	if err == nil {
@@ -290,9 +312,10 @@ func (c *RsetCfg) TimestampTz() GoColumnType {
 // SetTimestampLtz sets a GoColumnType associated to an Oracle select-list
 // TIMESTAMP WITH LOCAL TIME ZONE column.
 //
-// Valid values are T and OraT.
+// Valid values are T, OraT, S and OraS. S and OraS fetch the text formatted
+// by the session's NLS date or timestamp format.
 //
-// Returns an error if a non-time GoColumnType is specified.
+// Returns an error if a non-time, non-string GoColumnType is specified.
 func (c *RsetCfg) SetTimestampLtz(gct GoColumnType) (err error) {
 	err = checkTimeColumn(gct)
 	if err == nil {
@@ -538,3 +561,29 @@ func (c *RsetCfg) SetLongRaw(gct GoColumnType) (err error) {
 func (c *RsetCfg) LongRaw() GoColumnType {
 	return c.longRaw
 }
+
+// SetRowid sets a GoColumnType associated to an Oracle select-list
+// ROWID column and UROWID column.
+//
+// Valid values are S and OraRowid.
+//
+// Returns an error if a non-rowid GoColumnType is specified.
+func (c *RsetCfg) SetRowid(gct GoColumnType) (err error) {
+	err = checkRowidColumn(gct)
+	if err == nil {
+		c.rowid = gct
+	}
+	return err
+}
+
+// Rowid returns a GoColumnType associated to an Oracle select-list
+// ROWID column and UROWID column.
+//
+// The default is S.
+//
+// When using the ora package directly, custom GoColumnType associations may
+// be specified to the Ses.Prep method. If no custom GoColumnType association
+// is specified, Rowid is used.
+func (c *RsetCfg) Rowid() GoColumnType {
+	return c.rowid
+}
@@ -12,16 +12,134 @@ import "C"
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
 type SesCfg struct {
+	// Username and Password authenticate the session. When both are empty
+	// the session is authenticated with external credentials, such as an
+	// operating system account or a wallet.
 	Username string
 	Password string
 	StmtCfg  *StmtCfg
+
+	// ProxyUsername names the user a session connects as through proxy
+	// authentication. Username and Password authenticate the proxy user,
+	// which the ProxyUsername user must permit with
+	// ALTER USER ... GRANT CONNECT THROUGH.
+	//
+	// The session's schema and privileges are those of the ProxyUsername user.
+	//
+	// The default is empty, which connects as Username.
+	ProxyUsername string
+
+	// TimeZone is the session time zone set when the session is opened, for
+	// example "+05:30" or "Europe/Paris".
+	//
+	// TIMESTAMP WITH LOCAL TIME ZONE values are converted to the session time
+	// zone when fetched and interpreted in it when bound without a zone.
+	//
+	// The default is empty, which keeps the time zone chosen by the Oracle
+	// client, usually derived from the ORA_SDTZ environment variable or the
+	// machine's local zone.
+	TimeZone string
+
+	// Language and Territory are the session NLS_LANGUAGE and NLS_TERRITORY
+	// set when the session is opened, for example "GERMAN" and "AUSTRIA".
+	//
+	// NLS_LANGUAGE determines the language of Oracle server error messages and
+	// of day and month names; NLS_TERRITORY determines default date and
+	// number formats.
+	//
+	// The default is empty, which keeps the setting chosen by the Oracle
+	// client, usually derived from the NLS_LANG environment variable.
+	Language  string
+	Territory string
+
+	// StmtCacheSize determines the number of statements kept in the OCI
+	// statement cache of the session.
+	//
+	// When greater than zero, Ses.Prep reuses a cached OCI statement handle
+	// prepared with identical sql text, and Ses.PrepKey one prepared with the
+	// same key, avoiding a parse on the Oracle server. Stmt.Close releases the
+	// handle back to the cache.
+	//
+	// The default is zero, which disables the OCI statement cache.
+	StmtCacheSize uint32
+
+	// PrepCacheSize determines the maximum number of Stmts cached by
+	// Ses.PrepCached.
+	//
+	// The default is zero, which disables caching.
+	PrepCacheSize int
+
+	// Rewrite, when not nil, rewrites the sql text of each statement before
+	// it's prepared by Ses.Prep, Ses.PrepKey and Ses.PrepCached, for example
+	// to add a hint, a tracing comment or a schema prefix. Rewrite must not use
+	// the Ses.
+	//
+	// The OCI statement cache and Ses.PrepCached match statements by the
+	// rewritten sql text. With Ses.PrepKey, a statement reused from the OCI
+	// statement cache keeps the sql text it was first prepared with.
+	//
+	// The default is nil, which prepares sql text unchanged.
+	Rewrite func(sql string) string
+
+	// IsResultCached determines whether SELECT statements prepared by the Ses
+	// are given a /*+ result_cache */ hint, so that the Oracle server, or the
+	// client result cache when configured with CLIENT_RESULT_CACHE_SIZE,
+	// reuses the results of repeated queries. See ResultCacheHint.
+	//
+	// The hint is added after Rewrite when the statement is prepared. The OCI
+	// statement cache and Ses.PrepCached match statements by the hinted sql
+	// text.
+	//
+	// The default is false.
+	IsResultCached bool
+
+	// ConnectionClass names the sessions of a Database Resident Connection
+	// Pool which may be shared, isolating the session state of different
+	// applications. See SrvCfg.IsDRCP.
+	//
+	// The default is empty, which uses a connection class generated by the
+	// Oracle client.
+	ConnectionClass string
+
+	// Purity determines whether a session from a Database Resident Connection
+	// Pool may retain the state of a previous use with the same
+	// ConnectionClass.
+	//
+	// The default is PurityDefault.
+	Purity Purity
+
+	// Privilege determines whether the session connects AS SYSDBA or
+	// AS SYSOPER for administrative tasks.
+	//
+	// The default is PrivilegeDefault.
+	Privilege Privilege
+
+	// NewPassword, when not empty, is the password set by Srv.OpenSes when
+	// the Password of the account has expired. The expired password is
+	// changed to NewPassword and the session is opened, rather than
+	// returning the ORA-28001 error detected by IsPasswordExpired.
+	//
+	// The default is empty.
+	NewPassword string
+
+	// Edition names the edition used by the session for Edition-Based
+	// Redefinition. The edition is set as each session opened with the SesCfg
+	// begins, so a session opened anew after a lost connection uses it too.
+	//
+	// The default is empty, which uses the database default edition or the
+	// edition of the service.
+	Edition string
 }
 
 // NewSrvCfg creates a SrvCfg with default values.
@@ -72,6 +190,75 @@ type LogSesCfg struct {
 	//
 	// The default is true.
 	StartTx bool
+
+	// Ping determines whether the Ses.Ping method is logged.
+	//
+	// The default is true.
+	Ping bool
+
+	// OpenBfile determines whether the Ses.OpenBfile method is logged.
+	//
+	// The default is true.
+	OpenBfile bool
+
+	// ChangePassword determines whether the Ses.ChangePassword method is
+	// logged.
+	//
+	// The default is true.
+	ChangePassword bool
+
+	// ExecDDL determines whether the Ses.ExecDDL method is logged.
+	//
+	// The default is true.
+	ExecDDL bool
+
+	// Savepoint determines whether the Ses.Savepoint method is logged.
+	//
+	// The default is true.
+	Savepoint bool
+
+	// RollbackTo determines whether the Ses.RollbackTo method is logged.
+	//
+	// The default is true.
+	RollbackTo bool
+
+	// ReleaseSavepoint determines whether the Ses.ReleaseSavepoint method is
+	// logged.
+	//
+	// The default is true.
+	ReleaseSavepoint bool
+
+	// SetIsolation determines whether the Ses.SetIsolation method is logged.
+	//
+	// The default is true.
+	SetIsolation bool
+
+	// StartGlobalTx determines whether the Ses.StartGlobalTx method is
+	// logged.
+	//
+	// The default is true.
+	StartGlobalTx bool
+
+	// OpenQueue determines whether the Ses.OpenQueue method is logged.
+	//
+	// The default is true.
+	OpenQueue bool
+
+	// Enqueue determines whether the Queue.Enqueue method is logged.
+	//
+	// The default is false.
+	Enqueue bool
+
+	// Dequeue determines whether the Queue.Dequeue method is logged.
+	//
+	// The default is false.
+	Dequeue bool
+
+	// ServerVersion determines whether the Ses.ServerVersion method is
+	// logged.
+	//
+	// The default is true.
+	ServerVersion bool
 }
 
 // NewLogSesCfg creates a LogSesCfg with default values.
@@ -85,17 +272,61 @@ func NewLogSesCfg() LogSesCfg {
 	c.Upd = true
 	c.Sel = true
 	c.StartTx = true
+	c.Ping = true
+	c.OpenBfile = true
+	c.ChangePassword = true
+	c.ExecDDL = true
+	c.Savepoint = true
+	c.RollbackTo = true
+	c.ReleaseSavepoint = true
+	c.SetIsolation = true
+	c.StartGlobalTx = true
+	c.OpenQueue = true
+	c.ServerVersion = true
 	return c
 }
 
+// SesInfo describes an open Ses for diagnostics.
+type SesInfo struct {
+	// Id identifies the Ses within the driver.
+	Id uint64
+	// Dblink and Username are those the session was opened with.
+	Dblink   string
+	Username string
+	// Opened is the time the session was opened.
+	Opened time.Time
+	// LastUsed is the time a statement of the session was last prepared,
+	// executed or queried.
+	LastUsed time.Time
+	// NumStmt is the number of open Stmts of the session.
+	NumStmt int
+	// NumTx is the number of open Txs of the session.
+	NumTx int
+	// IsPooled is true when the session was obtained from a Pool.
+	IsPooled bool
+}
+
 // Ses is an Oracle session associated with a server.
 type Ses struct {
+	lastUsed int64 // UnixNano; accessed atomically, first for 64-bit alignment
+	openedAt time.Time
+
 	id       uint64
 	cfg      SesCfg
 	mu       sync.Mutex
 	srv      *Srv
 	ocises   *C.OCISession
 	isLocked bool
+	consCols map[string][]string
+
+	isCollectingCallTime bool
+	isolation            Isolation
+
+	prepCacheMu sync.Mutex
+	prepCache   map[string]*Stmt
+
+	objTypesMu sync.Mutex
+	objTypes   map[string]*objType // described object types by name
 
 	openStmts *list.List
 	openTxs   *list.List
@@ -104,7 +335,8 @@ type Ses struct {
 
 // Close ends a session on an Oracle server.
 //
-// Any open statements associated with the session are closed.
+// Any open statements associated with the session are closed. A session
+// obtained from a Pool is returned to the Pool rather than ended.
 //
 // Calling Close will cause Ses.IsOpen to return false. Once closed, a session
 // cannot be re-opened. Call Srv.OpenSes to open a new session.
@@ -123,9 +355,16 @@ func (ses *Ses) Close() (err error) {
 		}
 		srv := ses.srv
 		srv.openSess.Remove(ses.elem)
+		if srv.pool != nil { // pooled session's Srv isn't reused
+			srv.pool.release(srv)
+		}
 		ses.srv = nil
 		ses.ocises = nil
 		ses.elem = nil
+		ses.consCols = nil
+		ses.isCollectingCallTime = false
+		ses.isolation = IsolationReadCommitted
+		ses.objTypes = nil
 		ses.openStmts.Init()
 		ses.openTxs.Init()
 		_drv.sesPool.Put(ses)
@@ -146,6 +385,13 @@ func (ses *Ses) Close() (err error) {
 	for e := ses.openTxs.Front(); e != nil; e = e.Next() {
 		e.Value.(*Tx).close()
 	}
+	// uncache statements so that they're closed below
+	ses.prepCacheMu.Lock()
+	for _, stmt := range ses.prepCache {
+		stmt.isCached = false
+	}
+	ses.prepCache = nil
+	ses.prepCacheMu.Unlock()
 	// close statements
 	for e := ses.openStmts.Front(); e != nil; e = e.Next() {
 		err = e.Value.(*Stmt).Close()
@@ -153,6 +399,19 @@ func (ses *Ses) Close() (err error) {
 			errs.PushBack(errE(err))
 		}
 	}
+	// return pooled session to its pool
+	if ses.srv.pool != nil {
+		r := C.OCISessionRelease(
+			ses.srv.ocisvcctx,  //OCISvcCtx       *svchp,
+			ses.srv.env.ocierr, //OCIError        *errhp,
+			nil,                //OraText         *tag,
+			0,                  //ub4             tag_len,
+			C.OCI_DEFAULT)      //ub4             mode );
+		if r == C.OCI_ERROR {
+			errs.PushBack(errE(ses.srv.env.ociError()))
+		}
+		return nil
+	}
 	// close session
 	// OCISessionEnd invalidates oci session handle; no need to free session.ocises
 	r := C.OCISessionEnd(
@@ -213,31 +472,156 @@ func (ses *Ses) PrepAndQry(sql string, params ...interface{}) (rset *Rset, err e
 	return rset, nil
 }
 
+// PrepAndQryContext prepares a sql statement and queries an Oracle server
+// returning an *Rset and a possible error, interrupting the query when ctx is
+// done.
+//
+// The *Rset observes ctx as described for Stmt.QryContext, and the statement
+// is closed when the *Rset is exhausted, fails or ctx is done.
+func (ses *Ses) PrepAndQryContext(ctx context.Context, sql string, params ...interface{}) (rset *Rset, err error) {
+	ses.log(_drv.cfg.Log.Ses.PrepAndQry)
+	err = ses.checkClosed()
+	if err != nil {
+		return nil, errE(err)
+	}
+	stmt, err := ses.Prep(sql)
+	if err != nil {
+		return nil, errE(err)
+	}
+	rset, err = stmt.QryContext(ctx, params...)
+	if err != nil {
+		stmt.Close()
+		return nil, err
+	}
+	rset.autoClose = true
+	return rset, nil
+}
+
 // Prep prepares a sql statement returning a *Stmt and possible error.
 func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 	ses.mu.Lock()
 	defer ses.mu.Unlock()
 	ses.log(_drv.cfg.Log.Ses.Prep, sql)
-	err = ses.checkClosed()
-	if err != nil {
-		return nil, errE(err)
+	return ses.prep("", ses.rewrite(sql), gcts)
+}
+
+// PrepKey prepares a sql statement tagged with a caller-supplied key,
+// returning a *Stmt and possible error.
+//
+// With the OCI statement cache enabled by SesCfg.StmtCacheSize, a statement
+// previously prepared with the same key is reused regardless of differences in
+// its sql text, such as whitespace or formatting. The key is applied when the
+// Stmt is closed and returned to the cache. Without the OCI statement cache the
+// key has no effect and PrepKey behaves as Prep.
+func (ses *Ses) PrepKey(key string, sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	ses.log(_drv.cfg.Log.Ses.Prep, key, ": ", sql)
+	return ses.prep(key, ses.rewrite(sql), gcts)
+}
+
+// rewrite applies SesCfg.Rewrite and SesCfg.IsResultCached to the sql text.
+// No locking occurs.
+func (ses *Ses) rewrite(sql string) string {
+	if ses.cfg.Rewrite != nil {
+		sql = ses.cfg.Rewrite(sql)
+	}
+	if ses.cfg.IsResultCached {
+		sql = ResultCacheHint(sql)
 	}
-	// allocate statement handle
-	upOciStmt, err := ses.srv.env.allocOciHandle(C.OCI_HTYPE_STMT)
+	return sql
+}
+
+// ResultCacheHint returns a SELECT statement with a /*+ result_cache */ hint
+// following its SELECT keyword. A result_cache hint is added to an existing
+// hint comment rather than adding a second comment, which Oracle would ignore.
+//
+// Other statements, a SELECT already hinted with result_cache, and a query
+// beginning with WITH or a parenthesis are returned unchanged. Leading
+// whitespace and comments are skipped.
+func ResultCacheHint(sql string) string {
+	n := skipLeadingComments(sql)
+	const selectKeyword = "SELECT"
+	if len(sql)-n < len(selectKeyword) || !strings.EqualFold(sql[n:n+len(selectKeyword)], selectKeyword) {
+		return sql
+	}
+	n += len(selectKeyword)
+	if n < len(sql) && isIdentChar(sql[n]) {
+		return sql // e.g. SELECTED
+	}
+	rest := strings.TrimLeft(sql[n:], " \t\n\r")
+	if strings.HasPrefix(rest, "/*+") {
+		end := strings.Index(rest, "*/")
+		if end < 0 {
+			return sql
+		}
+		if strings.Contains(strings.ToLower(rest[:end]), "result_cache") {
+			return sql
+		}
+		hint := len(sql) - len(rest) + len("/*+")
+		return sql[:hint] + " result_cache" + sql[hint:]
+	}
+	return sql[:n] + " /*+ result_cache */" + sql[n:]
+}
+
+// skipLeadingComments returns the index of the first sql text which isn't
+// whitespace or a comment, or len(sql) when there's none.
+func skipLeadingComments(sql string) int {
+	n := 0
+	for n < len(sql) {
+		switch {
+		case sql[n] == ' ' || sql[n] == '\t' || sql[n] == '\n' || sql[n] == '\r':
+			n++
+		case strings.HasPrefix(sql[n:], "--"):
+			end := strings.IndexByte(sql[n:], '\n')
+			if end < 0 {
+				return len(sql)
+			}
+			n += end + 1
+		case strings.HasPrefix(sql[n:], "/*"):
+			end := strings.Index(sql[n+2:], "*/")
+			if end < 0 {
+				return len(sql)
+			}
+			n += 2 + end + 2
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// isIdentChar reports whether c may be part of an unquoted Oracle identifier.
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c == '#' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// prep prepares a sql statement with an optional statement cache key. No
+// locking occurs.
+func (ses *Ses) prep(key string, sql string, gcts []GoColumnType) (stmt *Stmt, err error) {
+	err = ses.checkClosed()
 	if err != nil {
 		return nil, errE(err)
 	}
-	ocistmt := (*C.OCIStmt)(upOciStmt)
+	ses.touch()
+	// OCIStmtPrepare2 allocates the statement handle, or returns a cached
+	// statement handle when the OCI statement cache is enabled
+	var ocistmt *C.OCIStmt
 	cSql := C.CString(sql) // prepare sql text with statement handle
 	defer C.free(unsafe.Pointer(cSql))
+	var cKey *C.char
+	if key != "" {
+		cKey = C.CString(key)
+		defer C.free(unsafe.Pointer(cKey))
+	}
 	r := C.OCIStmtPrepare2(
 		ses.srv.ocisvcctx,                  // OCISvcCtx     *svchp,
 		&ocistmt,                           // OCIStmt       *stmtp,
 		ses.srv.env.ocierr,                 // OCIError      *errhp,
 		(*C.OraText)(unsafe.Pointer(cSql)), // const OraText *stmt,
 		C.ub4(len(sql)),                    // ub4           stmt_len,
-		nil,                                // const OraText *key,
-		C.ub4(0),                           // ub4           keylen,
+		(*C.OraText)(unsafe.Pointer(cKey)), // const OraText *key,
+		C.ub4(len(key)),                    // ub4           keylen,
 		C.OCI_NTV_SYNTAX,                   // ub4           language,
 		C.OCI_DEFAULT)                      // ub4           mode );
 	if r == C.OCI_ERROR {
@@ -253,6 +637,7 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 	}
 	stmt.cfg = *stmtCfg
 	stmt.sql = sql
+	stmt.key = key
 	stmt.gcts = gcts
 	stmt.elem = ses.openStmts.PushBack(stmt)
 	if stmt.id == 0 {
@@ -265,6 +650,122 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 	return stmt, nil
 }
 
+// PrepCached prepares a sql statement returning a *Stmt and possible error,
+// reusing a cached *Stmt prepared with identical sql when available.
+//
+// Up to SesCfg.PrepCacheSize Stmts are cached; when the size is zero
+// PrepCached behaves as Prep. Calling Close on a cached Stmt returns it to the
+// cache rather than closing it.
+//
+// A cached Stmt is used by one caller at a time. When the cached Stmt for the
+// sql is in use, a new Stmt is prepared and closed normally by Close.
+//
+// The specified gcts are applied to both new and reused Stmts. A reused Stmt
+// is configured by SesCfg.StmtCfg as a new Stmt is, so changes made through
+// Stmt.Cfg by a previous caller don't carry over.
+func (ses *Ses) PrepCached(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
+	ses.mu.Lock()
+	cacheSize := ses.cfg.PrepCacheSize
+	if cacheSize > 0 {
+		sql = ses.rewrite(sql) // cache by the rewritten sql text
+	}
+	stmtCfg := ses.cfg.StmtCfg
+	if stmtCfg == nil {
+		stmtCfg = NewStmtCfg()
+	}
+	ses.mu.Unlock()
+	if cacheSize <= 0 {
+		return ses.Prep(sql, gcts...)
+	}
+	ses.prepCacheMu.Lock()
+	if stmt = ses.prepCache[sql]; stmt != nil && !stmt.isInUse {
+		stmt.isInUse = true
+		ses.prepCacheMu.Unlock()
+		stmt.mu.Lock()
+		stmt.cfg = *stmtCfg
+		stmt.gcts = gcts
+		stmt.mu.Unlock()
+		return stmt, nil
+	}
+	ses.prepCacheMu.Unlock()
+
+	ses.mu.Lock()
+	ses.log(_drv.cfg.Log.Ses.Prep, sql)
+	stmt, err = ses.prep("", sql, gcts)
+	ses.mu.Unlock()
+	if err != nil {
+		return stmt, errE(err)
+	}
+	var evicted *Stmt
+	ses.prepCacheMu.Lock()
+	if _, ok := ses.prepCache[sql]; !ok {
+		if len(ses.prepCache) >= cacheSize { // evict an idle Stmt to make room
+			for key, cached := range ses.prepCache {
+				if !cached.isInUse {
+					cached.isCached = false
+					delete(ses.prepCache, key)
+					evicted = cached
+					break
+				}
+			}
+		}
+		if len(ses.prepCache) < cacheSize {
+			if ses.prepCache == nil {
+				ses.prepCache = make(map[string]*Stmt, cacheSize)
+			}
+			stmt.isCached = true
+			stmt.isInUse = true
+			ses.prepCache[sql] = stmt
+		}
+	}
+	ses.prepCacheMu.Unlock()
+	if evicted != nil {
+		evicted.Close()
+	}
+	return stmt, nil
+}
+
+// ClearPrepCache closes idle Stmts cached by Ses.PrepCached and empties the
+// cache. Cached Stmts in use are closed when their Close method is called.
+func (ses *Ses) ClearPrepCache() (err error) {
+	var idle []*Stmt
+	ses.prepCacheMu.Lock()
+	for _, stmt := range ses.prepCache {
+		stmt.isCached = false
+		if !stmt.isInUse {
+			idle = append(idle, stmt)
+		}
+	}
+	ses.prepCache = nil
+	ses.prepCacheMu.Unlock()
+	errs := _drv.listPool.Get().(*list.List)
+	defer func() {
+		errs.Init()
+		_drv.listPool.Put(errs)
+	}()
+	for _, stmt := range idle {
+		if err = stmt.Close(); err != nil {
+			errs.PushBack(errE(err))
+		}
+	}
+	if multiErr := newMultiErrL(errs); multiErr != nil {
+		return errE(*multiErr)
+	}
+	return nil
+}
+
+// releaseCached returns a cached Stmt to the cache, reporting whether the
+// Stmt is cached.
+func (ses *Ses) releaseCached(stmt *Stmt) bool {
+	ses.prepCacheMu.Lock()
+	defer ses.prepCacheMu.Unlock()
+	if !stmt.isCached {
+		return false
+	}
+	stmt.isInUse = false
+	return true
+}
+
 // Ins composes, prepares and executes a sql INSERT statement returning a
 // possible error.
 //
@@ -462,7 +963,291 @@ func (ses *Ses) Sel(sqlFrom string, columnPairs ...interface{}) (rset *Rset, err
 	return rset, nil
 }
 
+// ExecDDL executes a DDL statement such as CREATE, ALTER or DROP.
+//
+// Oracle commits the current transaction before a DDL statement runs, even
+// when the DDL statement fails. So that DML of an explicit transaction isn't
+// committed by accident, ExecDDL returns an error without executing the
+// statement while a Tx started with Ses.StartTx is open; commit or roll back
+// the Tx first. Uncommitted DML executed without a Tx is committed as usual.
+//
+// ExecDDL returns an error for a query or DML statement.
+func (ses *Ses) ExecDDL(sql string) (err error) {
+	ses.log(_drv.cfg.Log.Ses.ExecDDL)
+	err = ses.checkClosed()
+	if err != nil {
+		return errE(err)
+	}
+	if ses.NumTx() > 0 {
+		return er("DDL commits the open Tx implicitly; commit or roll back the Tx before calling ExecDDL.")
+	}
+	stmt, err := ses.Prep(sql)
+	if err != nil {
+		return errE(err)
+	}
+	defer stmt.Close()
+	switch stmt.stmtType {
+	case C.OCI_STMT_SELECT, C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE, C.OCI_STMT_INSERT, C.OCI_STMT_MERGE:
+		return er("ExecDDL requires a DDL statement.")
+	}
+	_, err = stmt.Exe()
+	if err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// Savepoint marks a point in the current transaction to which RollbackTo
+// rolls back, leaving earlier changes in place.
+//
+// Savepoints are set within a Tx started with Ses.StartTx, or on a session
+// whose statements aren't auto-committed; an auto-committed statement ends the
+// transaction and its savepoints. Setting a savepoint with the name of an
+// existing savepoint moves it.
+//
+// The name is an unquoted Oracle identifier: a letter followed by letters,
+// digits, '_', '$' or '#'. Other names are rejected.
+func (ses *Ses) Savepoint(name string) error {
+	ses.log(_drv.cfg.Log.Ses.Savepoint)
+	err := ses.exeSavepoint("SAVEPOINT ", name)
+	if err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// RollbackTo rolls back the changes of the current transaction made after the
+// named savepoint was set with Ses.Savepoint. The transaction remains open,
+// along with the named savepoint; later savepoints are erased.
+func (ses *Ses) RollbackTo(name string) error {
+	ses.log(_drv.cfg.Log.Ses.RollbackTo)
+	err := ses.exeSavepoint("ROLLBACK TO SAVEPOINT ", name)
+	if err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// ReleaseSavepoint validates the name of a savepoint set with Ses.Savepoint.
+//
+// Oracle has no statement releasing a savepoint; savepoints are erased when
+// the transaction is committed or rolled back. ReleaseSavepoint is provided
+// for symmetry with databases which release savepoints, and doesn't contact
+// the Oracle server.
+func (ses *Ses) ReleaseSavepoint(name string) error {
+	ses.log(_drv.cfg.Log.Ses.ReleaseSavepoint)
+	err := ses.checkClosed()
+	if err != nil {
+		return errE(err)
+	}
+	err = checkSavepointName(name)
+	if err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// exeSavepoint executes a savepoint statement without auto-committing.
+func (ses *Ses) exeSavepoint(sqlPrefix string, name string) error {
+	err := ses.checkClosed()
+	if err != nil {
+		return err
+	}
+	err = checkSavepointName(name)
+	if err != nil {
+		return err
+	}
+	stmt, err := ses.Prep(sqlPrefix + name)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	stmt.Cfg().IsAutoCommitting = false
+	_, err = stmt.Exe()
+	return err
+}
+
+// checkSavepointName returns an error unless name is an unquoted Oracle
+// identifier, so that it may be concatenated into a savepoint statement.
+func checkSavepointName(name string) error {
+	if name == "" || len(name) > 128 {
+		return errF("Invalid savepoint name (%q).", name)
+	}
+	for n, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case n > 0 && (c >= '0' && c <= '9' || c == '_' || c == '$' || c == '#'):
+		default:
+			return errF("Invalid savepoint name (%q).", name)
+		}
+	}
+	return nil
+}
+
+// EnableParallelDML enables parallel DML for the session.
+//
+// When degree is greater than zero, parallel DML is forced for the session
+// with the specified degree of parallelism; otherwise, DML statements run in
+// parallel only when hinted, for example with ParallelHint.
+//
+// A table modified with parallel DML may not be read or modified again in the
+// same transaction until the transaction is committed or rolled back.
+func (ses *Ses) EnableParallelDML(degree int) (err error) {
+	if degree > 0 {
+		_, err = ses.PrepAndExe(fmt.Sprintf("ALTER SESSION FORCE PARALLEL DML PARALLEL %d", degree))
+	} else {
+		_, err = ses.PrepAndExe("ALTER SESSION ENABLE PARALLEL DML")
+	}
+	if err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// DisableParallelDML disables parallel DML for the session.
+func (ses *Ses) DisableParallelDML() (err error) {
+	_, err = ses.PrepAndExe("ALTER SESSION DISABLE PARALLEL DML")
+	if err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// QryMaps runs a sql query returning all rows as maps of column name to value
+// and a possible error.
+//
+// Column names are keyed as described for Rset.NextMap.
+func (ses *Ses) QryMaps(sql string, params ...interface{}) (rows []map[string]interface{}, err error) {
+	rset, err := ses.PrepAndQry(sql, params...)
+	if err != nil {
+		return nil, errE(err)
+	}
+	for row := rset.NextMap(); row != nil; row = rset.NextMap() {
+		rows = append(rows, row)
+	}
+	if rset.Err != nil {
+		return nil, errE(rset.Err)
+	}
+	return rows, nil
+}
+
+// Exists executes a sql SELECT statement returning true when the statement
+// produces at least one row.
+//
+// The statement runs as written, so it may have an ORDER BY, a FOR UPDATE or a
+// trailing comment, and at most one row is fetched: prefetching is limited to a
+// single row. A trailing semicolon is removed.
+//
+// Returns an error if the specified sql is not a SELECT statement.
+func (ses *Ses) Exists(sql string, params ...interface{}) (exists bool, err error) {
+	trimmed := strings.TrimLeft(sql, " \t\r\n(")
+	if !hasPrefixFold(trimmed, "SELECT") && !hasPrefixFold(trimmed, "WITH") {
+		return false, errF("Exists requires a SELECT statement: %v", sql)
+	}
+	sql = strings.TrimRight(strings.TrimRight(sql, " \t\r\n"), ";")
+	stmt, err := ses.Prep(sql)
+	if err != nil {
+		return false, errE(err)
+	}
+	defer stmt.Close()
+	stmt.mu.Lock()
+	stmt.cfg.prefetchRowCount = 1
+	stmt.cfg.fetchArraySize = 1
+	stmt.mu.Unlock()
+	rset, err := stmt.Qry(params...)
+	if err != nil {
+		return false, errE(err)
+	}
+	exists = rset.Next()
+	if rset.Err != nil {
+		return false, errE(rset.Err)
+	}
+	return exists, nil
+}
+
+// hasPrefixFold reports whether s begins with prefix, ignoring case.
+func hasPrefixFold(s string, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// UniqueConstraintColumns returns the column names of the unique constraint
+// violated by the specified error.
+//
+// An ORA-00001 error names the violated constraint rather than the offending
+// columns. UniqueConstraintColumns extracts the constraint name from the error
+// and looks up its columns with Ses.ConstraintColumns.
+//
+// A nil slice and nil error are returned when err is not an ORA-00001 error.
+func (ses *Ses) UniqueConstraintColumns(err error) (columns []string, e error) {
+	oe, ok := err.(*Error)
+	if !ok || oe.Code != 1 {
+		return nil, nil
+	}
+	// ORA-00001: unique constraint (OWNER.CONSTRAINT_NAME) violated
+	msg := oe.Text
+	start := strings.IndexRune(msg, '(')
+	end := strings.IndexRune(msg, ')')
+	if start < 0 || end < start {
+		return nil, nil
+	}
+	names := strings.SplitN(msg[start+1:end], ".", 2)
+	if len(names) != 2 {
+		return nil, nil
+	}
+	columns, e = ses.ConstraintColumns(names[0], names[1])
+	if e != nil {
+		return nil, errE(e)
+	}
+	return columns, nil
+}
+
+// ConstraintColumns returns the column names of the specified constraint
+// ordered by position.
+//
+// Constraint columns are read from ALL_CONSTRAINTS and ALL_CONS_COLUMNS once
+// and cached for the lifetime of the Ses.
+func (ses *Ses) ConstraintColumns(owner string, constraintName string) (columns []string, err error) {
+	key := owner + "." + constraintName
+	ses.mu.Lock()
+	columns, ok := ses.consCols[key]
+	ses.mu.Unlock()
+	if ok {
+		return columns, nil
+	}
+	// the column is read as a string whatever the session's RsetCfg
+	stmt, err := ses.Prep(
+		"SELECT CC.COLUMN_NAME FROM ALL_CONSTRAINTS C "+
+			"JOIN ALL_CONS_COLUMNS CC ON CC.OWNER = C.OWNER AND CC.CONSTRAINT_NAME = C.CONSTRAINT_NAME "+
+			"WHERE C.OWNER = :1 AND C.CONSTRAINT_NAME = :2 ORDER BY CC.POSITION",
+		S)
+	if err != nil {
+		return nil, errE(err)
+	}
+	defer stmt.Close()
+	rset, err := stmt.Qry(owner, constraintName)
+	if err != nil {
+		return nil, errE(err)
+	}
+	for rset.Next() {
+		if column, ok := rset.Row[0].(string); ok {
+			columns = append(columns, column)
+		}
+	}
+	if rset.Err != nil {
+		return nil, errE(rset.Err)
+	}
+	ses.mu.Lock()
+	if ses.consCols == nil {
+		ses.consCols = make(map[string][]string)
+	}
+	ses.consCols[key] = columns
+	ses.mu.Unlock()
+	return columns, nil
+}
+
 // StartTx starts an Oracle transaction returning a *Tx and possible error.
+//
+// The transaction has the isolation set with Ses.SetIsolation.
 func (ses *Ses) StartTx() (tx *Tx, err error) {
 	ses.mu.Lock()
 	defer ses.mu.Unlock()
@@ -476,11 +1261,18 @@ func (ses *Ses) StartTx() (tx *Tx, err error) {
 	// before it is automatically terminated by the system.
 	// TODO: add timeout config value
 	var timeout C.uword = C.uword(60)
+	var flags C.ub4 = C.OCI_TRANS_NEW
+	switch ses.isolation {
+	case IsolationSerializable:
+		flags |= C.OCI_TRANS_SERIALIZABLE
+	case IsolationReadOnly:
+		flags |= C.OCI_TRANS_READONLY
+	}
 	r := C.OCITransStart(
 		ses.srv.ocisvcctx,  //OCISvcCtx    *svchp,
 		ses.srv.env.ocierr, //OCIError     *errhp,
 		timeout,            //uword        timeout,
-		C.OCI_TRANS_NEW)    //ub4          flags );
+		flags)              //ub4          flags );
 	if r == C.OCI_ERROR {
 		return nil, errE(ses.srv.env.ociError())
 	}
@@ -493,6 +1285,259 @@ func (ses *Ses) StartTx() (tx *Tx, err error) {
 	return tx, nil
 }
 
+// StartGlobalTx starts a branch of a distributed transaction identified by
+// xid, returning a *Tx and possible error.
+//
+// Coordinate a two-phase commit by calling Tx.Prepare on every branch and, once
+// all are prepared, Tx.Commit on every branch; or Tx.Rollback on every branch
+// should a Prepare fail. Tx.Commit of an unprepared branch commits in one
+// phase.
+func (ses *Ses) StartGlobalTx(xid Xid) (tx *Tx, err error) {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	ses.log(_drv.cfg.Log.Ses.StartGlobalTx)
+	err = ses.checkClosed()
+	if err != nil {
+		return nil, errE(err)
+	}
+	if len(xid.GlobalID) == 0 || len(xid.GlobalID) > 64 || len(xid.BranchID) > 64 {
+		return nil, er("Xid requires a GlobalID of 1 to 64 bytes and a BranchID of at most 64 bytes.")
+	}
+	var ocixid C.XID
+	ocixid.formatID = C.long(xid.FormatID)
+	ocixid.gtrid_length = C.long(len(xid.GlobalID))
+	ocixid.bqual_length = C.long(len(xid.BranchID))
+	data := (*[128]byte)(unsafe.Pointer(&ocixid.data[0]))
+	copy(data[copy(data[:], xid.GlobalID):], xid.BranchID)
+
+	env := ses.srv.env
+	ocitrans, err := env.allocOciHandle(C.OCI_HTYPE_TRANS)
+	if err != nil {
+		return nil, errE(err)
+	}
+	err = env.setAttr(ocitrans, C.OCI_HTYPE_TRANS, unsafe.Pointer(&ocixid), C.ub4(C.sizeof_XID), C.OCI_ATTR_XID)
+	if err == nil {
+		err = env.setAttr(unsafe.Pointer(ses.srv.ocisvcctx), C.OCI_HTYPE_SVCCTX, ocitrans, 0, C.OCI_ATTR_TRANS)
+	}
+	if err != nil {
+		env.freeOciHandle(ocitrans, C.OCI_HTYPE_TRANS)
+		return nil, errE(err)
+	}
+	var timeout C.uword = C.uword(60)
+	r := C.OCITransStart(
+		ses.srv.ocisvcctx,  //OCISvcCtx    *svchp,
+		ses.srv.env.ocierr, //OCIError     *errhp,
+		timeout,            //uword        timeout,
+		C.OCI_TRANS_NEW)    //ub4          flags );
+	if r == C.OCI_ERROR {
+		err = env.ociError()
+		env.setAttr(unsafe.Pointer(ses.srv.ocisvcctx), C.OCI_HTYPE_SVCCTX, nil, 0, C.OCI_ATTR_TRANS)
+		env.freeOciHandle(ocitrans, C.OCI_HTYPE_TRANS)
+		return nil, errE(err)
+	}
+	tx = _drv.txPool.Get().(*Tx) // set *Tx
+	tx.ses = ses
+	tx.ocitrans = (*C.OCITrans)(ocitrans)
+	tx.elem = ses.openTxs.PushFront(tx)
+	if tx.id == 0 {
+		tx.id = _drv.txId.nextId()
+	}
+	return tx, nil
+}
+
+// Edition returns the name of the edition used by the session.
+func (ses *Ses) Edition() (string, error) {
+	rset, err := ses.PrepAndQry("SELECT SYS_CONTEXT('USERENV', 'CURRENT_EDITION_NAME') FROM DUAL")
+	if err != nil {
+		return "", errE(err)
+	}
+	if !rset.Next() {
+		if rset.Err != nil {
+			return "", errE(rset.Err)
+		}
+		return "", er("Unable to read the current edition.")
+	}
+	edition, _ := rset.Row[0].(string)
+	for rset.Next() {
+	}
+	return edition, nil
+}
+
+// ServerVersion returns the numeric components of the Oracle database server
+// version of the session, as does Srv.VersionNum.
+func (ses *Ses) ServerVersion() (ver VersionNum, err error) {
+	ses.mu.Lock()
+	ses.log(_drv.cfg.Log.Ses.ServerVersion)
+	err = ses.checkClosed()
+	srv := ses.srv
+	ses.mu.Unlock()
+	if err != nil {
+		return ver, errE(err)
+	}
+	return srv.VersionNum()
+}
+
+// SetIsolation sets the isolation of transactions subsequently started by the
+// session.
+//
+// IsolationReadCommitted and IsolationSerializable apply to Txs started with
+// Ses.StartTx and to the implicit transactions of statements executed outside
+// a Tx. IsolationReadOnly applies to Txs started with Ses.StartTx; statements
+// executed outside a Tx keep the previous isolation.
+func (ses *Ses) SetIsolation(isolation Isolation) (err error) {
+	ses.log(_drv.cfg.Log.Ses.SetIsolation)
+	switch isolation {
+	case IsolationReadCommitted:
+		_, err = ses.PrepAndExe("ALTER SESSION SET ISOLATION_LEVEL = READ COMMITTED")
+	case IsolationSerializable:
+		_, err = ses.PrepAndExe("ALTER SESSION SET ISOLATION_LEVEL = SERIALIZABLE")
+	case IsolationReadOnly:
+		err = ses.checkClosed()
+	default:
+		return errF("Unknown isolation (%v).", isolation)
+	}
+	if err != nil {
+		return errE(err)
+	}
+	ses.mu.Lock()
+	ses.isolation = isolation
+	ses.mu.Unlock()
+	return nil
+}
+
+// Ping returns nil when the Oracle server of the session is contacted;
+// otherwise, an error.
+//
+// Ping makes a single round trip with OCIPing and is suited to checking the
+// liveness of a session before use.
+func (ses *Ses) Ping() (err error) {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	ses.log(_drv.cfg.Log.Ses.Ping)
+	err = ses.checkClosed()
+	if err != nil {
+		return errE(err)
+	}
+	r := C.OCIPing(
+		ses.srv.ocisvcctx,  //OCISvcCtx     *svchp,
+		ses.srv.env.ocierr, //OCIError      *errhp,
+		C.OCI_DEFAULT)      //ub4           mode );
+	if r == C.OCI_ERROR {
+		return errE(ses.srv.env.ociError())
+	}
+	return nil
+}
+
+// ChangePassword changes the password of the session's user from oldPassword
+// to newPassword.
+//
+// To open a session for an account whose password has expired, set
+// SesCfg.NewPassword.
+func (ses *Ses) ChangePassword(oldPassword, newPassword string) (err error) {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	ses.log(_drv.cfg.Log.Ses.ChangePassword)
+	err = ses.checkClosed()
+	if err != nil {
+		return errE(err)
+	}
+	err = ses.srv.changePassword(ses.cfg.Username, oldPassword, newPassword, C.OCI_DEFAULT)
+	if err != nil {
+		return errE(err)
+	}
+	ses.cfg.Password = newPassword
+	return nil
+}
+
+// IsPasswordExpired returns true when err is an ORA-28001 error returned when
+// opening a session for an account whose password has expired; otherwise,
+// false.
+func IsPasswordExpired(err error) bool {
+	if oe, ok := err.(*Error); ok {
+		return oe.Code == passwordExpiredCode
+	}
+	return err != nil && strings.Contains(err.Error(), "ORA-28001:")
+}
+
+// OpenBfile opens the file referenced by a Bfile, returning an io.ReadCloser
+// reading the file's contents.
+//
+// BFILEs are read-only; the file is opened with OCILobFileOpen in read-only
+// mode. Close the returned io.ReadCloser to close the file; reading to io.EOF
+// or an error also closes it.
+func (ses *Ses) OpenBfile(bfile Bfile) (rc io.ReadCloser, err error) {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	ses.log(_drv.cfg.Log.Ses.OpenBfile)
+	err = ses.checkClosed()
+	if err != nil {
+		return nil, errE(err)
+	}
+	if bfile.IsNull {
+		return nil, er("Unable to open a null Bfile.")
+	}
+	env := ses.srv.env
+	var ociLobLocator *C.OCILobLocator
+	r := C.OCIDescriptorAlloc(
+		unsafe.Pointer(env.ocienv),                        //CONST dvoid   *parenth,
+		(*unsafe.Pointer)(unsafe.Pointer(&ociLobLocator)), //dvoid         **descpp,
+		C.OCI_DTYPE_FILE,                                  //ub4           type,
+		0,                                                 //size_t        xtramem_sz,
+		nil)                                               //dvoid         **usrmempp);
+	if r == C.OCI_ERROR {
+		return nil, errE(env.ociError())
+	} else if r == C.OCI_INVALID_HANDLE {
+		return nil, er("Unable to allocate oci lob handle.")
+	}
+	defer func() {
+		if err != nil {
+			C.OCIDescriptorFree(unsafe.Pointer(ociLobLocator), C.OCI_DTYPE_FILE)
+		}
+	}()
+	cDirectoryAlias := C.CString(bfile.DirectoryAlias)
+	defer C.free(unsafe.Pointer(cDirectoryAlias))
+	cFilename := C.CString(bfile.Filename)
+	defer C.free(unsafe.Pointer(cFilename))
+	r = C.OCILobFileSetName(
+		env.ocienv,     //OCIEnv             *envhp,
+		env.ocierr,     //OCIError           *errhp,
+		&ociLobLocator, //OCILobLocator      **filepp,
+		(*C.OraText)(unsafe.Pointer(cDirectoryAlias)), //const OraText      *dir_alias,
+		C.ub2(len(bfile.DirectoryAlias)),              //ub2                d_length,
+		(*C.OraText)(unsafe.Pointer(cFilename)),       //const OraText      *filename,
+		C.ub2(len(bfile.Filename)))                    //ub2                f_length );
+	if r == C.OCI_ERROR {
+		return nil, errE(env.ociError())
+	}
+	r = C.OCILobFileOpen(
+		ses.srv.ocisvcctx,   //OCISvcCtx          *svchp,
+		env.ocierr,          //OCIError           *errhp,
+		ociLobLocator,       //OCILobLocator      *filep,
+		C.OCI_FILE_READONLY) //ub1                mode );
+	if r == C.OCI_ERROR {
+		return nil, errE(env.ociError())
+	}
+	var length C.oraub8
+	r = C.OCILobGetLength2(
+		ses.srv.ocisvcctx, //OCISvcCtx          *svchp,
+		env.ocierr,        //OCIError           *errhp,
+		ociLobLocator,     //OCILobLocator      *locp,
+		&length)           //oraub8             *lenp );
+	if r == C.OCI_ERROR {
+		err = errE(env.ociError())
+		C.OCILobFileClose(ses.srv.ocisvcctx, env.ocierr, ociLobLocator)
+		return nil, err
+	}
+	return &lobReader{
+		srv:           ses.srv,
+		ociLobLocator: ociLobLocator,
+		charsetForm:   C.SQLCS_IMPLICIT,
+		piece:         C.OCI_FIRST_PIECE,
+		Length:        length,
+		isFile:        true,
+	}, nil
+}
+
 // NumStmt returns the number of open Oracle statements.
 func (ses *Ses) NumStmt() int {
 	ses.mu.Lock()
@@ -516,6 +1561,37 @@ func (ses *Ses) SetCfg(cfg SesCfg) {
 	ses.cfg = cfg
 }
 
+// SetAutoCommit sets whether DML statements subsequently prepared by the
+// session are automatically committed.
+//
+// SetAutoCommit updates SesCfg.StmtCfg.IsAutoCommitting, so the setting is
+// kept by a session opened with the Ses's cfg. Open Stmts do not observe the
+// setting.
+func (ses *Ses) SetAutoCommit(isAutoCommitting bool) {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	stmtCfg := NewStmtCfg()
+	if ses.cfg.StmtCfg != nil {
+		*stmtCfg = *ses.cfg.StmtCfg // copy so that a shared StmtCfg is unchanged
+	}
+	stmtCfg.IsAutoCommitting = isAutoCommitting
+	ses.cfg.StmtCfg = stmtCfg
+}
+
+// AutoCommit returns true when DML statements prepared by the session are
+// executed with OCI_COMMIT_ON_SUCCESS; otherwise, false.
+//
+// AutoCommit returns false while a transaction is open, as automatic commits
+// are not observed during a transaction.
+func (ses *Ses) AutoCommit() bool {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	if ses.openTxs.Front() != nil {
+		return false
+	}
+	return ses.cfg.StmtCfg == nil || ses.cfg.StmtCfg.IsAutoCommitting
+}
+
 // Cfg returns the Ses's cfg.
 func (ses *Ses) Cfg() *SesCfg {
 	ses.mu.Lock()
@@ -533,6 +1609,97 @@ func (ses *Ses) IsOpen() bool {
 	return ses.checkClosed() == nil
 }
 
+// collectCallTime asks the Oracle server to measure the time spent on each
+// subsequent call of the session. No locking occurs.
+func (ses *Ses) collectCallTime() error {
+	if ses.isCollectingCallTime {
+		return nil
+	}
+	isCollecting := C.boolean(1)
+	r := C.OCIAttrSet(
+		unsafe.Pointer(ses.ocises),    //void        *trgthndlp,
+		C.OCI_HTYPE_SESSION,           //ub4         trghndltyp,
+		unsafe.Pointer(&isCollecting), //void        *attributep,
+		0,                             //ub4         size,
+		C.OCI_ATTR_COLLECT_CALL_TIME,  //ub4         attrtype,
+		ses.srv.env.ocierr)            //OCIError    *errhp );
+	if r == C.OCI_ERROR {
+		return ses.srv.env.ociError()
+	}
+	ses.isCollectingCallTime = true
+	return nil
+}
+
+// callTime returns the server time of the preceding call of the session as
+// measured by the Oracle server. No locking occurs.
+func (ses *Ses) callTime() (time.Duration, error) {
+	var microseconds C.ub8
+	var size C.ub4
+	r := C.OCIAttrGet(
+		unsafe.Pointer(ses.ocises),    //const void     *trgthndlp,
+		C.OCI_HTYPE_SESSION,           //ub4            trghndltyp,
+		unsafe.Pointer(&microseconds), //void           *attributep,
+		&size,                         //ub4            *sizep,
+		C.OCI_ATTR_CALL_TIME,          //ub4            attrtype,
+		ses.srv.env.ocierr)            //OCIError       *errhp );
+	if r == C.OCI_ERROR {
+		return 0, ses.srv.env.ociError()
+	}
+	return time.Duration(microseconds) * time.Microsecond, nil
+}
+
+// typeByName returns the type descriptor of a named Oracle object type such as
+// SYS.ANYDATA. The descriptor is pinned for the duration of the session. No
+// locking occurs.
+func (ses *Ses) typeByName(schema, name string) (*C.OCIType, error) {
+	cSchema := C.CString(schema)
+	defer C.free(unsafe.Pointer(cSchema))
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	var tdo *C.OCIType
+	r := C.OCITypeByName(
+		ses.srv.env.ocienv,                    //OCIEnv          *env,
+		ses.srv.env.ocierr,                    //OCIError        *err,
+		ses.srv.ocisvcctx,                     //const OCISvcCtx *svc,
+		(*C.oratext)(unsafe.Pointer(cSchema)), //const oratext   *schema_name,
+		C.ub4(len(schema)),                    //ub4             s_length,
+		(*C.oratext)(unsafe.Pointer(cName)),   //const oratext   *type_name,
+		C.ub4(len(name)),                      //ub4             t_length,
+		nil,                                   //const oratext   *version_name,
+		0,                                     //ub4             v_length,
+		C.OCI_DURATION_SESSION,                //OCIDuration     pin_duration,
+		C.OCI_TYPEGET_ALL,                     //OCITypeGetOpt   get_option,
+		&tdo)                                  //OCIType         **tdo );
+	if r == C.OCI_ERROR {
+		return nil, ses.srv.env.ociError()
+	}
+	return tdo, nil
+}
+
+// info returns a snapshot of the Ses and true, or false when the Ses is closed.
+func (ses *Ses) info() (SesInfo, bool) {
+	ses.mu.Lock()
+	defer ses.mu.Unlock()
+	if ses.checkClosed() != nil {
+		return SesInfo{}, false
+	}
+	return SesInfo{
+		Id:       ses.id,
+		Dblink:   ses.srv.cfg.Dblink,
+		Username: ses.cfg.Username,
+		Opened:   ses.openedAt,
+		LastUsed: time.Unix(0, atomic.LoadInt64(&ses.lastUsed)),
+		NumStmt:  ses.openStmts.Len(),
+		NumTx:    ses.openTxs.Len(),
+		IsPooled: ses.srv.pool != nil,
+	}, true
+}
+
+// touch records the time the Ses is used. No locking occurs.
+func (ses *Ses) touch() {
+	atomic.StoreInt64(&ses.lastUsed, time.Now().UnixNano())
+}
+
 // checkClosed returns an error if Ses is closed. No locking occurs.
 func (ses *Ses) checkClosed() error {
 	if ses == nil || ses.ocises == nil {
@@ -7,12 +7,28 @@ package ora
 /*
 #include <oci.h>
 #include <stdlib.h>
+#include "version.h"
+
+// serverRelease gets the server release with OCIServerRelease2 when the client
+// has it. From 18.1 OCIServerRelease reports only the base release, such as
+// 18.0.0.0.0, rather than the release update of the server.
+static sword serverRelease(void *hndlp, OCIError *errhp, OraText *bufp, ub4 bufsz,
+	ub1 hndltype, ub4 *version) {
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(18, 1)
+	return OCIServerRelease2(hndlp, errhp, bufp, bufsz, hndltype, version, OCI_DEFAULT);
+#else
+	return OCIServerRelease(hndlp, errhp, bufp, bufsz, hndltype, version);
+#endif
+}
 */
 import "C"
 import (
 	"container/list"
+	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -22,6 +38,19 @@ type SrvCfg struct {
 	// or a service point.
 	Dblink string
 
+	// IsDRCP determines whether the server is attached to a Database Resident
+	// Connection Pool, which shares server processes among sessions.
+	//
+	// When true, ":POOLED" is appended to an Easy Connect Dblink such as
+	// "dbhost:1521/orcl". A net service name or connect descriptor Dblink
+	// must specify (SERVER=POOLED) itself.
+	//
+	// Set SesCfg.ConnectionClass and SesCfg.Purity to control the reuse of
+	// pooled sessions.
+	//
+	// The default is false.
+	IsDRCP bool
+
 	// StmtCfg configures new Stmts.
 	StmtCfg *StmtCfg
 }
@@ -59,6 +88,18 @@ type LogSrvCfg struct {
 	//
 	// The default is true.
 	Break bool
+
+	// RegisterFailover determines whether the Srv.RegisterFailover method is
+	// logged.
+	//
+	// The default is true.
+	RegisterFailover bool
+
+	// Failover determines whether calls of a registered FailoverFunc are
+	// logged.
+	//
+	// The default is true.
+	Failover bool
 }
 
 // NewLogSrvCfg creates a LogSrvCfg with default values.
@@ -69,18 +110,23 @@ func NewLogSrvCfg() LogSrvCfg {
 	c.Ping = true
 	c.Version = true
 	c.Break = true
+	c.RegisterFailover = true
+	c.Failover = true
 	return c
 }
 
 // Srv represents an Oracle server.
 type Srv struct {
-	id        uint64
-	cfg       SrvCfg
-	mu        sync.Mutex
-	env       *Env
-	ocisvcctx *C.OCISvcCtx
-	ocisrv    *C.OCIServer
-	dbIsUTF8  bool
+	id          uint64
+	cfg         SrvCfg
+	mu          sync.Mutex
+	env         *Env
+	ocisvcctx   *C.OCISvcCtx
+	ocisrv      *C.OCIServer
+	dbCharset   string // NLS_CHARACTERSET of the database, once determined
+	dbIsUTF8    bool
+	callTimeout C.ub4 // OCI_ATTR_CALL_TIMEOUT last set on ocisvcctx
+	pool        *Pool // the Pool of a pooled session; otherwise, nil
 
 	openSess *list.List
 	elem     *list.Element
@@ -111,6 +157,8 @@ func (srv *Srv) Close() (err error) {
 		srv.env = nil
 		srv.ocisrv = nil
 		srv.ocisvcctx = nil
+		srv.setDbCharset("")
+		srv.callTimeout = 0
 		srv.elem = nil
 		_drv.srvPool.Put(srv)
 
@@ -129,6 +177,7 @@ func (srv *Srv) Close() (err error) {
 			errs.PushBack(errE(err))
 		}
 	}
+	srv.unregisterFailover()
 	// detach server
 	// OCIServerDetach invalidates oci server handle; no need to free server.ocisvr
 	// OCIServerDetach invalidates oci service context handle; no need to free server.ocisvcctx
@@ -163,9 +212,14 @@ func (srv *Srv) OpenSes(cfg *SesCfg) (ses *Ses, err error) {
 	if cfg.Username != "" || cfg.Password != "" {
 		credentialType = C.OCI_CRED_RDBMS
 		// set username on session handle
-		cUsername := C.CString(cfg.Username)
+		// a proxied user is specified with the proxy[client] username form
+		username := cfg.Username
+		if cfg.ProxyUsername != "" {
+			username += "[" + cfg.ProxyUsername + "]"
+		}
+		cUsername := C.CString(username)
 		defer C.free(unsafe.Pointer(cUsername))
-		err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(cUsername), C.ub4(len(cfg.Username)), C.OCI_ATTR_USERNAME)
+		err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(cUsername), C.ub4(len(username)), C.OCI_ATTR_USERNAME)
 		if err != nil {
 			return nil, errE(err)
 		}
@@ -176,6 +230,15 @@ func (srv *Srv) OpenSes(cfg *SesCfg) (ses *Ses, err error) {
 		if err != nil {
 			return nil, errE(err)
 		}
+	} else if cfg.ProxyUsername != "" {
+		// an externally authenticated proxy specifies the proxied user as [client]
+		username := "[" + cfg.ProxyUsername + "]"
+		cUsername := C.CString(username)
+		defer C.free(unsafe.Pointer(cUsername))
+		err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(cUsername), C.ub4(len(username)), C.OCI_ATTR_USERNAME)
+		if err != nil {
+			return nil, errE(err)
+		}
 	}
 	//srv.logF(true, "CRED_EXT? %t username=%q", credentialType == C.OCI_CRED_EXT, username)
 	// set driver name on the session handle
@@ -184,35 +247,87 @@ func (srv *Srv) OpenSes(cfg *SesCfg) (ses *Ses, err error) {
 	drvName := fmt.Sprintf("GO %v", Version)
 	cDrvName := C.CString(drvName)
 	defer C.free(unsafe.Pointer(cDrvName))
-	err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(cDrvName), C.ub4(len(drvName)), C.OCI_ATTR_DRIVER_NAME)
+	err = srv.env.setOptionalAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(cDrvName), C.ub4(len(drvName)), C.OCI_ATTR_DRIVER_NAME, "OCI_ATTR_DRIVER_NAME")
 	if err != nil {
 		return nil, errE(err)
 	}
 	// http://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci07lob.htm#CHDDHFAB
 	// Set LOB prefetch size to chunk size
 	lobPrefetchSize := C.ub4(lobChunkSize)
-	err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(&lobPrefetchSize), C.ub4(0), C.OCI_ATTR_DEFAULT_LOBPREFETCH_SIZE)
+	err = srv.env.setOptionalAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(&lobPrefetchSize), C.ub4(0), C.OCI_ATTR_DEFAULT_LOBPREFETCH_SIZE, "OCI_ATTR_DEFAULT_LOBPREFETCH_SIZE")
 	if err != nil {
 		return nil, errE(err)
 	}
-	// begin session
+	// set connection class and purity for Database Resident Connection Pooling
+	if cfg.ConnectionClass != "" {
+		cConnectionClass := C.CString(cfg.ConnectionClass)
+		defer C.free(unsafe.Pointer(cConnectionClass))
+		err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(cConnectionClass), C.ub4(len(cfg.ConnectionClass)), C.OCI_ATTR_CONNECTION_CLASS)
+		if err != nil {
+			return nil, errE(err)
+		}
+	}
+	if cfg.Purity != PurityDefault {
+		purity := C.ub4(C.OCI_ATTR_PURITY_NEW)
+		if cfg.Purity == PuritySelf {
+			purity = C.OCI_ATTR_PURITY_SELF
+		}
+		err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(&purity), C.ub4(0), C.OCI_ATTR_PURITY)
+		if err != nil {
+			return nil, errE(err)
+		}
+	}
+	// set edition for Edition-Based Redefinition
+	if cfg.Edition != "" {
+		cEdition := C.CString(cfg.Edition)
+		defer C.free(unsafe.Pointer(cEdition))
+		err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(cEdition), C.ub4(len(cfg.Edition)), C.OCI_ATTR_EDITION)
+		if err != nil {
+			return nil, errE(err)
+		}
+	}
+	// begin session; enable the OCI statement cache and privilege when requested
+	mode := C.ub4(C.OCI_DEFAULT)
+	if cfg.StmtCacheSize > 0 {
+		mode |= C.OCI_STMT_CACHE
+	}
+	switch cfg.Privilege {
+	case PrivilegeSysDBA:
+		mode |= C.OCI_SYSDBA
+	case PrivilegeSysOper:
+		mode |= C.OCI_SYSOPER
+	}
+	isPasswordChanged := false
 	r := C.OCISessionBegin(
 		srv.ocisvcctx,           //OCISvcCtx     *svchp,
 		srv.env.ocierr,          //OCIError      *errhp,
 		(*C.OCISession)(ocises), //OCISession    *usrhp,
 		credentialType,          //ub4           credt,
-		C.OCI_DEFAULT)           //ub4           mode );
+		mode)                    //ub4           mode );
 	if r == C.OCI_ERROR {
-		return nil, errE(srv.env.ociError())
+		code, err := srv.env.ociErrorCode()
+		if code != passwordExpiredCode || cfg.NewPassword == "" {
+			return nil, errE(err)
+		}
+		// change the expired password; OCI_AUTH begins the session
+		err = srv.env.setAttr(unsafe.Pointer(srv.ocisvcctx), C.OCI_HTYPE_SVCCTX, ocises, C.ub4(0), C.OCI_ATTR_SESSION)
+		if err != nil {
+			return nil, errE(err)
+		}
+		err = srv.changePassword(cfg.Username, cfg.Password, cfg.NewPassword, C.OCI_AUTH)
+		if err != nil {
+			return nil, errE(err)
+		}
+		isPasswordChanged = true
 	}
 	// set session handle on service context handle
 	err = srv.env.setAttr(unsafe.Pointer(srv.ocisvcctx), C.OCI_HTYPE_SVCCTX, ocises, C.ub4(0), C.OCI_ATTR_SESSION)
 	if err != nil {
 		return nil, errE(err)
 	}
-	// set stmt cache size to zero
+	// set stmt cache size; zero disables the cache
 	// https://docs.oracle.com/database/121/LNOCI/oci09adv.htm#LNOCI16655
-	stmtCacheSize := C.ub4(0)
+	stmtCacheSize := C.ub4(cfg.StmtCacheSize)
 	err = srv.env.setAttr(unsafe.Pointer(srv.ocisvcctx), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&stmtCacheSize), C.ub4(0), C.OCI_ATTR_STMTCACHESIZE)
 	if err != nil {
 		return nil, errE(err)
@@ -225,14 +340,102 @@ func (srv *Srv) OpenSes(cfg *SesCfg) (ses *Ses, err error) {
 	if ses.id == 0 {
 		ses.id = _drv.sesId.nextId()
 	}
+	ses.openedAt = time.Now()
+	ses.touch()
 	ses.cfg = *cfg
+	if isPasswordChanged {
+		ses.cfg.Password = cfg.NewPassword
+	}
+	ses.cfg.NewPassword = ""
 	if ses.cfg.StmtCfg == nil && ses.srv.cfg.StmtCfg != nil {
 		ses.cfg.StmtCfg = &(*ses.srv.cfg.StmtCfg) // copy by value so that user may change independently
 	}
+	if ses.cfg.TimeZone != "" { // set session time zone for TIMESTAMP WITH LOCAL TIME ZONE conversions
+		_, err = ses.PrepAndExe(fmt.Sprintf("ALTER SESSION SET TIME_ZONE = '%v'", strings.Replace(ses.cfg.TimeZone, "'", "''", -1)))
+		if err != nil {
+			ses.Close()
+			return nil, errE(err)
+		}
+	}
+	// NLS_LANGUAGE precedes NLS_TERRITORY as setting the language resets the
+	// territory to the language's default
+	for _, nls := range [...]struct{ param, value string }{
+		{"NLS_LANGUAGE", ses.cfg.Language},
+		{"NLS_TERRITORY", ses.cfg.Territory},
+	} {
+		if nls.value == "" {
+			continue
+		}
+		_, err = ses.PrepAndExe(fmt.Sprintf("ALTER SESSION SET %v = '%v'", nls.param, strings.Replace(nls.value, "'", "''", -1)))
+		if err != nil {
+			ses.Close()
+			return nil, errE(err)
+		}
+	}
+	srv.loadDbCharset(ses)
 
 	return ses, nil
 }
 
+// changePassword changes the password of a user with OCIPasswordChange. No
+// locking occurs.
+func (srv *Srv) changePassword(username, oldPassword, newPassword string, mode C.ub4) error {
+	cUsername := C.CString(username)
+	defer C.free(unsafe.Pointer(cUsername))
+	cOldPassword := C.CString(oldPassword)
+	defer C.free(unsafe.Pointer(cOldPassword))
+	cNewPassword := C.CString(newPassword)
+	defer C.free(unsafe.Pointer(cNewPassword))
+	r := C.OCIPasswordChange(
+		srv.ocisvcctx,                              //OCISvcCtx     *svchp,
+		srv.env.ocierr,                             //OCIError      *errhp,
+		(*C.OraText)(unsafe.Pointer(cUsername)),    //const OraText *user_name,
+		C.ub4(len(username)),                       //ub4           usernm_len,
+		(*C.OraText)(unsafe.Pointer(cOldPassword)), //const OraText *opasswd,
+		C.ub4(len(oldPassword)),                    //ub4           opasswd_len,
+		(*C.OraText)(unsafe.Pointer(cNewPassword)), //const OraText *npasswd,
+		C.ub4(len(newPassword)),                    //ub4           npasswd_len,
+		mode)                                       //ub4           mode );
+	if r == C.OCI_ERROR {
+		return errE(srv.env.ociError())
+	}
+	return nil
+}
+
+// OpenSesContext opens an Oracle session returning a *Ses and possible error,
+// abandoning the session begin when ctx is done.
+//
+// The session begin runs on a separate goroutine. When ctx is done before the
+// session begin completes, the blocked OCI call is interrupted with OCIBreak
+// and ctx.Err() is returned; a session which is opened regardless is closed
+// once the session begin returns.
+func (srv *Srv) OpenSesContext(ctx context.Context, cfg *SesCfg) (ses *Ses, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		ses *Ses
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ses, err := srv.OpenSes(cfg)
+		done <- result{ses: ses, err: err}
+	}()
+	select {
+	case res := <-done:
+		return res.ses, res.err
+	case <-ctx.Done():
+		srv.breakCall()
+		go func() { // clean up a session opened after cancellation
+			if res := <-done; res.ses != nil {
+				res.ses.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
 // Ping return nil when an Oracle server is contacted; otherwise, an error.
 //
 // Ping requires the server have at least one open session.
@@ -278,6 +481,33 @@ func (srv *Srv) Version() (ver string, err error) {
 	return C.GoString(&buf[0]), nil
 }
 
+// VersionNum returns the numeric components of the Oracle database server
+// version.
+//
+// VersionNum requires the server have at least one open session.
+func (srv *Srv) VersionNum() (ver VersionNum, err error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.log(_drv.cfg.Log.Srv.Version)
+	err = srv.checkClosed()
+	if err != nil {
+		return ver, errE(err)
+	}
+	var buf [512]C.char
+	var release C.ub4
+	r := C.serverRelease(
+		unsafe.Pointer(srv.ocisrv),            //void         *hndlp,
+		srv.env.ocierr,                        //OCIError     *errhp,
+		(*C.OraText)(unsafe.Pointer(&buf[0])), //OraText      *bufp,
+		C.ub4(len(buf)),                       //ub4          bufsz
+		C.OCI_HTYPE_SERVER,                    //ub1          hndltype
+		&release)                              //ub4          *version );
+	if r == C.OCI_ERROR {
+		return ver, errE(srv.env.ociError())
+	}
+	return newVersionNum(uint32(release)), nil
+}
+
 // Break the currently running OCI function.
 func (srv *Srv) Break() (err error) {
 	srv.mu.Lock()
@@ -287,13 +517,74 @@ func (srv *Srv) Break() (err error) {
 	if err != nil {
 		return errE(err)
 	}
+	err = srv.breakCall()
+	if err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// breakCall interrupts the OCI call currently running on the service context.
+//
+// OCIBreak is designed to be called while another OCI call is blocked on the
+// same service context. No locking occurs.
+func (srv *Srv) breakCall() error {
 	r := C.OCIBreak(unsafe.Pointer(srv.ocisvcctx), srv.env.ocierr)
 	if r == C.OCI_ERROR {
-		return errE(srv.env.ociError())
+		return srv.env.ociError()
+	}
+	return nil
+}
+
+// resetCall resets the service context after an OCI call was interrupted with
+// OCIBreak so that it may be used for subsequent calls. No locking occurs.
+func (srv *Srv) resetCall() error {
+	r := C.OCIReset(unsafe.Pointer(srv.ocisvcctx), srv.env.ocierr)
+	if r == C.OCI_ERROR {
+		return srv.env.ociError()
 	}
 	return nil
 }
 
+// DbCharset returns the database character set of the Srv, e.g. AL32UTF8,
+// as determined with the first session opened.
+//
+// DbCharset returns an empty string when no session has been opened or the
+// character set couldn't be determined.
+func (srv *Srv) DbCharset() string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.dbCharset
+}
+
+// loadDbCharset determines the database character set of the Srv with ses,
+// once per Srv. No locking of the Srv occurs.
+//
+// When the character set can't be determined, string buffers remain sized for
+// a non-AL32UTF8 database.
+func (srv *Srv) loadDbCharset(ses *Ses) {
+	if srv.dbCharset != "" {
+		return
+	}
+	rset, err := ses.PrepAndQry(`SELECT property_value FROM database_properties WHERE property_name = 'NLS_CHARACTERSET'`)
+	if err != nil {
+		return
+	}
+	if rset.Next() {
+		if charset, ok := rset.Row[0].(string); ok {
+			srv.setDbCharset(charset)
+		}
+	}
+	for rset.Next() {
+	}
+}
+
+// setDbCharset sets the database character set of the Srv. No locking occurs.
+func (srv *Srv) setDbCharset(charset string) {
+	srv.dbCharset = charset
+	srv.dbIsUTF8 = charset == "AL32UTF8"
+}
+
 // NumSes returns the number of open Oracle sessions.
 func (srv *Srv) NumSes() int {
 	srv.mu.Lock()
@@ -8,15 +8,25 @@ package ora
 #include <oci.h>
 #include <stdlib.h>
 #include "version.h"
+
+// OCI_ATTR_CALL_TIMEOUT is available from 18.1 client libraries; older
+// clients reject the attribute at run time.
+#ifndef OCI_ATTR_CALL_TIMEOUT
+#define OCI_ATTR_CALL_TIMEOUT 531
+#endif
 */
 import "C"
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"fmt"
+	"io"
+	"math/big"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
 )
@@ -63,9 +73,18 @@ type Stmt struct {
 	ocistmt    *C.OCIStmt
 	stmtType   C.ub4
 	sql        string
+	key        string
 	gcts       []GoColumnType
 	bnds       []bnd
+	bindNames  []string // placeholder names of Named parameters by position
+	columns    []column // select-list describe information of the statement
+	columnsKey string   // paramsKey of the execution which described columns
 	hasPtrBind bool
+	sqlID      string
+	serverTime time.Duration
+	isCached   bool  // guarded by ses.prepCacheMu
+	isInUse    bool  // guarded by ses.prepCacheMu
+	busy       int32 // set atomically while executing
 
 	openRsets *list.List
 	elem      *list.Element
@@ -83,6 +102,17 @@ func (stmt *Stmt) Close() (err error) {
 	if err != nil {
 		return errE(err)
 	}
+	if stmt.ses.releaseCached(stmt) { // return a cached statement to the Ses cache
+		for e := stmt.openRsets.Front(); e != nil; e = e.Next() {
+			e.Value.(*Rset).close()
+		}
+		stmt.openRsets.Init()
+		// the next caller binds its own parameters
+		if err = stmt.closeBnds(); err != nil {
+			return errE(err)
+		}
+		return nil
+	}
 	errs := _drv.listPool.Get().(*list.List)
 	defer func() {
 		if value := recover(); value != nil {
@@ -91,12 +121,18 @@ func (stmt *Stmt) Close() (err error) {
 		// free ocistmt to release cursor on server
 		// OCIStmtRelease must be called with OCIStmtPrepare2
 		// See https://docs.oracle.com/database/121/LNOCI/oci09adv.htm#LNOCI16655
+		// a key tags the statement in the OCI statement cache
+		var cKey *C.char
+		if stmt.key != "" {
+			cKey = C.CString(stmt.key)
+			defer C.free(unsafe.Pointer(cKey))
+		}
 		r := C.OCIStmtRelease(
-			stmt.ocistmt,            // OCIStmt        *stmthp
-			stmt.ses.srv.env.ocierr, // OCIError       *errhp,
-			nil,           // const OraText  *key
-			C.ub4(0),      // ub4 keylen
-			C.OCI_DEFAULT, // ub4 mode
+			stmt.ocistmt,                       // OCIStmt        *stmthp
+			stmt.ses.srv.env.ocierr,            // OCIError       *errhp,
+			(*C.OraText)(unsafe.Pointer(cKey)), // const OraText  *key
+			C.ub4(len(stmt.key)),               // ub4 keylen
+			C.OCI_DEFAULT,                      // ub4 mode
 		)
 		if r == C.OCI_ERROR {
 			errs.PushBack(errE(stmt.ses.srv.env.ociError()))
@@ -107,9 +143,17 @@ func (stmt *Stmt) Close() (err error) {
 		stmt.ocistmt = nil
 		stmt.stmtType = C.ub4(0)
 		stmt.sql = ""
+		stmt.key = ""
 		stmt.gcts = nil
 		stmt.bnds = nil
+		stmt.bindNames = nil
+		stmt.columns = nil
+		stmt.columnsKey = ""
 		stmt.hasPtrBind = false
+		stmt.sqlID = ""
+		stmt.serverTime = 0
+		stmt.isCached = false
+		stmt.isInUse = false
 		stmt.elem = nil
 		stmt.openRsets.Init()
 		_drv.stmtPool.Put(stmt)
@@ -121,15 +165,9 @@ func (stmt *Stmt) Close() (err error) {
 		errs.Init()
 		_drv.listPool.Put(errs)
 	}()
-	if len(stmt.bnds) > 0 { // close binds
-		for _, bind := range stmt.bnds {
-			if bind != nil {
-				err = bind.close()
-				if err != nil {
-					errs.PushBack(errE(err))
-				}
-			}
-		}
+	err = stmt.closeBnds() // close binds
+	if err != nil {
+		errs.PushBack(errE(err))
 	}
 	for e := stmt.openRsets.Front(); e != nil; e = e.Next() { // close result sets
 		err = e.Value.(*Rset).close()
@@ -142,13 +180,176 @@ func (stmt *Stmt) Close() (err error) {
 
 // Exe executes a SQL statement on an Oracle server returning the number of
 // rows affected and a possible error.
+//
+// A Stmt may not be used by more than one goroutine at a time; an error is
+// returned when Exe or Qry is called while the Stmt is executing.
 func (stmt *Stmt) Exe(params ...interface{}) (rowsAffected uint64, err error) {
 	rowsAffected, _, err = stmt.exe(params)
 	return rowsAffected, err
 }
 
+// ExeContext executes a SQL statement on an Oracle server returning the number
+// of rows affected and a possible error, interrupting the statement when ctx
+// is done.
+//
+// The statement executes on a separate goroutine. When ctx is done before the
+// statement completes, the running OCI call is interrupted with OCIBreak, the
+// service context is reset with OCIReset so that the session remains usable,
+// and ctx.Err() is returned. Should the statement complete regardless, its
+// result is returned, including an error other than the ORA-01013 of the
+// interrupted call.
+func (stmt *Stmt) ExeContext(ctx context.Context, params ...interface{}) (rowsAffected uint64, err error) {
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+	stmt.mu.Lock()
+	err = stmt.checkClosed()
+	if err != nil {
+		stmt.mu.Unlock()
+		return 0, errE(err)
+	}
+	srv := stmt.ses.srv
+	stmt.mu.Unlock()
+	type result struct {
+		rowsAffected uint64
+		err          error
+	}
+	done := make(chan result, 1)
+	go func() {
+		rowsAffected, _, err := stmt.exe(params)
+		done <- result{rowsAffected: rowsAffected, err: err}
+	}()
+	select {
+	case res := <-done:
+		return res.rowsAffected, res.err
+	case <-ctx.Done():
+		srv.breakCall()
+		res := <-done // wait for the interrupted call to return
+		// reset even when the call completed first, so that no break remains
+		// pending on the service context
+		resetErr := srv.resetCall()
+		if oe, ok := res.err.(*Error); ok && oe.Code == userCancelCode {
+			if resetErr != nil {
+				return 0, errE(resetErr)
+			}
+			return 0, ctx.Err()
+		}
+		// the call completed, or failed, before the break
+		if res.err == nil && resetErr != nil {
+			return res.rowsAffected, errE(resetErr)
+		}
+		return res.rowsAffected, res.err
+	}
+}
+
+// ExeRows executes an array DML statement with rows pulled from the nextRow
+// callback, returning the number of rows affected and a possible error.
+//
+// ExeRows bounds the memory used by a large array DML to batchSize rows rather
+// than to the whole input. Rows are buffered into slices of at most batchSize
+// elements, one slice per placeholder, and each full batch is bound and
+// executed as a single array DML call, as with Stmt.Exe. OCI doesn't call back
+// into Go for row data during an execute; each batch is fully buffered before
+// it's sent. nextRow returns io.EOF to indicate there are no more rows; any
+// other error stops execution and is returned.
+//
+// Batches executed before an error are not rolled back by ExeRows.
+//
+// Every row must have the same number of values and each placeholder must
+// receive values of the same Go type across rows. Use nullable ora types, such
+// as ora.Int64, to send NULL values.
+func (stmt *Stmt) ExeRows(batchSize int, nextRow func() ([]interface{}, error)) (rowsAffected uint64, err error) {
+	if batchSize < 1 {
+		return 0, errF("Parameter 'batchSize' must be greater than zero.")
+	}
+	var cols []reflect.Value
+	flush := func() error {
+		if len(cols) == 0 || cols[0].Len() == 0 {
+			return nil
+		}
+		params := make([]interface{}, len(cols))
+		for n := range cols {
+			params[n] = cols[n].Interface()
+		}
+		batchRowsAffected, err := stmt.Exe(params...)
+		rowsAffected += batchRowsAffected
+		if err != nil {
+			return err
+		}
+		for n := range cols {
+			cols[n] = reflect.MakeSlice(cols[n].Type(), 0, batchSize)
+		}
+		return nil
+	}
+	for rowIndex := 0; ; rowIndex++ {
+		row, err := nextRow()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rowsAffected, errE(err)
+		}
+		if cols == nil {
+			cols = make([]reflect.Value, len(row))
+			for n := range row {
+				if row[n] == nil {
+					return rowsAffected, errF("Row %v has a nil value at position %v; use a nullable ora type to send NULL.", rowIndex, n+1)
+				}
+				cols[n] = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(row[n])), 0, batchSize)
+			}
+		}
+		if len(row) != len(cols) {
+			return rowsAffected, errF("Row %v has %v values; expected %v.", rowIndex, len(row), len(cols))
+		}
+		for n := range row {
+			value := reflect.ValueOf(row[n])
+			if !value.IsValid() || value.Type() != cols[n].Type().Elem() {
+				return rowsAffected, errF("Row %v has a value of type %T at position %v; expected %v.", rowIndex, row[n], n+1, cols[n].Type().Elem())
+			}
+			cols[n] = reflect.Append(cols[n], value)
+		}
+		if cols[0].Len() == batchSize {
+			if err = flush(); err != nil {
+				return rowsAffected, errE(err)
+			}
+		}
+	}
+	if err = flush(); err != nil {
+		return rowsAffected, errE(err)
+	}
+	return rowsAffected, nil
+}
+
+// ExeStream executes an array DML statement with rows received from the rows
+// channel, returning the number of rows affected and a possible error.
+//
+// ExeStream batches rows as described for Stmt.ExeRows and returns when the
+// rows channel is closed. When StmtCfg.IsAutoCommitting is true and no
+// transaction is open, each batch is committed as it's executed.
+//
+// ExeStream stops receiving from the rows channel at the first error. A
+// producer should therefore not block indefinitely on sending to the channel,
+// for example by selecting on a done channel closed once ExeStream returns.
+func (stmt *Stmt) ExeStream(batchSize int, rows <-chan []interface{}) (rowsAffected uint64, err error) {
+	rowsAffected, err = stmt.ExeRows(batchSize, func() ([]interface{}, error) {
+		row, ok := <-rows
+		if !ok {
+			return nil, io.EOF
+		}
+		return row, nil
+	})
+	if err != nil {
+		return rowsAffected, errE(err)
+	}
+	return rowsAffected, nil
+}
+
 // exe executes a SQL statement on an Oracle server returning rowsAffected, lastInsertId and error.
 func (stmt *Stmt) exe(params []interface{}) (rowsAffected uint64, lastInsertId int64, err error) {
+	if !atomic.CompareAndSwapInt32(&stmt.busy, 0, 1) {
+		return 0, 0, er("Concurrent use of Stmt.")
+	}
+	defer atomic.StoreInt32(&stmt.busy, 0)
 	stmt.mu.Lock()
 	defer stmt.mu.Unlock()
 	stmt.log(_drv.cfg.Log.Stmt.Exe)
@@ -156,6 +357,7 @@ func (stmt *Stmt) exe(params []interface{}) (rowsAffected uint64, lastInsertId i
 	if err != nil {
 		return 0, 0, errE(err)
 	}
+	stmt.ses.touch()
 	// for case of inserting and returning identity for database/sql package
 	if _drv.sqlPkgEnv == stmt.ses.srv.env && stmt.stmtType == C.OCI_STMT_INSERT {
 		lastIndex := strings.LastIndex(stmt.sql, ")")
@@ -167,6 +369,7 @@ func (stmt *Stmt) exe(params []interface{}) (rowsAffected uint64, lastInsertId i
 		}
 	}
 	iterations, err := stmt.bind(params) // bind parameters
+	defer stmt.freeTempLobs()
 	if err != nil {
 		return 0, 0, errE(err)
 	}
@@ -174,34 +377,87 @@ func (stmt *Stmt) exe(params []interface{}) (rowsAffected uint64, lastInsertId i
 	if err != nil {
 		return 0, 0, errE(err)
 	}
+	err = stmt.setCallTimeout()
+	if err != nil {
+		return 0, 0, errE(err)
+	}
 	var mode C.ub4 // determine auto-commit state; don't auto-comit if there's an explicit user transaction occuring
 	if stmt.cfg.IsAutoCommitting && stmt.ses.openTxs.Front() == nil {
 		mode = C.OCI_COMMIT_ON_SUCCESS
 	} else {
 		mode = C.OCI_DEFAULT
 	}
-	// Execute statement on Oracle server
-	r := C.OCIStmtExecute(
-		stmt.ses.srv.ocisvcctx,  //OCISvcCtx           *svchp,
-		stmt.ocistmt,            //OCIStmt             *stmtp,
-		stmt.ses.srv.env.ocierr, //OCIError            *errhp,
-		C.ub4(iterations),       //ub4                 iters,
-		C.ub4(0),                //ub4                 rowoff,
-		nil,                     //const OCISnapshot   *snap_in,
-		nil,                     //OCISnapshot         *snap_out,
-		mode)                    //ub4                 mode );
-	if r == C.OCI_ERROR {
-		return 0, 0, errE(stmt.ses.srv.env.ociError())
+	// determine batch size; auto-committed array DML may be committed every CommitRowCount rows
+	batchSize := iterations
+	if stmt.cfg.commitRowCount > 0 && stmt.cfg.commitRowCount < iterations && mode == C.OCI_COMMIT_ON_SUCCESS {
+		switch stmt.stmtType {
+		case C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE, C.OCI_STMT_INSERT, C.OCI_STMT_MERGE:
+			batchSize = stmt.cfg.commitRowCount
+		}
 	}
-	var ub8RowsAffected C.ub8 // Get rowsAffected based on statement type
-	switch stmt.stmtType {
-	case C.OCI_STMT_SELECT, C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE, C.OCI_STMT_INSERT:
-		err := stmt.attr(unsafe.Pointer(&ub8RowsAffected), 8, C.OCI_ATTR_UB8_ROW_COUNT)
+	// continue past failed rows of array DML when batch errors are requested
+	isBatchErrors := false
+	if stmt.cfg.IsBatchErrors {
+		switch stmt.stmtType {
+		case C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE, C.OCI_STMT_INSERT, C.OCI_STMT_MERGE:
+			isBatchErrors = true
+			mode |= C.OCI_BATCH_ERRORS
+		}
+	}
+	var batchErrs BatchErrors
+	stmt.serverTime = 0
+	if stmt.cfg.IsTimingServer {
+		err = stmt.ses.collectCallTime()
 		if err != nil {
 			return 0, 0, errE(err)
 		}
-		rowsAffected = uint64(ub8RowsAffected)
-	case C.OCI_STMT_CREATE, C.OCI_STMT_DROP, C.OCI_STMT_ALTER, C.OCI_STMT_BEGIN:
+	}
+	defer stmt.logSlow(time.Now(), len(params))
+	for rowOffset := uint32(0); ; rowOffset += batchSize {
+		batchIterations := batchSize
+		if iterations-rowOffset < batchIterations {
+			batchIterations = iterations - rowOffset
+		}
+		// Execute statement on Oracle server
+		r := C.OCIStmtExecute(
+			stmt.ses.srv.ocisvcctx,  //OCISvcCtx           *svchp,
+			stmt.ocistmt,            //OCIStmt             *stmtp,
+			stmt.ses.srv.env.ocierr, //OCIError            *errhp,
+			C.ub4(batchIterations),  //ub4                 iters,
+			C.ub4(rowOffset),        //ub4                 rowoff,
+			nil,                     //const OCISnapshot   *snap_in,
+			nil,                     //OCISnapshot         *snap_out,
+			mode)                    //ub4                 mode );
+		if r == C.OCI_ERROR {
+			return rowsAffected, 0, errE(stmt.ses.srv.env.ociError())
+		}
+		if isBatchErrors && r == C.OCI_SUCCESS_WITH_INFO {
+			errs, err := stmt.batchErrors()
+			if err != nil {
+				return rowsAffected, 0, errE(err)
+			}
+			batchErrs = append(batchErrs, errs...)
+		}
+		if stmt.cfg.IsTimingServer {
+			callTime, err := stmt.ses.callTime()
+			if err != nil {
+				return rowsAffected, 0, errE(err)
+			}
+			stmt.serverTime += callTime
+		}
+		var ub8RowsAffected C.ub8 // Get rowsAffected based on statement type
+		switch stmt.stmtType {
+		case C.OCI_STMT_SELECT, C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE, C.OCI_STMT_INSERT:
+			err := stmt.attr(unsafe.Pointer(&ub8RowsAffected), 8, C.OCI_ATTR_UB8_ROW_COUNT)
+			if err != nil {
+				return rowsAffected, 0, errE(err)
+			}
+			rowsAffected += uint64(ub8RowsAffected)
+		case C.OCI_STMT_CREATE, C.OCI_STMT_DROP, C.OCI_STMT_ALTER, C.OCI_STMT_BEGIN:
+		}
+		if rowOffset+batchIterations >= iterations {
+			break
+		}
 	}
 	if stmt.hasPtrBind { // Set any bind pointers
 		err = stmt.setBindPtrs()
@@ -209,9 +465,58 @@ func (stmt *Stmt) exe(params []interface{}) (rowsAffected uint64, lastInsertId i
 			return rowsAffected, lastInsertId, errE(err)
 		}
 	}
+	if len(batchErrs) > 0 {
+		return rowsAffected, lastInsertId, batchErrs
+	}
 	return rowsAffected, lastInsertId, nil
 }
 
+// batchErrors reads the errors of the failed rows of an array DML statement
+// executed with OCI_BATCH_ERRORS. No locking occurs.
+func (stmt *Stmt) batchErrors() (BatchErrors, error) {
+	env := stmt.ses.srv.env
+	var numErrs C.ub4
+	err := stmt.attr(unsafe.Pointer(&numErrs), 4, C.OCI_ATTR_NUM_DML_ERRORS)
+	if err != nil || numErrs == 0 {
+		return nil, err
+	}
+	// each row error is a parameter of the environment error handle, read
+	// into a separate error handle
+	rowErr, err := env.allocOciHandle(C.OCI_HTYPE_ERROR)
+	if err != nil {
+		return nil, err
+	}
+	defer env.freeOciHandle(rowErr, C.OCI_HTYPE_ERROR)
+	errs := make(BatchErrors, 0, int(numErrs))
+	for n := C.ub4(0); n < numErrs; n++ {
+		r := C.OCIParamGet(
+			unsafe.Pointer(env.ocierr), //const void        *hndlp,
+			C.OCI_HTYPE_ERROR,          //ub4               htype,
+			env.ocierr,                 //OCIError          *errhp,
+			&rowErr,                    //void              **parmdpp,
+			n)                          //ub4               pos );
+		if r == C.OCI_ERROR {
+			return nil, env.ociError()
+		}
+		var rowOffset C.ub4
+		err = env.attr(rowErr, C.OCI_HTYPE_ERROR, unsafe.Pointer(&rowOffset), C.OCI_ATTR_DML_ROW_OFFSET)
+		if err != nil {
+			return nil, err
+		}
+		var errcode C.sb4
+		C.OCIErrorGet(
+			rowErr,
+			1, nil,
+			&errcode,
+			(*C.OraText)(unsafe.Pointer(&env.errBuf[0])),
+			C.ub4(len(env.errBuf)),
+			C.OCI_HTYPE_ERROR)
+		text := strings.TrimSpace(C.GoString(&env.errBuf[0]))
+		errs = append(errs, BatchError{Index: int(rowOffset), Err: &Error{Code: int(errcode), Text: text, str: text}})
+	}
+	return errs, nil
+}
+
 // Qry runs a SQL query on an Oracle server returning a *Rset and possible error.
 func (stmt *Stmt) Qry(params ...interface{}) (*Rset, error) {
 	return stmt.qry(params)
@@ -219,6 +524,10 @@ func (stmt *Stmt) Qry(params ...interface{}) (*Rset, error) {
 
 // qry runs a SQL query on an Oracle server returning a *Rset and possible error.
 func (stmt *Stmt) qry(params []interface{}) (rset *Rset, err error) {
+	if !atomic.CompareAndSwapInt32(&stmt.busy, 0, 1) {
+		return nil, er("Concurrent use of Stmt.")
+	}
+	defer atomic.StoreInt32(&stmt.busy, 0)
 	stmt.mu.Lock()
 	defer stmt.mu.Unlock()
 	stmt.log(_drv.cfg.Log.Stmt.Qry)
@@ -226,6 +535,7 @@ func (stmt *Stmt) qry(params []interface{}) (rset *Rset, err error) {
 	if err != nil {
 		return nil, errE(err)
 	}
+	stmt.ses.touch()
 	_, err = stmt.bind(params) // bind parameters
 	if err != nil {
 		return nil, errE(err)
@@ -234,7 +544,23 @@ func (stmt *Stmt) qry(params []interface{}) (rset *Rset, err error) {
 	if err != nil {
 		return nil, errE(err)
 	}
+	err = stmt.setCallTimeout()
+	if err != nil {
+		return nil, errE(err)
+	}
+	stmt.serverTime = 0
+	if stmt.cfg.IsTimingServer {
+		err = stmt.ses.collectCallTime()
+		if err != nil {
+			return nil, errE(err)
+		}
+	}
 	// Query statement on Oracle server
+	defer stmt.logSlow(time.Now(), len(params))
+	mode := C.ub4(C.OCI_DEFAULT)
+	if stmt.cfg.IsScrollable {
+		mode = C.OCI_STMT_SCROLLABLE_READONLY
+	}
 	r := C.OCIStmtExecute(
 		stmt.ses.srv.ocisvcctx,  //OCISvcCtx           *svchp,
 		stmt.ocistmt,            //OCIStmt             *stmtp,
@@ -243,24 +569,37 @@ func (stmt *Stmt) qry(params []interface{}) (rset *Rset, err error) {
 		C.ub4(0),                //ub4                 rowoff,
 		nil,                     //const OCISnapshot   *snap_in,
 		nil,                     //OCISnapshot         *snap_out,
-		C.OCI_DEFAULT)           //ub4                 mode );
+		mode)                    //ub4                 mode );
 	if r == C.OCI_ERROR {
 		return nil, errE(stmt.ses.srv.env.ociError())
 	}
+	if stmt.cfg.IsTimingServer {
+		stmt.serverTime, err = stmt.ses.callTime()
+		if err != nil {
+			return nil, errE(err)
+		}
+	}
 	if stmt.hasPtrBind { // set any bind pointers
 		err = stmt.setBindPtrs()
 		if err != nil {
 			return nil, errE(err)
 		}
 	}
+	// reuse the describe of a previous execution with like parameters
+	if key := paramsKey(params); key != stmt.columnsKey {
+		stmt.columns, stmt.columnsKey = nil, key
+	}
 	// create result set and open
 	rset = _drv.rsetPool.Get().(*Rset)
 	if rset.id == 0 {
 		rset.id = _drv.rsetId.nextId()
 	}
+	rset.isScroll = stmt.cfg.IsScrollable
 	err = rset.open(stmt, stmt.ocistmt)
 	if err != nil {
 		rset.close()
+		// a define error may be caused by a changed select-list
+		stmt.columns, stmt.columnsKey = nil, ""
 		return nil, errE(err)
 	}
 	// store result set for later close call
@@ -268,6 +607,165 @@ func (stmt *Stmt) qry(params []interface{}) (rset *Rset, err error) {
 	return rset, nil
 }
 
+// paramsKey identifies the Go types of params, and the lengths of string and
+// []byte params, on which the select-list of a query such as
+// "select :1 from dual" depends.
+func paramsKey(params []interface{}) string {
+	var buf bytes.Buffer
+	for _, param := range params {
+		if named, ok := param.(Named); ok {
+			fmt.Fprintf(&buf, ":%v=", named.Name)
+			param = named.Value
+		}
+		switch value := param.(type) {
+		case string:
+			fmt.Fprintf(&buf, "string(%v),", len(value))
+		case []byte:
+			fmt.Fprintf(&buf, "[]byte(%v),", len(value))
+		default:
+			fmt.Fprintf(&buf, "%T,", param)
+		}
+	}
+	return buf.String()
+}
+
+// QryContext runs a SQL query on an Oracle server returning a *Rset and
+// possible error, interrupting the query when ctx is done.
+//
+// The query executes on a separate goroutine. When ctx is done before the
+// query completes, the running OCI call is interrupted with OCIBreak, the
+// service context is reset with OCIReset so that the session remains usable,
+// any opened *Rset is closed, and ctx.Err() is returned.
+//
+// Once returned, the *Rset observes ctx between fetches; see Rset.Next.
+func (stmt *Stmt) QryContext(ctx context.Context, params ...interface{}) (*Rset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	stmt.mu.Lock()
+	err := stmt.checkClosed()
+	if err != nil {
+		stmt.mu.Unlock()
+		return nil, errE(err)
+	}
+	srv := stmt.ses.srv
+	stmt.mu.Unlock()
+	type result struct {
+		rset *Rset
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		rset, err := stmt.qry(params)
+		done <- result{rset: rset, err: err}
+	}()
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		res.rset.ctx = ctx
+		return res.rset, nil
+	case <-ctx.Done():
+		srv.breakCall()
+		res := <-done // wait for the interrupted call to return
+		if res.rset != nil {
+			stmt.mu.Lock()
+			for e := stmt.openRsets.Front(); e != nil; e = e.Next() {
+				if e.Value.(*Rset) == res.rset {
+					stmt.openRsets.Remove(e)
+					break
+				}
+			}
+			res.rset.close()
+			stmt.mu.Unlock()
+		}
+		if err = srv.resetCall(); err != nil {
+			return nil, errE(err)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// setBindNames records the placeholder name of each Named parameter, which is
+// then bound by name, and returns the parameters with their Named values
+// unwrapped. Positional parameters have no names.
+func (stmt *Stmt) setBindNames(params []interface{}) ([]interface{}, error) {
+	stmt.bindNames = nil
+	var values []interface{}
+	for n, param := range params {
+		named, ok := param.(Named)
+		if !ok {
+			if values != nil {
+				return nil, errF("Named and positional parameters can't be mixed (parameter %v).", n+1)
+			}
+			continue
+		}
+		if values == nil {
+			if n > 0 {
+				return nil, errF("Named and positional parameters can't be mixed (parameter %v).", n+1)
+			}
+			values = make([]interface{}, len(params))
+			stmt.bindNames = make([]string, len(params))
+		}
+		name := strings.TrimPrefix(named.Name, ":")
+		if name == "" {
+			return nil, errF("Named parameter %v has no name.", n+1)
+		}
+		for _, bindName := range stmt.bindNames[:n] {
+			if strings.EqualFold(bindName, name) {
+				return nil, errF("Named parameter %v is passed more than once.", name)
+			}
+		}
+		values[n] = named.Value
+		stmt.bindNames[n] = name
+	}
+	if values == nil {
+		return params, nil
+	}
+	return values, nil
+}
+
+// ociBind binds the parameter at a 1-based position with OCIBindByPos, or
+// with OCIBindByName when the parameter was passed as a Named value.
+func (stmt *Stmt) ociBind(bindpp **C.OCIBind, position int, valuep unsafe.Pointer, valueSz C.LENGTH_TYPE, dty C.ub2,
+	indp unsafe.Pointer, alenp *C.ACTUAL_LENGTH_TYPE, rcodep *C.ub2, maxarrLen C.ub4, curelep *C.ub4, mode C.ub4) C.sword {
+	if position <= len(stmt.bindNames) {
+		placeholder := stmt.bindNames[position-1]
+		cPlaceholder := C.CString(placeholder)
+		defer C.free(unsafe.Pointer(cPlaceholder))
+		return C.OCIBINDBYNAME(
+			stmt.ocistmt,            //OCIStmt      *stmtp,
+			bindpp,                  //OCIBind      **bindpp,
+			stmt.ses.srv.env.ocierr, //OCIError     *errhp,
+			(*C.OraText)(unsafe.Pointer(cPlaceholder)), //OraText      *placeholder,
+			C.sb4(len(placeholder)),                    //sb4          placeh_len,
+			valuep,                                     //void         *valuep,
+			valueSz,                                    //sb8          value_sz,
+			dty,                                        //ub2          dty,
+			indp,                                       //void         *indp,
+			alenp,                                      //ub2          *alenp,
+			rcodep,                                     //ub2          *rcodep,
+			maxarrLen,                                  //ub4          maxarr_len,
+			curelep,                                    //ub4          *curelep,
+			mode)                                       //ub4          mode );
+	}
+	return C.OCIBINDBYPOS(
+		stmt.ocistmt,            //OCIStmt      *stmtp,
+		bindpp,                  //OCIBind      **bindpp,
+		stmt.ses.srv.env.ocierr, //OCIError     *errhp,
+		C.ub4(position),         //ub4          position,
+		valuep,                  //void         *valuep,
+		valueSz,                 //sb8          value_sz,
+		dty,                     //ub2          dty,
+		indp,                    //void         *indp,
+		alenp,                   //ub2          *alenp,
+		rcodep,                  //ub2          *rcodep,
+		maxarrLen,               //ub4          maxarr_len,
+		curelep,                 //ub4          *curelep,
+		mode)                    //ub4          mode );
+}
+
 // setBindPtrs enables binds to set out pointers for some types such as time.Time, etc.
 func (stmt *Stmt) setBindPtrs() (err error) {
 	for _, bind := range stmt.bnds {
@@ -303,6 +801,20 @@ func (stmt *Stmt) putBnd(idx int, bnd bnd) {
 func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 	stmt.logF(_drv.cfg.Log.Stmt.Bind, "Params %v", len(params))
 	iterations = 1
+	params, err = stmt.setBindNames(params) // unwrap Named parameters
+	if err != nil {
+		return iterations, err
+	}
+	err = stmt.checkArrayBindLens(params) // array binds must have equal lengths
+	if err != nil {
+		return iterations, err
+	}
+	// Close binds of a previous execution; a parameter's type may differ
+	// between executions, so each position is bound anew
+	err = stmt.closeBnds()
+	if err != nil {
+		return iterations, err
+	}
 	// Create binds for each parameter; bind position is 1-based
 	if params != nil && len(params) > 0 {
 		stmt.bnds = make([]bnd, len(params))
@@ -638,7 +1150,7 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 					switch bnd := stmt.getBnd(bndIdxBin).(type) {
 					case *bndBin:
 						stmt.bnds[n] = bnd
-						err = bnd.bind(value, n+1, stmt)
+						err = bnd.bind(value, n+1, stmt.cfg.rawThreshold, stmt)
 						if err != nil {
 							return iterations, err
 						}
@@ -754,6 +1266,10 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 				}
 				iterations = uint32(len(value))
 			case time.Time:
+				value, err = stmt.dateFraction(value, n+1)
+				if err != nil {
+					return iterations, err
+				}
 				bnd := stmt.getBnd(bndIdxTime).(*bndTime)
 				stmt.bnds[n] = bnd
 				err = bnd.bind(value, n+1, stmt)
@@ -772,6 +1288,10 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 				if value.IsNull {
 					stmt.setNilBind(n, C.SQLT_TIMESTAMP_TZ)
 				} else {
+					value.Value, err = stmt.dateFraction(value.Value, n+1)
+					if err != nil {
+						return iterations, err
+					}
 					bnd := stmt.getBnd(bndIdxTime).(*bndTime)
 					stmt.bnds[n] = bnd
 					err = bnd.bind(value.Value, n+1, stmt)
@@ -780,6 +1300,16 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 					}
 				}
 			case []time.Time:
+				if stmt.cfg.DateFraction != KeepFraction {
+					values := make([]time.Time, len(value))
+					for m := range value {
+						values[m], err = stmt.dateFraction(value[m], n+1)
+						if err != nil {
+							return iterations, err
+						}
+					}
+					value = values
+				}
 				bnd := stmt.getBnd(bndIdxTimeSlice).(*bndTimeSlice)
 				stmt.bnds[n] = bnd
 				err = bnd.bind(value, nil, n+1, stmt)
@@ -788,6 +1318,19 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 				}
 				iterations = uint32(len(value))
 			case []Time:
+				if stmt.cfg.DateFraction != KeepFraction {
+					values := make([]Time, len(value))
+					for m := range value {
+						values[m] = value[m]
+						if !value[m].IsNull {
+							values[m].Value, err = stmt.dateFraction(value[m].Value, n+1)
+							if err != nil {
+								return iterations, err
+							}
+						}
+					}
+					value = values
+				}
 				bnd := stmt.getBnd(bndIdxTimeSlice).(*bndTimeSlice)
 				stmt.bnds[n] = bnd
 				err = bnd.bindOra(value, n+1, stmt)
@@ -796,11 +1339,20 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 				}
 				iterations = uint32(len(value))
 			case string:
-				bnd := stmt.getBnd(bndIdxString).(*bndString)
-				stmt.bnds[n] = bnd
-				err = bnd.bind(value, n+1, stmt)
-				if err != nil {
-					return iterations, err
+				if stmt.cfg.clobThreshold > 0 && len(value) > stmt.cfg.clobThreshold {
+					bnd := stmt.getBnd(bndIdxLob).(*bndLob)
+					stmt.bnds[n] = bnd
+					err = bnd.bindClob(value, n+1, stmt.cfg.lobBufferSize, stmt)
+					if err != nil {
+						return iterations, err
+					}
+				} else {
+					bnd := stmt.getBnd(bndIdxString).(*bndString)
+					stmt.bnds[n] = bnd
+					err = bnd.bind(value, n+1, stmt)
+					if err != nil {
+						return iterations, err
+					}
 				}
 			case *string:
 				bnd := stmt.getBnd(bndIdxStringPtr).(*bndStringPtr)
@@ -810,9 +1362,70 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 					return iterations, err
 				}
 				stmt.hasPtrBind = true
+			case Rowid:
+				// Rowid is text-only; the server converts the textual rowid
+				if value.IsNull {
+					stmt.setNilBind(n, C.SQLT_CHR)
+				} else {
+					bnd := stmt.getBnd(bndIdxString).(*bndString)
+					stmt.bnds[n] = bnd
+					err = bnd.bind(value.Value, n+1, stmt)
+					if err != nil {
+						return iterations, err
+					}
+				}
+			case *[]Rowid:
+				// RETURNING ROWID INTO of each row of an array DML statement
+				bnd := stmt.getBnd(bndIdxRowidSlicePtr).(*bndRowidSlicePtr)
+				stmt.bnds[n] = bnd
+				err = bnd.bind(value, stmt.arrayIterations(params), n+1, stmt)
+				if err != nil {
+					return iterations, err
+				}
+				stmt.hasPtrBind = true
+			case Out:
+				switch outValue := value.Value.(type) {
+				case *int64:
+					bnd := stmt.getBnd(bndIdxInt64Ptr).(*bndInt64Ptr)
+					stmt.bnds[n] = bnd
+					err = bnd.bindOut(outValue, value.IsNull, n+1, stmt)
+				case *float64:
+					bnd := stmt.getBnd(bndIdxFloat64Ptr).(*bndFloat64Ptr)
+					stmt.bnds[n] = bnd
+					err = bnd.bindOut(outValue, value.IsNull, n+1, stmt)
+				case *string:
+					bnd := stmt.getBnd(bndIdxStringPtr).(*bndStringPtr)
+					stmt.bnds[n] = bnd
+					err = bnd.bindOut(outValue, value.IsNull, n+1, stmt.cfg.stringPtrBufferSize, stmt)
+				case *time.Time:
+					bnd := stmt.getBnd(bndIdxTimePtr).(*bndTimePtr)
+					stmt.bnds[n] = bnd
+					err = bnd.bindOut(outValue, value.IsNull, n+1, stmt)
+				case **Rset:
+					// an OUT REF CURSOR; a nil *Rset is allocated
+					if *outValue == nil {
+						*outValue = &Rset{}
+					}
+					bnd := stmt.getBnd(bndIdxRset).(*bndRset)
+					stmt.bnds[n] = bnd
+					err = bnd.bind(*outValue, n+1, stmt)
+				default:
+					err = errF("Unsupported Out value type (%T). Expected *int64, *float64, *string, *time.Time or **Rset.", value.Value)
+				}
+				if err != nil {
+					return iterations, err
+				}
+				stmt.hasPtrBind = true
 			case String:
 				if value.IsNull {
 					stmt.setNilBind(n, C.SQLT_CHR)
+				} else if stmt.cfg.clobThreshold > 0 && len(value.Value) > stmt.cfg.clobThreshold {
+					bnd := stmt.getBnd(bndIdxLob).(*bndLob)
+					stmt.bnds[n] = bnd
+					err = bnd.bindClob(value.Value, n+1, stmt.cfg.lobBufferSize, stmt)
+					if err != nil {
+						return iterations, err
+					}
 				} else {
 					bnd := stmt.getBnd(bndIdxString).(*bndString)
 					stmt.bnds[n] = bnd
@@ -885,7 +1498,7 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 				} else {
 					bnd := stmt.getBnd(bndIdxBin).(*bndBin)
 					stmt.bnds[n] = bnd
-					err = bnd.bind(value.Value, n+1, stmt)
+					err = bnd.bind(value.Value, n+1, stmt.cfg.rawThreshold, stmt)
 					if err != nil {
 						return iterations, err
 					}
@@ -971,6 +1584,67 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 						return iterations, err
 					}
 				}
+			case *big.Int:
+				bnd := stmt.getBnd(bndIdxBigInt).(*bndBigInt)
+				stmt.bnds[n] = bnd
+				err = bnd.bind(value, n+1, stmt)
+				if err != nil {
+					return iterations, err
+				}
+			case big.Int:
+				bnd := stmt.getBnd(bndIdxBigInt).(*bndBigInt)
+				stmt.bnds[n] = bnd
+				err = bnd.bind(&value, n+1, stmt)
+				if err != nil {
+					return iterations, err
+				}
+			case Decimal:
+				if value.IsNull {
+					stmt.setNilBind(n, C.SQLT_CHR)
+				} else {
+					bnd := stmt.getBnd(bndIdxDecimal).(*bndDecimal)
+					stmt.bnds[n] = bnd
+					err = bnd.bind(value.Value, n+1, stmt)
+					if err != nil {
+						return iterations, err
+					}
+				}
+			case Number:
+				bnd := stmt.getBnd(bndIdxDecimal).(*bndDecimal)
+				stmt.bnds[n] = bnd
+				err = bnd.bind(value.String(), n+1, stmt)
+				if err != nil {
+					return iterations, err
+				}
+			case AnyData:
+				bnd := stmt.getBnd(bndIdxAnyData).(*bndAnyData)
+				stmt.bnds[n] = bnd
+				err = bnd.bind(value, n+1, stmt)
+				if err != nil {
+					return iterations, err
+				}
+			case Coll:
+				bnd := stmt.getBnd(bndIdxColl).(*bndColl)
+				stmt.bnds[n] = bnd
+				err = bnd.bind(value, n+1, stmt)
+				if err != nil {
+					return iterations, err
+				}
+			case PlsArr:
+				bnd := stmt.getBnd(bndIdxPlsArr).(*bndPlsArr)
+				stmt.bnds[n] = bnd
+				err = bnd.bind(value, n+1, stmt.cfg.stringPtrBufferSize, stmt)
+				if err != nil {
+					return iterations, err
+				}
+				stmt.hasPtrBind = true
+			case time.Duration:
+				bnd := stmt.getBnd(bndIdxIntervalDS).(*bndIntervalDS)
+				stmt.bnds[n] = bnd
+				err = bnd.bind(NewIntervalDS(value), n+1, stmt)
+				if err != nil {
+					return iterations, err
+				}
 			case []IntervalDS:
 				bnd := stmt.getBnd(bndIdxIntervalDSSlice).(*bndIntervalDSSlice)
 				stmt.bnds[n] = bnd
@@ -1001,6 +1675,13 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 			default:
 				if params[n] == nil {
 					err = stmt.setNilBind(n, C.SQLT_CHR)
+				} else if typeName, ok := objTypeName(params[n]); ok {
+					bnd := stmt.getBnd(bndIdxObject).(*bndObject)
+					stmt.bnds[n] = bnd
+					err = bnd.bind(params[n], typeName, n+1, stmt)
+					if err != nil {
+						return iterations, err
+					}
 				} else {
 					t := reflect.TypeOf(params[n])
 					if t.Kind() == reflect.Slice {
@@ -1017,6 +1698,111 @@ func (stmt *Stmt) bind(params []interface{}) (iterations uint32, err error) {
 	return iterations, err
 }
 
+// dateFraction applies StmtCfg.DateFraction to a time parameter at the
+// specified bind position. No locking occurs.
+func (stmt *Stmt) dateFraction(value time.Time, position int) (time.Time, error) {
+	switch stmt.cfg.DateFraction {
+	case TruncateFraction:
+		return value.Truncate(time.Second), nil
+	case RoundFraction:
+		return value.Round(time.Second), nil
+	case ErrorFraction:
+		if value.Nanosecond() != 0 {
+			return value, errF("Time parameter at position %v has fractional seconds (%v) which a DATE doesn't store.", position, value)
+		}
+	}
+	return value, nil
+}
+
+// freeTempLobs frees the temporary LOBs bound for an execution. No locking
+// occurs.
+func (stmt *Stmt) freeTempLobs() {
+	for _, bind := range stmt.bnds {
+		if bnd, ok := bind.(*bndLob); ok {
+			bnd.freeLob()
+		}
+	}
+}
+
+// closeBnds closes the binds of a previous execution. No locking occurs.
+func (stmt *Stmt) closeBnds() error {
+	errs := _drv.listPool.Get().(*list.List)
+	defer func() {
+		errs.Init()
+		_drv.listPool.Put(errs)
+	}()
+	for _, bind := range stmt.bnds {
+		if bind != nil {
+			err := bind.close()
+			if err != nil {
+				errs.PushBack(errE(err))
+			}
+		}
+	}
+	stmt.bnds = nil
+	stmt.hasPtrBind = false
+	if multiErr := newMultiErrL(errs); multiErr != nil {
+		return *multiErr
+	}
+	return nil
+}
+
+// checkArrayBindLens returns an error when array bind parameters have differing
+// lengths. All array binds of a statement share a single iteration count.
+// No locking occurs.
+func (stmt *Stmt) checkArrayBindLens(params []interface{}) error {
+	firstPosition, firstLen := 0, 0
+	var mismatches []string
+	for n := range params {
+		length, ok := stmt.arrayBindLen(params[n])
+		if !ok {
+			continue
+		}
+		if firstPosition == 0 {
+			firstPosition, firstLen = n+1, length
+			continue
+		}
+		if length != firstLen {
+			mismatches = append(mismatches, fmt.Sprintf("position %v has %v elements", n+1, length))
+		}
+	}
+	if len(mismatches) > 0 {
+		return errF("Array binds must have the same length: position %v has %v elements; %v.",
+			firstPosition, firstLen, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// arrayIterations returns the number of iterations of a statement executed
+// with params: the length of the array binds, or one without array binds.
+func (stmt *Stmt) arrayIterations(params []interface{}) uint32 {
+	for n := range params {
+		if length, ok := stmt.arrayBindLen(params[n]); ok {
+			return uint32(length)
+		}
+	}
+	return 1
+}
+
+// arrayBindLen returns the number of elements of an array bind parameter, and
+// whether the parameter is an array bind.
+func (stmt *Stmt) arrayBindLen(value interface{}) (length int, ok bool) {
+	switch value := value.(type) {
+	case []uint8:
+		if stmt.cfg.byteSlice != U8 {
+			return 0, false // bound as a single RAW or BLOB value
+		}
+		return len(value), true
+	case []int64, []int32, []int16, []int8, []uint64, []uint32, []uint16,
+		[]float64, []float32, []Int64, []Int32, []Int16, []Int8, []Uint64,
+		[]Uint32, []Uint16, []Uint8, []Float64, []Float32, []time.Time, []Time,
+		[]string, []String, []bool, []Bool, [][]byte, []Raw, []Lob,
+		[]IntervalYM, []IntervalDS:
+		return reflect.ValueOf(value).Len(), true
+	}
+	return 0, false
+}
+
 // NumRset returns the number of open Oracle result sets.
 func (stmt *Stmt) NumRset() int {
 	stmt.mu.Lock()
@@ -1036,6 +1822,60 @@ func (stmt *Stmt) NumInput() int {
 	return int(bindCount)
 }
 
+// SQLID returns the SQL_ID Oracle assigned to the statement.
+//
+// The SQL_ID is available once the statement has been executed, and may
+// be used to look up the statement in v$sql and AWR. An empty string is
+// returned when the statement hasn't been executed, or when the Oracle
+// client predates 12.2 and doesn't support OCI_ATTR_SQL_ID. The SQL_ID is
+// cached after it's first read.
+func (stmt *Stmt) SQLID() (string, error) {
+	stmt.mu.Lock()
+	defer stmt.mu.Unlock()
+	err := stmt.checkClosed()
+	if err != nil {
+		return "", errE(err)
+	}
+	if stmt.sqlID != "" {
+		return stmt.sqlID, nil
+	}
+	var sqlID *C.char
+	var sqlIDLen C.ub4
+	_, err = stmt.ses.srv.env.optionalAttr(
+		unsafe.Pointer(stmt.ocistmt),
+		C.OCI_HTYPE_STMT,
+		unsafe.Pointer(&sqlID),
+		&sqlIDLen,
+		C.OCI_ATTR_SQL_ID,
+		"OCI_ATTR_SQL_ID")
+	if err != nil {
+		return "", errE(err)
+	}
+	if sqlID != nil && sqlIDLen > 0 {
+		stmt.sqlID = C.GoStringN(sqlID, C.int(sqlIDLen))
+	}
+	return stmt.sqlID, nil
+}
+
+// ServerTime returns the time the Oracle server spent executing the most
+// recent Stmt.Exe or Stmt.Qry call.
+//
+// ServerTime excludes network round-trip time, distinguishing database work
+// from latency. For a query, the time covers the execute call only; fetching
+// rows with Rset.Next is not included.
+//
+// ServerTime is measured only when StmtCfg.IsTimingServer is true; otherwise,
+// zero is returned.
+func (stmt *Stmt) ServerTime() (time.Duration, error) {
+	stmt.mu.Lock()
+	defer stmt.mu.Unlock()
+	err := stmt.checkClosed()
+	if err != nil {
+		return 0, errE(err)
+	}
+	return stmt.serverTime, nil
+}
+
 // SetGcts sets a slice of GoColumnType used in a Stmt.Qry *ora.Rset.
 //
 // SetGcts is optional.
@@ -1116,20 +1956,60 @@ func (stmt *Stmt) logF(enabled bool, format string, v ...interface{}) {
 	}
 }
 
+// logSlow writes a warning when the time elapsed since started exceeds
+// DrvCfg.SlowQueryThreshold.
+func (stmt *Stmt) logSlow(started time.Time, bindCount int) {
+	threshold := _drv.cfg.SlowQueryThreshold
+	if threshold <= 0 {
+		return
+	}
+	if elapsed := time.Since(started); elapsed > threshold {
+		logWarnf("%v %v slow query: elapsed=%v binds=%v sql=%q",
+			stmt.sysName(), callInfo(1), elapsed, bindCount, stmt.sql)
+	}
+}
+
+// setCallTimeout sets the call timeout of the service context to
+// StmtCfg.CallTimeout when it differs from the timeout last set. No locking
+// occurs.
+func (stmt *Stmt) setCallTimeout() error {
+	timeout := C.ub4(stmt.cfg.CallTimeout / time.Millisecond)
+	if timeout == 0 && stmt.cfg.CallTimeout > 0 {
+		timeout = 1
+	}
+	srv := stmt.ses.srv
+	if timeout == srv.callTimeout {
+		return nil
+	}
+	err := srv.env.setOptionalAttr(unsafe.Pointer(srv.ocisvcctx), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&timeout), 4, C.OCI_ATTR_CALL_TIMEOUT, "OCI_ATTR_CALL_TIMEOUT")
+	if err != nil {
+		return errE(err)
+	}
+	srv.callTimeout = timeout
+	return nil
+}
+
 // set prefetch size. No locking occurs.
 func (stmt *Stmt) setPrefetchSize() error {
-	if stmt.cfg.prefetchRowCount > 0 {
-		//fmt.Println("stmt.setPrefetchSize: prefetchRowCount ", stmt.cfg.prefetchRowCount)
-		// set prefetch row count
-		if err := stmt.setAttr(unsafe.Pointer(&stmt.cfg.prefetchRowCount), 4, C.OCI_ATTR_PREFETCH_ROWS); err != nil {
+	if stmt.cfg.IsPrefetchDisabled || (stmt.stmtType == C.OCI_STMT_SELECT && isForUpdate(stmt.sql)) {
+		// disable prefetch when configured, and so that the row last fetched
+		// from a SELECT ... FOR UPDATE is the current row of Rset.CurrentRowid
+		var zero uint32
+		if err := stmt.setAttr(unsafe.Pointer(&zero), 4, C.OCI_ATTR_PREFETCH_ROWS); err != nil {
 			return errE(err)
 		}
-	} else {
-		//fmt.Println("stmt.setPrefetchSize: prefetchMemorySize ", stmt.cfg.prefetchMemorySize)
-		// Set prefetch memory size
-		if err := stmt.setAttr(unsafe.Pointer(&stmt.cfg.prefetchMemorySize), 4, C.OCI_ATTR_PREFETCH_MEMORY); err != nil {
+		if err := stmt.setAttr(unsafe.Pointer(&zero), 4, C.OCI_ATTR_PREFETCH_MEMORY); err != nil {
 			return errE(err)
 		}
+		return nil
+	}
+	// set both attributes; OCI prefetches one row by default, which would
+	// otherwise limit a prefetch by memory size to a single row
+	if err := stmt.setAttr(unsafe.Pointer(&stmt.cfg.prefetchRowCount), 4, C.OCI_ATTR_PREFETCH_ROWS); err != nil {
+		return errE(err)
+	}
+	if err := stmt.setAttr(unsafe.Pointer(&stmt.cfg.prefetchMemorySize), 4, C.OCI_ATTR_PREFETCH_MEMORY); err != nil {
+		return errE(err)
 	}
 	return nil
 }
@@ -4,6 +4,8 @@
 
 package ora
 
+import "time"
+
 // StmtCfg affects various aspects of a SQL statement.
 //
 // Assign values to StmtCfg prior to calling Stmt.Exe
@@ -15,7 +17,11 @@ type StmtCfg struct {
 	longRawBufferSize   uint32
 	lobBufferSize       int
 	stringPtrBufferSize int
+	clobThreshold       int
+	rawThreshold        int
 	byteSlice           GoColumnType
+	commitRowCount      uint32
+	fetchArraySize      uint32
 
 	// IsAutoCommitting determines whether DML statements are automatically
 	// committed.
@@ -37,6 +43,76 @@ type StmtCfg struct {
 	// The is default is '1'.
 	TrueRune rune
 
+	// IsBoolNumeric determines whether a Go bool or ora.Bool is sent to an
+	// Oracle server as a NUMBER during a parameter bind. When true, true is
+	// sent as 1 and false as 0, suiting NUMBER(1) columns; otherwise,
+	// TrueRune and FalseRune are sent as a single character.
+	//
+	// The default is false.
+	IsBoolNumeric bool
+
+	// IsTimingServer determines whether the Oracle server measures the time
+	// spent executing the statement, which is reported by Stmt.ServerTime.
+	//
+	// The default is false.
+	IsTimingServer bool
+
+	// IsMaterializingLobs determines whether CLOB, NCLOB and BLOB select-list
+	// columns are read whole during Rset.Next. When true, a CLOB or NCLOB is
+	// returned as a string or ora.String, and a BLOB as a []byte or ora.Raw,
+	// according to the column's GoColumnType.
+	//
+	// The default is false, which streams LOBs: a CLOB or NCLOB is returned as
+	// an ora.Lob, and a BLOB as an io.ReadCloser for Bin or an ora.Lob for
+	// OraBin. Read the value in chunks and close it when done.
+	IsMaterializingLobs bool
+
+	// DateFraction determines how a time.Time or ora.Time parameter, or a
+	// slice of them, with fractional seconds is bound. Set DateFraction on
+	// statements whose time parameters are Oracle DATEs, which store whole
+	// seconds; TIMESTAMP parameters are expected to keep fractional seconds.
+	//
+	// The default is KeepFraction.
+	DateFraction DateFraction
+
+	// IsPrefetchDisabled determines whether rows of a query are fetched from
+	// the Oracle server one at a time as Rset.Next is called. When true, the
+	// prefetch row count and memory size are zero, so that a pipelined table
+	// function, for example, produces each row only when it's requested.
+	//
+	// The default is false, which prefetches rows according to
+	// PrefetchRowCount and PrefetchMemorySize.
+	IsPrefetchDisabled bool
+
+	// IsBatchErrors determines whether an array DML statement continues past
+	// rows which fail. When true, Stmt.Exe processes every row and returns
+	// BatchErrors holding the index and Oracle error of each failed row;
+	// otherwise, the first failed row stops the statement.
+	//
+	// The default is false.
+	IsBatchErrors bool
+
+	// IsScrollable determines whether Stmt.Qry opens a scrollable, read-only
+	// cursor. A scrollable Rset moves to any row with Rset.Seek, Rset.First,
+	// Rset.Last and Rset.Prev as well as Rset.Next. The Oracle server keeps
+	// the rows of a scrollable cursor until the Rset is closed.
+	//
+	// The default is false.
+	IsScrollable bool
+
+	// CallTimeout limits each round trip to the Oracle server made by Stmt.Exe
+	// and Stmt.Qry, and by the fetches of an Rset until another statement of
+	// the session is executed. A call exceeding CallTimeout is interrupted and
+	// returns an *Error whose Timeout method returns true. OCI resets the
+	// interrupted call, but when the reset fails the connection can be left
+	// unusable, so a Con which times out is treated as broken.
+	//
+	// CallTimeout has millisecond precision and requires Oracle 18c or later
+	// client libraries; older clients ignore it.
+	//
+	// The default is zero, which doesn't limit calls.
+	CallTimeout time.Duration
+
 	// Rset represents configuration options for an Rset struct.
 	Rset RsetCfg
 }
@@ -44,12 +120,15 @@ type StmtCfg struct {
 // NewStmtCfg returns a StmtCfg with default values.
 func NewStmtCfg() *StmtCfg {
 	c := &StmtCfg{}
-	c.prefetchRowCount = 0
+	c.prefetchRowCount = 100
 	c.prefetchMemorySize = 1 << 27 // 134,217,728
 	c.longBufferSize = 1 << 24     // 16,777,216
 	c.longRawBufferSize = 1 << 24  // 16,777,216
 	c.lobBufferSize = 1 << 24      // 16,777,216
 	c.stringPtrBufferSize = 4000
+	c.clobThreshold = 32767
+	c.rawThreshold = maxRawLen
+	c.fetchArraySize = 1
 
 	c.IsAutoCommitting = true
 	c.FalseRune = '0'
@@ -66,7 +145,11 @@ func (c *StmtCfg) SetPrefetchRowCount(prefetchRowCount uint32) error {
 
 // PrefetchRowCount returns the number of rows to prefetch during a select query.
 //
-// The default is 0.
+// The default is 100.
+//
+// Each round trip to the Oracle server fetches up to PrefetchRowCount rows,
+// which are then returned by Rset.Next without a round trip. Raise
+// PrefetchRowCount for large result sets; lower it for wide rows.
 //
 // PrefetchRowCount works in coordination with PrefetchMemorySize. When
 // PrefetchRowCount is set to zero only PrefetchMemorySize is used;
@@ -94,6 +177,30 @@ func (c *StmtCfg) PrefetchMemorySize() uint32 {
 	return c.prefetchMemorySize
 }
 
+// SetFetchArraySize sets the number of rows fetched into define buffers by
+// each fetch call during a select query.
+//
+// Returns an error if the specified size is less than 1.
+func (c *StmtCfg) SetFetchArraySize(size uint32) error {
+	if size < 1 {
+		return errNew("SetFetchArraySize parameter 'size' must be greater than zero")
+	}
+	c.fetchArraySize = size
+	return nil
+}
+
+// FetchArraySize returns the number of rows fetched into define buffers by
+// each fetch call during a select query.
+//
+// The default is 1, which fetches one row per call from the rows prefetched
+// by OCI. A larger size fetches a batch of rows per call, avoiding a call per
+// row when scanning large result sets. The batch is used only when every
+// select-list column is a number or a string; other queries, and SELECT ...
+// FOR UPDATE queries, fetch one row per call.
+func (c *StmtCfg) FetchArraySize() uint32 {
+	return c.fetchArraySize
+}
+
 // SetLongBufferSize sets the long buffer size in bytes.
 //
 // The maximum is 2,147,483,642 bytes.
@@ -115,6 +222,8 @@ func (c *StmtCfg) SetLongBufferSize(size uint32) error {
 // LongBufferSize returns the long buffer size in bytes used to define the sql select-column
 // buffer size of an Oracle LONG type.
 //
+// A longer LONG value is fetched in further pieces of LongBufferSize bytes.
+//
 // The default is 16,777,216 bytes.
 //
 // The default is considered a moderate buffer where the 2GB max buffer may not
@@ -141,6 +250,9 @@ func (c *StmtCfg) SetLongRawBufferSize(size uint32) error {
 // LongRawBufferSize returns the LONG RAW buffer size in bytes used to define the sql select-column
 // buffer size of an Oracle LONG RAW type.
 //
+// A longer LONG RAW value is fetched in further pieces of LongRawBufferSize
+// bytes.
+//
 // The default is 16,777,216 bytes.
 //
 // The default is considered a moderate buffer where the 2GB max buffer may not
@@ -198,6 +310,53 @@ func (c *StmtCfg) StringPtrBufferSize() int {
 	return c.stringPtrBufferSize
 }
 
+// SetClobThreshold sets the length in bytes above which a string or
+// ora.String parameter is bound as a temporary CLOB.
+//
+// Returns an error if the specified size is less than 1.
+func (c *StmtCfg) SetClobThreshold(size int) error {
+	if size < 1 {
+		return errNew("SetClobThreshold parameter 'size' must be greater than zero")
+	}
+	c.clobThreshold = size
+	return nil
+}
+
+// ClobThreshold returns the length in bytes above which a string or
+// ora.String parameter is bound as a temporary CLOB.
+//
+// The default is 32,767 bytes, the maximum length of a PL/SQL VARCHAR2.
+//
+// A longer string, such as a large argument to a PL/SQL procedure taking a
+// CLOB parameter, is written to a temporary LOB which is bound in place of the
+// string. The temporary LOB is freed once Stmt.Exe completes, whether or not
+// the execution succeeds.
+func (c *StmtCfg) ClobThreshold() int {
+	return c.clobThreshold
+}
+
+// SetRawThreshold sets the length in bytes above which a []byte or ora.Raw
+// parameter is bound as a LONG RAW rather than a RAW.
+//
+// Returns an error if the specified size is less than 1.
+func (c *StmtCfg) SetRawThreshold(size int) error {
+	if size < 1 {
+		return errNew("SetRawThreshold parameter 'size' must be greater than zero")
+	}
+	c.rawThreshold = size
+	return nil
+}
+
+// RawThreshold returns the length in bytes above which a []byte or ora.Raw
+// parameter is bound as a LONG RAW rather than a RAW.
+//
+// The default is 2,000 bytes, the maximum length of a RAW column without
+// extended data types. Raise it to 32,767 bytes for a database with
+// MAX_STRING_SIZE=EXTENDED.
+func (c *StmtCfg) RawThreshold() int {
+	return c.rawThreshold
+}
+
 // SetByteSlice sets a GoColumnType associated to SQL statement []byte parameter.
 //
 // Valid values are U8 and Bits.
@@ -224,3 +383,30 @@ func (c *StmtCfg) SetByteSlice(gct GoColumnType) (err error) {
 func (c *StmtCfg) ByteSlice() GoColumnType {
 	return c.byteSlice
 }
+
+// SetCommitRowCount sets the number of rows after which an array DML statement
+// is committed during a single call to Stmt.Exe.
+//
+// Specify zero to execute an array DML statement as a single unit.
+func (c *StmtCfg) SetCommitRowCount(commitRowCount uint32) error {
+	c.commitRowCount = commitRowCount
+	return nil
+}
+
+// CommitRowCount returns the number of rows after which an array DML statement
+// is committed during a single call to Stmt.Exe.
+//
+// The default is 0, meaning an array DML statement is executed as a single
+// unit.
+//
+// When CommitRowCount is greater than zero, Stmt.Exe executes an array DML
+// statement in batches of CommitRowCount rows and commits after each batch.
+// This keeps undo and redo bounded for very large array inserts at the cost of
+// atomicity: should a batch fail, previously committed batches remain in the
+// database and the returned rowsAffected reports the committed rows.
+//
+// CommitRowCount is observed only when IsAutoCommitting is true; it is not
+// observed during a transaction or when IsAutoCommitting is false.
+func (c *StmtCfg) CommitRowCount() uint32 {
+	return c.commitRowCount
+}
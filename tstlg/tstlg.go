@@ -22,6 +22,14 @@ func (t Tst) Infoln(v ...interface{}) {
 	v[0] = "ORA I"
 	t.Log(v...)
 }
+func (t Tst) Warnf(format string, v ...interface{}) {
+	t.Logf("ORA W "+format, v...)
+}
+func (t Tst) Warnln(v ...interface{}) {
+	v = append(make([]interface{}, 1, len(v)+1), v)
+	v[0] = "ORA W"
+	t.Log(v...)
+}
 func (t Tst) Errorf(format string, v ...interface{}) {
 	t.Logf("ORA E "+format, v...)
 }
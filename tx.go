@@ -12,6 +12,7 @@ import "C"
 import (
 	"container/list"
 	"fmt"
+	"unsafe"
 )
 
 // LogTxCfg represents Tx logging configuration values.
@@ -25,6 +26,16 @@ type LogTxCfg struct {
 	//
 	// The default is true.
 	Rollback bool
+
+	// Prepare determines whether the Tx.Prepare method is logged.
+	//
+	// The default is true.
+	Prepare bool
+
+	// Forget determines whether the Tx.Forget method is logged.
+	//
+	// The default is true.
+	Forget bool
 }
 
 // NewLogTxCfg creates a LogTxCfg with default values.
@@ -32,9 +43,27 @@ func NewLogTxCfg() LogTxCfg {
 	c := LogTxCfg{}
 	c.Commit = true
 	c.Rollback = true
+	c.Prepare = true
+	c.Forget = true
 	return c
 }
 
+// Xid identifies a branch of a distributed transaction, as an X/Open XA
+// transaction identifier.
+//
+// Branches of one distributed transaction share FormatID and GlobalID and
+// differ in BranchID.
+type Xid struct {
+	// FormatID identifies the format of GlobalID and BranchID.
+	FormatID int32
+
+	// GlobalID identifies the distributed transaction; 1 to 64 bytes.
+	GlobalID []byte
+
+	// BranchID identifies the branch; 0 to 64 bytes.
+	BranchID []byte
+}
+
 // Tx represents an Oracle transaction associated with a session.
 //
 // Implements the driver.Tx interface.
@@ -42,6 +71,10 @@ type Tx struct {
 	id   uint64
 	ses  *Ses
 	elem *list.Element
+
+	ocitrans   *C.OCITrans // transaction handle of a distributed transaction
+	isPrepared bool
+	isReadOnly bool // prepared without changes; nothing to commit
 }
 
 // checkIsOpen validates that the session is open.
@@ -54,15 +87,28 @@ func (tx *Tx) checkIsOpen() error {
 
 func (tx *Tx) close() {
 	if tx.ses != nil {
+		if tx.ocitrans != nil {
+			// detach the transaction handle from the service context before freeing it
+			env := tx.ses.srv.env
+			env.setAttr(unsafe.Pointer(tx.ses.srv.ocisvcctx), C.OCI_HTYPE_SVCCTX, nil, 0, C.OCI_ATTR_TRANS)
+			env.freeOciHandle(unsafe.Pointer(tx.ocitrans), C.OCI_HTYPE_TRANS)
+			tx.ocitrans = nil
+		}
 		tx.ses.openTxs.Remove(tx.elem)
 		tx.ses = nil
 		tx.elem = nil
+		tx.isPrepared = false
+		tx.isReadOnly = false
 		_drv.txPool.Put(tx)
 	}
 }
 
 // Commit commits the transaction.
 //
+// A distributed transaction branch prepared with Tx.Prepare is committed in
+// the second phase of a two-phase commit; an unprepared branch is committed
+// in one phase.
+//
 // Commit is a member of the driver.Tx interface.
 func (tx *Tx) Commit() (err error) {
 	if tx == nil {
@@ -73,10 +119,17 @@ func (tx *Tx) Commit() (err error) {
 		return err
 	}
 	defer tx.close()
+	if tx.isReadOnly {
+		return nil
+	}
+	var flags C.ub4 = C.OCI_DEFAULT
+	if tx.isPrepared {
+		flags = C.OCI_TRANS_TWOPHASE
+	}
 	r := C.OCITransCommit(
 		tx.ses.srv.ocisvcctx,  //OCISvcCtx    *svchp,
 		tx.ses.srv.env.ocierr, //OCIError     *errhp,
-		C.OCI_DEFAULT)         //ub4          flags );
+		flags)                 //ub4          flags );
 	if r == C.OCI_ERROR {
 		return tx.ses.srv.env.ociError()
 	}
@@ -108,6 +161,54 @@ func (tx *Tx) Rollback() (err error) {
 	return nil
 }
 
+// Prepare prepares a distributed transaction branch started with
+// Ses.StartGlobalTx for commit, the first phase of a two-phase commit.
+//
+// Prepare returns true when the branch made no changes; the branch is then
+// complete and Tx.Commit does nothing. Otherwise, commit the branch with
+// Tx.Commit, or roll it back with Tx.Rollback, once every branch is prepared.
+func (tx *Tx) Prepare() (isReadOnly bool, err error) {
+	tx.log(_drv.cfg.Log.Tx.Prepare)
+	if err = tx.checkIsOpen(); err != nil {
+		return false, errE(err)
+	}
+	if tx.ocitrans == nil {
+		return false, er("Prepare requires a Tx started with Ses.StartGlobalTx.")
+	}
+	r := C.OCITransPrepare(
+		tx.ses.srv.ocisvcctx,  //OCISvcCtx    *svchp,
+		tx.ses.srv.env.ocierr, //OCIError     *errhp,
+		C.OCI_DEFAULT)         //ub4          flags );
+	if r == C.OCI_ERROR {
+		return false, errE(tx.ses.srv.env.ociError())
+	}
+	tx.isPrepared = true
+	// OCI_SUCCESS_WITH_INFO with ORA-24767 reports a read-only branch
+	tx.isReadOnly = r == C.OCI_SUCCESS_WITH_INFO
+	return tx.isReadOnly, nil
+}
+
+// Forget tells the Oracle server to forget a heuristically completed
+// distributed transaction branch, and closes the Tx.
+func (tx *Tx) Forget() (err error) {
+	tx.log(_drv.cfg.Log.Tx.Forget)
+	if err = tx.checkIsOpen(); err != nil {
+		return errE(err)
+	}
+	if tx.ocitrans == nil {
+		return er("Forget requires a Tx started with Ses.StartGlobalTx.")
+	}
+	defer tx.close()
+	r := C.OCITransForget(
+		tx.ses.srv.ocisvcctx,  //OCISvcCtx    *svchp,
+		tx.ses.srv.env.ocierr, //OCIError     *errhp,
+		C.OCI_DEFAULT)         //ub4          flags );
+	if r == C.OCI_ERROR {
+		return errE(tx.ses.srv.env.ociError())
+	}
+	return nil
+}
+
 // sysName returns a string representing the Tx.
 func (tx *Tx) sysName() string {
 	if tx == nil {
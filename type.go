@@ -13,9 +13,11 @@ import "C"
 import (
 	"bytes"
 	"container/list"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"math"
+	"reflect"
 	"sync"
 	"time"
 )
@@ -33,6 +35,22 @@ type bnd interface {
 	close() error
 }
 
+// stringDef is a define able to return its value as a string when the value
+// can't be converted to the define's Go type.
+type stringDef interface {
+	// stringValue gets a String or string value from an Oracle buffer.
+	stringValue() (interface{}, error)
+}
+
+// arrayDef is a define able to fetch a batch of rows per fetch call, as
+// configured by StmtCfg.FetchArraySize.
+type arrayDef interface {
+	// defineArray redefines the column with buffers of rows values.
+	defineArray(rows int) error
+	// setRow selects the buffered row returned by value.
+	setRow(row int)
+}
+
 // def represents a select-list column definition containing logic to transfer
 // an Oracle OCI type to a Go type.
 type def interface {
@@ -176,6 +194,117 @@ func (this Float32) Equals(other Float32) bool {
 		(this.IsNull == other.IsNull && this.Value == other.Value)
 }
 
+// Decimal is a nullable NUMBER value held in its exact decimal text form.
+//
+// Value uses a period as the decimal separator and a leading minus sign for
+// negative numbers, for example "-1234.5600". Decimal values are bound and
+// fetched without conversion through a binary float.
+type Decimal struct {
+	IsNull bool
+	Value  string
+}
+
+// Equals returns true when the receiver and specified Decimal are both null,
+// or when the receiver and specified Decimal are both not null and Values are equal.
+func (this Decimal) Equals(other Decimal) bool {
+	return (this.IsNull && other.IsNull) ||
+		(this.IsNull == other.IsNull && this.Value == other.Value)
+}
+
+// AnyData is a nullable SYS.ANYDATA value holding a scalar.
+//
+// Value is an int64 or float64 for a NUMBER, a string for a VARCHAR2, CHAR or
+// VARCHAR, and a time.Time for a DATE. An AnyData is bound by wrapping Value,
+// which may be any Go integer or float type, a string or a time.Time.
+type AnyData struct {
+	IsNull bool
+	Value  interface{}
+}
+
+// Equals returns true when the receiver and specified AnyData are both null,
+// or when the receiver and specified AnyData are both not null and Values are equal.
+func (this AnyData) Equals(other AnyData) bool {
+	if this.IsNull || other.IsNull {
+		return this.IsNull && other.IsNull
+	}
+	switch value := this.Value.(type) {
+	case time.Time:
+		otherValue, ok := other.Value.(time.Time)
+		return ok && value.Equal(otherValue)
+	case []byte:
+		otherValue, ok := other.Value.([]byte)
+		return ok && bytes.Equal(value, otherValue)
+	}
+	// Values of uncomparable types, such as slices, panic with ==
+	return reflect.DeepEqual(this.Value, other.Value)
+}
+
+// Coll is a nullable VARRAY or nested table value.
+//
+// TypeName is the name of the collection type, such as "NUM_LIST" or
+// "SCOTT.NUM_LIST", and is required to bind a Coll. Value is a slice of a Go
+// integer, float or string type for a collection of NUMBER, or a []string for
+// a collection of VARCHAR2, CHAR or VARCHAR. A Coll with IsNull set or a nil
+// Value is bound as a NULL collection, and an empty slice as an empty
+// collection.
+//
+// A fetched collection of NUMBER is a []int64, or a []float64 when the element
+// type has a positive scale or is a FLOAT, as with NUMBER columns. A fetched
+// empty collection has an empty, non-nil Value, while a NULL collection has
+// IsNull set. NULL elements are fetched as zero values.
+type Coll struct {
+	IsNull   bool
+	TypeName string
+	Value    interface{}
+}
+
+// Out binds a Go pointer as a PL/SQL OUT or IN OUT parameter.
+//
+// Value is an *int64, *float64, *string or *time.Time. The value it points to
+// is sent to the Oracle server, and is updated after Stmt.Exe with the value
+// returned by the server. An OUT parameter ignores the value sent.
+//
+// Value may also be a **Rset for an OUT REF CURSOR parameter. A nil *Rset is
+// allocated, and after Stmt.Exe the *Rset iterates the returned cursor. The
+// *Rset is closed when the Stmt is closed, and re-executing the Stmt closes the
+// previous cursor before opening the new one. IsNull is ignored for a **Rset.
+//
+// IsNull is optional. When not nil, a true value sends a NULL, and after
+// Stmt.Exe it reports whether the server returned a NULL. Value is unchanged
+// when a NULL is returned.
+type Out struct {
+	Value  interface{}
+	IsNull *bool
+}
+
+// PlsArr binds a Go slice as a PL/SQL associative array parameter, such as a
+// TABLE OF NUMBER INDEX BY BINARY_INTEGER, rather than as an array of rows.
+//
+// Value is a []int64 or []string for an IN parameter, or a *[]int64 or
+// *[]string for an OUT or IN OUT parameter. Elements are sent with the
+// indexes 1 through len, and after Stmt.Exe the pointed-to slice holds the
+// elements returned by the server. NULL elements are returned as zero values.
+//
+// Cap is the maximum number of elements the server may return, and defaults
+// to the capacity of the slice. Returned string elements are limited to
+// StmtCfg.StringPtrBufferSize bytes, or to the longest element sent.
+type PlsArr struct {
+	Value interface{}
+	Cap   int
+}
+
+// Named binds Value to the placeholder called Name, with or without its
+// leading colon, rather than by position.
+//
+// Value is any parameter accepted by Stmt.Exe or Stmt.Qry. A placeholder
+// appearing more than once in the SQL, as in "where a = :x or b = :x", is
+// bound once to Value. Pass every parameter of a statement as a Named when
+// passing any.
+type Named struct {
+	Name  string
+	Value interface{}
+}
+
 // Time is a nullable time.Time.
 type Time struct {
 	IsNull bool
@@ -229,8 +358,73 @@ func (this Raw) Equals(other Raw) bool {
 			bytes.Equal(this.Value, other.Value))
 }
 
+// Rowid represents a nullable ROWID or UROWID Oracle value.
+//
+// A ROWID or UROWID select-list column is fetched as a Rowid with the OraRowid
+// GoColumnType. A Rowid parameter is bound as its textual form, which the
+// Oracle server converts to the rowid of the row.
+//
+// Rowid is text-only: it's defined and bound as a string (SQLT_STR and
+// SQLT_CHR) rather than through an OCIRowid descriptor (SQLT_RDD), so a
+// fetched Rowid doesn't carry the binary rowid and binding it costs a
+// server-side conversion.
+//
+// Rowid is comparable and may be used as a map key.
+type Rowid struct {
+	IsNull bool
+	Value  string
+}
+
+// ParseRowid returns a Rowid parsed from the textual form of a ROWID or UROWID.
+//
+// A physical rowid is 18 characters of the Oracle base64 alphabet
+// (A-Z, a-z, 0-9, + and /). A logical rowid begins with an asterisk.
+//
+// Returns an error if the specified string is not a valid rowid.
+func ParseRowid(str string) (Rowid, error) {
+	if str == "" {
+		return Rowid{}, errNew("rowid is empty")
+	}
+	body := str
+	if str[0] == '*' {
+		body = str[1:]
+		if body == "" {
+			return Rowid{}, errNew(fmt.Sprintf("invalid logical rowid %q", str))
+		}
+	} else if len(str) != 18 {
+		return Rowid{}, errNew(fmt.Sprintf("invalid rowid %q: expected 18 characters", str))
+	}
+	for n := 0; n < len(body); n++ {
+		c := body[n]
+		if !('A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '+' || c == '/') {
+			return Rowid{}, errNew(fmt.Sprintf("invalid rowid %q: unexpected character %q", str, c))
+		}
+	}
+	return Rowid{Value: str}, nil
+}
+
+// Equals returns true when the receiver and specified Rowid are both null,
+// or when the receiver and specified Rowid are both not null and Values are equal.
+func (this Rowid) Equals(other Rowid) bool {
+	return (this.IsNull && other.IsNull) ||
+		(this.IsNull == other.IsNull && this.Value == other.Value)
+}
+
+// String returns the textual form of the Rowid; an empty string is returned
+// when the Rowid is null.
+func (this Rowid) String() string {
+	if this.IsNull {
+		return ""
+	}
+	return this.Value
+}
+
 // Lob's Reader is sent to the DB on bind, if not nil.
 // The Reader can read the LOB if we bind a *Lob, Closer will close the LOB.
+//
+// A nil Reader binds a NULL LOB, and a Reader returning no bytes binds an
+// empty LOB. Likewise, a fetched NULL LOB has a nil Reader, and a fetched
+// empty LOB has a Reader which returns io.EOF.
 type Lob struct {
 	io.Reader
 	io.Closer
@@ -257,6 +451,9 @@ func (this Lob) Bytes() ([]byte, error) {
 }
 
 // Bfile represents a nullable BFILE Oracle value.
+//
+// DirectoryAlias and Filename are read from a fetched BFILE locator with
+// OCILobFileGetName. Ses.OpenBfile reads the contents of the file.
 type Bfile struct {
 	IsNull         bool
 	DirectoryAlias string
@@ -314,6 +511,28 @@ func (this IntervalDS) Equals(other IntervalDS) bool {
 			this.Nanosecond == other.Nanosecond)
 }
 
+// NewIntervalDS returns an IntervalDS representing the specified duration.
+//
+// All fields of the returned IntervalDS share the sign of the duration.
+func NewIntervalDS(d time.Duration) IntervalDS {
+	return IntervalDS{
+		Day:        int32(d / (24 * time.Hour)),
+		Hour:       int32(d % (24 * time.Hour) / time.Hour),
+		Minute:     int32(d % time.Hour / time.Minute),
+		Second:     int32(d % time.Minute / time.Second),
+		Nanosecond: int32(d % time.Second),
+	}
+}
+
+// Duration returns the IntervalDS as a time.Duration.
+func (this IntervalDS) Duration() time.Duration {
+	return time.Duration(this.Day)*24*time.Hour +
+		time.Duration(this.Hour)*time.Hour +
+		time.Duration(this.Minute)*time.Minute +
+		time.Duration(this.Second)*time.Second +
+		time.Duration(this.Nanosecond)
+}
+
 // ShiftTime returns a new Time with IntervalDS applied.
 func (this IntervalDS) ShiftTime(t time.Time) time.Time {
 	year, month, day := t.Date()
@@ -321,6 +540,102 @@ func (this IntervalDS) ShiftTime(t time.Time) time.Time {
 	return time.Date(year, month, day+int(this.Day), hour+int(this.Hour), min+int(this.Minute), sec+int(this.Second), t.Nanosecond()+int(this.Nanosecond), t.Location())
 }
 
+// VersionNum represents the numeric components of an Oracle version.
+//
+// For Oracle 18 and later, Minor is the release update, Patchset the release
+// update revision and Patch the increment.
+type VersionNum struct {
+	Major      int
+	Minor      int
+	Patchset   int
+	Patch      int
+	PortUpdate int
+}
+
+// newVersionNum decodes a version number as returned by OCIServerRelease or
+// OCIServerRelease2.
+func newVersionNum(release uint32) VersionNum {
+	v := VersionNum{Major: int(release >> 24 & 0xFF)}
+	if v.Major >= 18 {
+		v.Minor = int(release >> 16 & 0xFF)
+		v.Patchset = int(release >> 12 & 0x0F)
+		v.Patch = int(release >> 4 & 0xFF)
+		v.PortUpdate = int(release & 0x0F)
+	} else {
+		v.Minor = int(release >> 20 & 0x0F)
+		v.Patchset = int(release >> 12 & 0xFF)
+		v.Patch = int(release >> 8 & 0x0F)
+		v.PortUpdate = int(release & 0xFF)
+	}
+	return v
+}
+
+// AtLeast returns true when the version is greater than or equal to the
+// specified major and minor version.
+func (this VersionNum) AtLeast(major, minor int) bool {
+	return this.Major > major || (this.Major == major && this.Minor >= minor)
+}
+
+// String returns the version in dotted form, e.g., 12.1.0.2.0.
+func (this VersionNum) String() string {
+	return fmt.Sprintf("%d.%d.%d.%d.%d", this.Major, this.Minor, this.Patchset, this.Patch, this.PortUpdate)
+}
+
+// Error is an error returned by an Oracle server or the Oracle client, carrying
+// the Oracle error code.
+//
+// Switch on Code rather than matching the error text; for example, Code 1 is
+// an ORA-00001 unique constraint violation. Errors returned by Ses, Stmt and
+// other methods keep the *Error type as caller info is added.
+type Error struct {
+	// Code is the Oracle error code, e.g. 1 for ORA-00001.
+	Code int
+
+	// Text is the Oracle error message, e.g.
+	// "ORA-00001: unique constraint (SCOTT.PK_EMP) violated".
+	Text string
+
+	str string // Text prefixed with caller info
+}
+
+// Error returns the Oracle error message prefixed with caller info.
+//
+// Error is a member of the 'error' interface.
+func (e *Error) Error() string {
+	return e.str
+}
+
+// Timeout returns true when the error is an Oracle call interrupted after
+// exceeding StmtCfg.CallTimeout, ORA-03156.
+func (e *Error) Timeout() bool {
+	return e.Code == 3156
+}
+
+// BatchError is the error of a single row of an array DML statement executed
+// with StmtCfg.IsBatchErrors.
+type BatchError struct {
+	// Index is the index of the failed row in the bound slices.
+	Index int
+
+	// Err is the Oracle error of the row.
+	Err *Error
+}
+
+// BatchErrors is returned by Stmt.Exe when rows of an array DML statement
+// executed with StmtCfg.IsBatchErrors fail. The remaining rows are processed
+// and counted in the rows affected.
+type BatchErrors []BatchError
+
+// Error returns the number of failed rows and the error of the first.
+//
+// Error is a member of the 'error' interface.
+func (b BatchErrors) Error() string {
+	if len(b) == 0 {
+		return "no batch errors"
+	}
+	return fmt.Sprintf("%v row(s) failed; row %v: %v", len(b), b[0].Index, b[0].Err.Text)
+}
+
 // MultiErr holds multiple errors in a single string.
 type MultiErr struct {
 	str string
@@ -1,6 +1,7 @@
 package ora
 
 import (
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"runtime"
@@ -10,23 +11,24 @@ import (
 // checkNumericColumn returns nil when the column type is numeric; otherwise, an error.
 func checkNumericColumn(gct GoColumnType, columnName string) error {
 	switch gct {
-	case I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64, OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32:
+	case I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64, OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32, BigInt, Dec:
 		return nil
 	}
 	if columnName == "" {
-		return errF("Invalid go column type (%v) specified for numeric sql column. Expected go column type I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64, OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64 or OraF32.", GctName(gct))
+		return errF("Invalid go column type (%v) specified for numeric sql column. Expected go column type I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64, OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32, BigInt or Dec.", GctName(gct))
 	} else {
-		return errF("Invalid go column type (%v) specified for numeric sql column (%v). Expected go column type I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64, OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64 or OraF32.", GctName(gct), columnName)
+		return errF("Invalid go column type (%v) specified for numeric sql column (%v). Expected go column type I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64, OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32, BigInt or Dec.", GctName(gct), columnName)
 	}
 }
 
-// checkTimeColumn returns nil when the column type is time; otherwise, an error.
+// checkTimeColumn returns nil when the column type is time or string;
+// otherwise, an error.
 func checkTimeColumn(gct GoColumnType) error {
 	switch gct {
-	case T, OraT:
+	case T, OraT, S, OraS:
 		return nil
 	}
-	return errF("Invalid go column type (%v) specified for time-based sql column. Expected go column type T or OraT.", GctName(gct))
+	return errF("Invalid go column type (%v) specified for time-based sql column. Expected go column type T, OraT, S or OraS.", GctName(gct))
 }
 
 // checkStringColumn returns nil when the column type is string; otherwise, an error.
@@ -38,6 +40,16 @@ func checkStringColumn(gct GoColumnType) error {
 	return errF("Invalid go column type (%v) specified for string-based sql column. Expected go column type S or OraS.", GctName(gct))
 }
 
+// checkRowidColumn returns nil when the column type is a rowid or string;
+// otherwise, an error.
+func checkRowidColumn(gct GoColumnType) error {
+	switch gct {
+	case S, OraRowid:
+		return nil
+	}
+	return errF("Invalid go column type (%v) specified for rowid sql column. Expected go column type S or OraRowid.", GctName(gct))
+}
+
 // checkBoolOrStringColumn returns nil when the column type is bool; otherwise, an error.
 func checkBoolOrStringColumn(gct GoColumnType) error {
 	switch gct {
@@ -125,10 +137,70 @@ func GctName(gct GoColumnType) string {
 		return "Bin"
 	case OraBin:
 		return "OraBin"
+	case Dur:
+		return "Dur"
+	case BigInt:
+		return "BigInt"
+	case Dec:
+		return "Dec"
+	case OraRowid:
+		return "OraRowid"
 	}
 	return ""
 }
 
+// ParallelHint returns the sql statement with a PARALLEL optimizer hint placed
+// after its leading INSERT, UPDATE, DELETE, MERGE or SELECT keyword.
+//
+// A degree less than one requests the default degree of parallelism chosen by
+// the Oracle server. The sql statement is returned unchanged when it doesn't
+// begin with one of the keywords.
+//
+// Parallel DML also requires that parallel DML is enabled for the session;
+// see Ses.EnableParallelDML.
+func ParallelHint(sql string, degree int) string {
+	hint := "/*+ PARALLEL */"
+	if degree > 0 {
+		hint = fmt.Sprintf("/*+ PARALLEL(%d) */", degree)
+	}
+	trimmed := strings.TrimLeft(sql, " \t\r\n")
+	for _, keyword := range []string{"INSERT", "UPDATE", "DELETE", "MERGE", "SELECT"} {
+		if hasPrefixFold(trimmed, keyword) {
+			n := len(sql) - len(trimmed) + len(keyword)
+			return sql[:n] + " " + hint + sql[n:]
+		}
+	}
+	return sql
+}
+
+// isForUpdate returns true when the sql statement contains a FOR UPDATE clause.
+func isForUpdate(sql string) bool {
+	words := strings.Fields(sql)
+	for n := 1; n < len(words); n++ {
+		if strings.EqualFold(words[n-1], "FOR") && hasPrefixFold(words[n], "UPDATE") {
+			return true
+		}
+	}
+	return false
+}
+
+// drcpDblink returns an Easy Connect dblink with the POOLED server type
+// appended. A net service name, a connect descriptor, or a dblink which
+// already specifies a server type is returned unchanged.
+func drcpDblink(dblink string) string {
+	dblink = strings.TrimSpace(dblink)
+	if strings.HasPrefix(dblink, "(") || !strings.Contains(dblink, "/") {
+		return dblink
+	}
+	upper := strings.ToUpper(dblink)
+	for _, serverType := range []string{":POOLED", ":DEDICATED", ":SHARED"} {
+		if strings.HasSuffix(upper, serverType) {
+			return dblink
+		}
+	}
+	return dblink + ":POOLED"
+}
+
 func stringTrimmed(buffer []byte, pad byte) string {
 	// Find length of non-padded string value
 	// String buffer returned from Oracle is padded with Space char (32)
@@ -218,6 +290,14 @@ func er(v ...interface{}) (err error) {
 	return err
 }
 
+// errOci creates an *Error from an Oracle error code and message with caller
+// info.
+func errOci(code int, text string) (err error) {
+	err = &Error{Code: code, Text: strings.TrimSpace(text), str: fmt.Sprintf("%v %v", errInfo(1), text)}
+	_drv.cfg.Log.Logger.Errorln(err)
+	return err
+}
+
 // errF creates a formatted error with caller info.
 func errF(format string, v ...interface{}) (err error) {
 	err = errors.New(fmt.Sprintf("%v %v", errInfo(1), fmt.Sprintf(format, v...)))
@@ -232,8 +312,45 @@ func errR(v ...interface{}) (err error) {
 	return err
 }
 
+// badConnCodes are the Oracle error codes of a session whose connection to
+// the Oracle server is lost, or which the server has ended.
+var badConnCodes = map[int]bool{
+	28:    true, // your session has been killed
+	1012:  true, // not logged on
+	1033:  true, // ORACLE initialization or shutdown in progress
+	1034:  true, // ORACLE not available
+	1089:  true, // immediate shutdown in progress
+	2396:  true, // exceeded maximum idle time
+	3113:  true, // end-of-file on communication channel
+	3114:  true, // not connected to ORACLE
+	3135:  true, // connection lost contact
+	12537: true, // TNS:connection closed
+	12547: true, // TNS:lost contact
+	12570: true, // TNS:packet reader failure
+	12571: true, // TNS:packet writer failure
+}
+
+// badConn returns driver.ErrBadConn for an Oracle error of a lost
+// connection, so that database/sql discards the connection and retries on
+// another; otherwise, err is returned.
+func badConn(err error) error {
+	if oe, ok := err.(*Error); ok && badConnCodes[oe.Code] {
+		return driver.ErrBadConn
+	}
+	return err
+}
+
 // errE wraps an error with caller info.
 func errE(e error) (err error) {
+	if be, ok := e.(BatchErrors); ok { // keep the row errors
+		_drv.cfg.Log.Logger.Errorln(fmt.Sprintf("%v %v", errInfo(1), be.Error()))
+		return be
+	}
+	if oe, ok := e.(*Error); ok { // keep the Oracle error code
+		err = &Error{Code: oe.Code, Text: oe.Text, str: fmt.Sprintf("%v %v", errInfo(1), e.Error())}
+		_drv.cfg.Log.Logger.Errorln(err)
+		return err
+	}
 	err = errors.New(fmt.Sprintf("%v %v", errInfo(1), e.Error()))
 	_drv.cfg.Log.Logger.Errorln(err)
 	return err
@@ -39,6 +39,11 @@
 	#define OCI_ATTR_UB8_ROW_COUNT		OCI_ATTR_ROW_COUNT
 #endif
 
+// OCI_ATTR_SQL_ID is available from 12.2 client libraries
+#ifndef OCI_ATTR_SQL_ID
+	#define OCI_ATTR_SQL_ID				504
+#endif
+
 #if ORACLE_VERSION_HEX >= ORACLE_VERSION(10,1)
 	#define LOB_LENGTH_TYPE				oraub8
 	#define OCILOBGETLENGTH				OCILobGetLength2
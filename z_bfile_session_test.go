@@ -5,7 +5,11 @@
 package ora_test
 
 import (
+	"fmt"
+	"io/ioutil"
 	"testing"
+
+	"gopkg.in/rana/ora.v2"
 )
 
 //// bfile
@@ -29,3 +33,47 @@ func TestBindDefine_OraBfileNull_bfile_null_session(t *testing.T) {
 	//enableLogging(t)
 	testBindDefine(gen_OraBfile(true), bfileNull, t, nil)
 }
+
+func TestSession_OpenBfile(t *testing.T) {
+	dirName := "GO_ORA_BFILE_DIR"
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create or replace directory %v as '/tmp'", dirName))
+	if err != nil {
+		t.Skipf("SKIP create directory: %v", err)
+	}
+	defer testSes.PrepAndExe(fmt.Sprintf("drop directory %v", dirName))
+	fileName := tableName() + ".bin"
+	expected := []byte("bfile \x00\x01\x02 contents")
+	_, err = testSes.PrepAndExe(`declare
+	f utl_file.file_type;
+begin
+	f := utl_file.fopen(:1, :2, 'wb');
+	utl_file.put_raw(f, :3);
+	utl_file.fclose(f);
+end;`, dirName, fileName, expected)
+	if err != nil {
+		t.Skipf("SKIP write file with utl_file: %v", err)
+	}
+	defer testSes.PrepAndExe("begin utl_file.fremove(:1, :2); end;", dirName, fileName)
+
+	rset, err := testSes.PrepAndQry("select bfilename(:1, :2) from dual", dirName, fileName)
+	testErr(err, t)
+	if !rset.Next() {
+		testErr(rset.Err, t)
+		t.Fatal("expected a row")
+	}
+	bfile, ok := rset.Row[0].(ora.Bfile)
+	if !ok {
+		t.Fatalf("expected an ora.Bfile, actual %T", rset.Row[0])
+	}
+	if bfile.IsNull || bfile.DirectoryAlias != dirName || bfile.Filename != fileName {
+		t.Fatalf("expected(%v, %v), actual(%v, %v)", dirName, fileName, bfile.DirectoryAlias, bfile.Filename)
+	}
+	rc, err := testSes.OpenBfile(bfile)
+	testErr(err, t)
+	actual, err := ioutil.ReadAll(rc)
+	testErr(err, t)
+	testErr(rc.Close(), t)
+	if string(actual) != string(expected) {
+		t.Fatalf("expected(%q), actual(%q)", expected, actual)
+	}
+}
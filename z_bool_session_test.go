@@ -4,7 +4,12 @@
 
 package ora_test
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+
+	"gopkg.in/rana/ora.v2"
+)
 
 //// string or bool
 //charB1     oracleColumnType = "char(1 byte) not null"
@@ -192,3 +197,35 @@ func TestWorkload_charC1Null_session(t *testing.T) {
 func TestBindDefine_charC1Null_nil_session(t *testing.T) {
 	testBindDefine(nil, charC1Null, t, nil)
 }
+
+func TestBind_bool_numberP1_numeric_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number(1) null)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	cfg := stmt.Cfg()
+	cfg.IsBoolNumeric = true
+	stmt.SetCfg(cfg)
+	for _, value := range []interface{}{true, false, ora.Bool{IsNull: true}} {
+		_, err = stmt.Exe(value)
+		testErr(err, t)
+	}
+
+	selectStmt, err := testSes.Prep(fmt.Sprintf("select c1 from %v order by c1 nulls last", tableName), ora.OraI64)
+	defer selectStmt.Close()
+	testErr(err, t)
+	rset, err := selectStmt.Qry()
+	testErr(err, t)
+	expected := []ora.Int64{{Value: 0}, {Value: 1}, {IsNull: true}}
+	for rset.Next() {
+		actual := rset.Row[0].(ora.Int64)
+		if !expected[rset.Index].Equals(actual) {
+			t.Fatalf("row %v: expected(%v), actual(%v)", rset.Index, expected[rset.Index], actual)
+		}
+	}
+	testErr(rset.Err, t)
+}
@@ -5,6 +5,10 @@
 package ora_test
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
 	"testing"
 
 	"gopkg.in/rana/ora.v2"
@@ -294,3 +298,172 @@ func TestWorkload_blobNull_session(t *testing.T) {
 func TestBindDefine_blobNull_nil_session(t *testing.T) {
 	testBindDefine(nil, blobNull, t, nil)
 }
+
+func TestBindDefine_Lob_blob_roundTrip_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 blob)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	expected := make([]byte, 10<<20)
+	rand.New(rand.NewSource(1)).Read(expected)
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (:c1, :c2)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	_, err = stmt.Exe(int64(1), ora.Lob{Reader: bytes.NewReader(expected)})
+	testErr(err, t)
+	_, err = stmt.Exe(int64(2), ora.Lob{Reader: bytes.NewReader(nil)}) // empty
+	testErr(err, t)
+	_, err = stmt.Exe(int64(3), ora.Lob{}) // null
+	testErr(err, t)
+
+	qryStmt, err := testSes.Prep(fmt.Sprintf("select c2, dbms_lob.getlength(c2) from %v order by c1", tableName), ora.OraBin, ora.OraI64)
+	defer qryStmt.Close()
+	testErr(err, t)
+	rset, err := qryStmt.Qry()
+	testErr(err, t)
+	for rset.Next() {
+		lob, ok := rset.Row[0].(ora.Lob)
+		if !ok {
+			t.Fatalf("row %v: expected an ora.Lob, actual %T", rset.Index, rset.Row[0])
+		}
+		switch rset.Index {
+		case 0, 1:
+			if lob.Reader == nil {
+				t.Fatalf("row %v: expected a reader for a non-null BLOB", rset.Index)
+			}
+			var actual bytes.Buffer
+			_, err = io.Copy(&actual, lob)
+			testErr(err, t)
+			testErr(lob.Close(), t)
+			if rset.Index == 1 {
+				expected = nil
+			}
+			if !bytes.Equal(expected, actual.Bytes()) {
+				t.Fatalf("row %v: expected %v bytes, actual %v bytes", rset.Index, len(expected), actual.Len())
+			}
+			if rset.Row[1] != (ora.Int64{Value: int64(len(expected))}) {
+				t.Fatalf("row %v: expected length(%v), actual(%v)", rset.Index, len(expected), rset.Row[1])
+			}
+		case 2:
+			if lob.Reader != nil || rset.Row[1] != (ora.Int64{IsNull: true}) {
+				t.Fatalf("expected a NULL BLOB, actual(%v, %v)", lob, rset.Row[1])
+			}
+		}
+	}
+	testErr(rset.Err, t)
+	if rset.Len() != 3 {
+		t.Fatalf("row count: expected(%v), actual(%v)", 3, rset.Len())
+	}
+}
+
+func TestBindSlice_bytes_raw16_batch_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 raw(16))", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	rnd := rand.New(rand.NewSource(1))
+	ids := make([]int64, 1000)
+	keys := make([][]byte, len(ids))
+	for i := range keys {
+		ids[i] = int64(i)
+		switch i % 10 {
+		case 0: // NULL
+		case 1: // shorter than the column
+			keys[i] = []byte{byte(i), byte(i >> 8)}
+		default:
+			keys[i] = make([]byte, 16)
+			rnd.Read(keys[i])
+		}
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (:1, :2)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	rowsAffected, err := stmt.Exe(ids, keys)
+	testErr(err, t)
+	if rowsAffected != uint64(len(ids)) {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", len(ids), rowsAffected)
+	}
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c1, c2 from %v order by c1", tableName))
+	testErr(err, t)
+	for rset.Next() {
+		i := rset.Row[0].(int64)
+		if keys[i] == nil {
+			if rset.Row[1] != nil {
+				t.Fatalf("row %v: expected NULL, actual %v", i, rset.Row[1])
+			}
+			continue
+		}
+		if actual, _ := rset.Row[1].([]byte); !bytes.Equal(actual, keys[i]) {
+			t.Fatalf("row %v: expected(%x), actual(%x)", i, keys[i], actual)
+		}
+	}
+	testErr(rset.Err, t)
+}
+
+func TestBind_bytes_raw16_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 raw(16))", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	guid := make([]byte, 16)
+	rand.New(rand.NewSource(2)).Read(guid)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (1, :1)", tableName), guid)
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (2, :1)", tableName), ora.Raw{Value: bytes.Repeat([]byte{1}, 16)})
+	testErr(err, t)
+
+	// a RAW bind may be compared, unlike a LONG RAW bind
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c1, c2 from %v where c2 = :1", tableName), guid)
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("no row returned: %v", rset.Err)
+	}
+	if id := rset.Row[0].(int64); id != 1 {
+		t.Fatalf("expected row 1, actual row %v", id)
+	}
+	if actual := rset.Row[1].([]byte); !bytes.Equal(actual, guid) {
+		t.Fatalf("expected(%x), actual(%x)", guid, actual)
+	}
+	for rset.Next() {
+	}
+	testErr(rset.Err, t)
+}
+
+func TestBind_bytes_longRawThreshold_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 long raw)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	// longer than a RAW, so bound as a LONG RAW
+	expected := make([]byte, 100000)
+	rand.New(rand.NewSource(3)).Read(expected)
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:1)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	_, err = stmt.Exe(expected)
+	testErr(err, t)
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c1 from %v", tableName))
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("no row returned: %v", rset.Err)
+	}
+	if actual := rset.Row[0].([]byte); !bytes.Equal(actual, expected) {
+		t.Fatalf("expected %v bytes, actual %v bytes", len(expected), len(actual))
+	}
+	for rset.Next() {
+	}
+	testErr(rset.Err, t)
+
+	if err = stmt.Cfg().SetRawThreshold(0); err == nil {
+		t.Fatalf("expected an error for a zero raw threshold")
+	}
+	testErr(stmt.Cfg().SetRawThreshold(16), t)
+	if stmt.Cfg().RawThreshold() != 16 {
+		t.Fatalf("expected raw threshold 16, actual %v", stmt.Cfg().RawThreshold())
+	}
+}
@@ -5,8 +5,14 @@
 package ora_test
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
+
+	"gopkg.in/rana/ora.v2"
 )
 
 func Test_open_cursors_db(t *testing.T) {
@@ -58,7 +64,7 @@ func Test_open_cursors_db(t *testing.T) {
 
 func TestSelectNullString_db(t *testing.T) {
 	enableLogging(t)
-	var s string
+	var s sql.NullString
 	rows, err := testDb.Query("SELECT '' x FROM DUAL")
 	if err != nil {
 		t.Errorf("SELECT '' FROM DUAL: %v", err)
@@ -70,6 +76,9 @@ func TestSelectNullString_db(t *testing.T) {
 			t.Errorf("Scan: %v", err)
 			break
 		}
+		if s.Valid {
+			t.Errorf("expected NULL, actual %q", s.String)
+		}
 	}
 	if rows.Err() != nil {
 		t.Errorf("rows: %v", rows.Err())
@@ -303,3 +312,186 @@ func Test_blob_bytes_db(t *testing.T) {
 func Test_blobNull_bytes_db(t *testing.T) {
 	testBindDefineDB(gen_bytes(9), t, blobNull)
 }
+
+func TestPing_killedSession_db(t *testing.T) {
+	db, err := sql.Open(ora.Name, testConStr)
+	testErr(err, t)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // the killed session is the pool's only connection
+
+	session := func() (sid, serial int64) {
+		err := db.QueryRow("SELECT sid, serial# FROM v$session WHERE sid = sys_context('USERENV', 'SID')").Scan(&sid, &serial)
+		if err != nil {
+			t.Skipf("SKIP query v$session: %v", err)
+		}
+		return sid, serial
+	}
+	sid, serial := session()
+	// This needs "GRANT ALTER SYSTEM TO test".
+	if _, err = testSes.PrepAndExe(fmt.Sprintf("ALTER SYSTEM KILL SESSION '%d,%d' IMMEDIATE", sid, serial)); err != nil {
+		t.Skipf("SKIP kill session: %v", err)
+	}
+	// Ping returns driver.ErrBadConn for the killed session, so database/sql
+	// discards it and pings a new connection
+	if err = db.Ping(); err != nil {
+		t.Fatalf("expected the pool to reconnect, actual(%v)", err)
+	}
+	sid2, serial2 := session()
+	if sid2 == sid && serial2 == serial {
+		t.Fatalf("expected a new session, actual(%v,%v)", sid2, serial2)
+	}
+}
+
+func TestErrBadConn_disconnectCode_db(t *testing.T) {
+	db, err := sql.Open(ora.Name, testConStr)
+	testErr(err, t)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // the broken session is the pool's only connection
+
+	session := func() (sid, serial int64) {
+		err := db.QueryRow("SELECT sid, serial# FROM v$session WHERE sid = sys_context('USERENV', 'SID')").Scan(&sid, &serial)
+		testErr(err, t)
+		return sid, serial
+	}
+	sid, serial := session()
+	// raise ORA-03113 as though the connection were lost; the error is
+	// returned as is, rather than driver.ErrBadConn, so that database/sql
+	// doesn't execute the statement again
+	_, err = db.Exec(`DECLARE
+	e EXCEPTION;
+	PRAGMA EXCEPTION_INIT(e, -3113);
+BEGIN
+	RAISE e;
+END;`)
+	if oe, ok := err.(*ora.Error); !ok || oe.Code != 3113 {
+		t.Fatalf("expected ORA-03113, actual(%v)", err)
+	}
+	// the broken connection is discarded rather than reused
+	sid2, serial2 := session()
+	if sid2 == sid && serial2 == serial {
+		t.Fatalf("expected a new session, actual(%v,%v)", sid2, serial2)
+	}
+	// a call timeout, ORA-03156, may leave the connection unusable, so it is
+	// discarded as well
+	_, err = db.Exec(`DECLARE
+	e EXCEPTION;
+	PRAGMA EXCEPTION_INIT(e, -3156);
+BEGIN
+	RAISE e;
+END;`)
+	if oe, ok := err.(*ora.Error); !ok || !oe.Timeout() {
+		t.Fatalf("expected ORA-03156, actual(%v)", err)
+	}
+	sid, serial = sid2, serial2
+	sid2, serial2 = session()
+	if sid2 == sid && serial2 == serial {
+		t.Fatalf("expected a new session, actual(%v,%v)", sid2, serial2)
+	}
+	// other errors are returned as is and keep the connection
+	_, err = db.Exec("BEGIN RAISE_APPLICATION_ERROR(-20001, 'not a disconnect'); END;")
+	if oe, ok := err.(*ora.Error); !ok || oe.Code != 20001 {
+		t.Fatalf("expected ORA-20001, actual(%v)", err)
+	}
+	sid3, serial3 := session()
+	if sid3 != sid2 || serial3 != serial2 {
+		t.Fatalf("expected session (%v,%v), actual(%v,%v)", sid2, serial2, sid3, serial3)
+	}
+}
+
+func TestNumber_NamedValueChecker_db(t *testing.T) {
+	db, err := sql.Open(ora.Name, testConStr)
+	testErr(err, t)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // the NLS setting is of the pool's only connection
+
+	// a decimal text bound as a string would be converted with a comma
+	// decimal separator and fail; Number and Decimal are bound as OCINumber
+	_, err = db.Exec("ALTER SESSION SET NLS_NUMERIC_CHARACTERS = ',.'")
+	testErr(err, t)
+	var n, d string
+	err = db.QueryRow(`SELECT TO_CHAR(:1 * 2, 'TM9', 'NLS_NUMERIC_CHARACTERS=''.,'''),
+	TO_CHAR(:2 * 2, 'TM9', 'NLS_NUMERIC_CHARACTERS=''.,''') FROM DUAL`,
+		ora.Number("1.25"), ora.Decimal{Value: "-0.5"}).Scan(&n, &d)
+	testErr(err, t)
+	if n != "2.5" || d != "-1" {
+		t.Fatalf("expected(2.5, -1), actual(%v, %v)", n, d)
+	}
+}
+
+func TestConnector_db(t *testing.T) {
+	connector := ora.NewConnector(testConStr)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if _, err := connector.Connect(ctx); err != context.Canceled {
+		t.Fatalf("expected(%v), actual(%v)", context.Canceled, err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected a canceled Connect to return promptly, took %v", d)
+	}
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	if err := db.PingContext(ctx); err != context.Canceled {
+		t.Fatalf("expected(%v), actual(%v)", context.Canceled, err)
+	}
+	var n int64
+	testErr(db.QueryRow("SELECT 1 FROM dual").Scan(&n), t)
+	if n != 1 {
+		t.Fatalf("expected(1), actual(%v)", n)
+	}
+}
+
+func TestColumnTypes_db(t *testing.T) {
+	tableName := tableName()
+	_, err := testDb.Exec(fmt.Sprintf(`create table %v (
+		c1 number(10,2) not null,
+		c2 varchar2(20),
+		c3 date,
+		c4 clob)`, tableName))
+	testErr(err, t)
+	defer dropTableDB(testDb, t, tableName)
+
+	rows, err := testDb.Query(fmt.Sprintf("select c1, c2, c3, c4 from %v", tableName))
+	testErr(err, t)
+	defer rows.Close()
+	columnTypes, err := rows.ColumnTypes()
+	testErr(err, t)
+	expected := []struct {
+		name      string
+		typeName  string
+		scanType  reflect.Type
+		nullable  bool
+		precision int64
+		scale     int64
+		hasScale  bool
+	}{
+		{"C1", "NUMBER", reflect.TypeOf(float64(0)), false, 10, 2, true},
+		{"C2", "VARCHAR2", reflect.TypeOf(""), true, 0, 0, false},
+		{"C3", "DATE", reflect.TypeOf(time.Time{}), true, 0, 0, false},
+		{"C4", "CLOB", reflect.TypeOf(ora.Lob{}), true, 0, 0, false},
+	}
+	if len(columnTypes) != len(expected) {
+		t.Fatalf("expected %v column types, actual(%v)", len(expected), len(columnTypes))
+	}
+	for n, ct := range columnTypes {
+		e := expected[n]
+		if ct.Name() != e.name {
+			t.Errorf("column %v name: expected(%v), actual(%v)", n, e.name, ct.Name())
+		}
+		if ct.DatabaseTypeName() != e.typeName {
+			t.Errorf("%v DatabaseTypeName: expected(%v), actual(%v)", e.name, e.typeName, ct.DatabaseTypeName())
+		}
+		if ct.ScanType() != e.scanType {
+			t.Errorf("%v ScanType: expected(%v), actual(%v)", e.name, e.scanType, ct.ScanType())
+		}
+		if nullable, ok := ct.Nullable(); !ok || nullable != e.nullable {
+			t.Errorf("%v Nullable: expected(%v, true), actual(%v, %v)", e.name, e.nullable, nullable, ok)
+		}
+		precision, scale, ok := ct.DecimalSize()
+		if ok != e.hasScale || precision != e.precision || scale != e.scale {
+			t.Errorf("%v DecimalSize: expected(%v, %v, %v), actual(%v, %v, %v)",
+				e.name, e.precision, e.scale, e.hasScale, precision, scale, ok)
+		}
+	}
+}
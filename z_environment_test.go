@@ -5,6 +5,14 @@
 package ora_test
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"gopkg.in/rana/ora.v2"
@@ -17,6 +25,17 @@ func TestEnv_OpenClose(t *testing.T) {
 	testErr(err, t)
 }
 
+func TestClientVersion(t *testing.T) {
+	major, minor, update, patch, portUpdate := ora.ClientVersion()
+	t.Logf("client version %d.%d.%d.%d.%d", major, minor, update, patch, portUpdate)
+	if major < 11 {
+		t.Fatalf("major client version: expected at least 11, actual(%v)", major)
+	}
+	if minor < 0 || update < 0 || patch < 0 || portUpdate < 0 {
+		t.Fatalf("negative client version component: %d.%d.%d.%d.%d", major, minor, update, patch, portUpdate)
+	}
+}
+
 func TestEnv_IsOpen_opened(t *testing.T) {
 	env, err := ora.OpenEnv(nil)
 	testErr(err, t)
@@ -69,3 +88,406 @@ func TestEnv_OpenCloseCon(t *testing.T) {
 	err = conn.Close()
 	testErr(err, t)
 }
+
+func TestEnv_OpenConWithCfg(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	defer env.Close()
+
+	sesCfg := ora.NewSesCfg()
+	sesCfg.StmtCfg.SetPrefetchRowCount(42)
+	conn, err := env.OpenConWithCfg(testConStr, sesCfg, nil)
+	testErr(err, t)
+	defer conn.Close()
+
+	if sesCfg.Username != "" {
+		t.Fatalf("sesCfg modified: expected empty username, actual(%v)", sesCfg.Username)
+	}
+	err = conn.Ping(context.Background())
+	testErr(err, t)
+}
+
+func TestEnv_TrimIdle(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	defer env.Close()
+	srv, err := env.OpenSrv(testSrvCfg)
+	testErr(err, t)
+	sesCfg := *testSesCfg
+	sesCfg.PrepCacheSize = 2
+	ses, err := srv.OpenSes(&sesCfg)
+	testErr(err, t)
+
+	idle, err := ses.PrepCached("select 1 from dual")
+	testErr(err, t)
+	testErr(idle.Close(), t)
+	inUse, err := ses.PrepCached("select 2 from dual")
+	testErr(err, t)
+
+	before, after, err := env.TrimIdle()
+	testErr(err, t)
+	if before != 2 || after != 1 {
+		t.Fatalf("open Stmts: expected(2, 1), actual(%v, %v)", before, after)
+	}
+	if idle.IsOpen() || !inUse.IsOpen() {
+		t.Fatal("expected only the idle cached Stmt to be closed")
+	}
+	testErr(inUse.Close(), t)
+	if inUse.IsOpen() {
+		t.Fatal("expected the trimmed Stmt in use to close on Close")
+	}
+}
+
+func TestEnv_OpenPool(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	defer env.Close()
+	cfg := ora.NewPoolCfg()
+	cfg.Dblink = testSrvCfg.Dblink
+	cfg.Username = testSesCfg.Username
+	cfg.Password = testSesCfg.Password
+	cfg.Min, cfg.Max, cfg.Incr = 2, 10, 2
+	pool, err := env.OpenPool(cfg)
+	testErr(err, t)
+	defer pool.Close()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sids = make(map[int64]bool)
+		errs = make(chan error, 50)
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ses, err := pool.Get()
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer pool.Put(ses)
+			rset, err := ses.PrepAndQry("SELECT sys_context('USERENV', 'SID') FROM dual")
+			if err != nil {
+				errs <- err
+				return
+			}
+			for rset.Next() {
+				sid, err := strconv.ParseInt(rset.Row[0].(string), 10, 64)
+				if err != nil {
+					errs <- err
+					return
+				}
+				mu.Lock()
+				sids[sid] = true
+				mu.Unlock()
+			}
+			if rset.Err != nil {
+				errs <- rset.Err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if len(sids) == 0 || len(sids) > 10 {
+		t.Fatalf("sessions: expected 1 to 10, actual %v", len(sids))
+	}
+	if n := pool.NumSes(); n != 0 {
+		t.Fatalf("sessions not returned: expected 0, actual %v", n)
+	}
+}
+
+func TestEnv_OpenPool_exhausted(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	defer env.Close()
+	cfg := ora.NewPoolCfg()
+	cfg.Dblink = testSrvCfg.Dblink
+	cfg.Username = testSesCfg.Username
+	cfg.Password = testSesCfg.Password
+	cfg.Min, cfg.Max, cfg.Incr = 1, 1, 1
+	cfg.WaitTimeout = -1
+	pool, err := env.OpenPool(cfg)
+	testErr(err, t)
+
+	ses, err := pool.Get()
+	testErr(err, t)
+	if _, err = pool.Get(); err == nil {
+		t.Fatal("expected an error getting a session from an exhausted pool")
+	}
+	testErr(pool.Put(ses), t)
+	if ses.IsOpen() {
+		t.Fatal("expected the returned Ses to be closed")
+	}
+	ses, err = pool.Get()
+	testErr(err, t)
+	testErr(ses.Ping(), t)
+
+	testErr(env.Close(), t)
+	if pool.IsOpen() {
+		t.Fatal("expected Env.Close to close the pool")
+	}
+}
+
+func TestEnv_OpenPool_closeWhileGetting(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	defer env.Close()
+	cfg := ora.NewPoolCfg()
+	cfg.Dblink = testSrvCfg.Dblink
+	cfg.Username = testSesCfg.Username
+	cfg.Password = testSesCfg.Password
+	cfg.Min, cfg.Max, cfg.Incr = 1, 1, 1
+	cfg.Rewrite = func(sql string) string { return strings.Replace(sql, "{x}", "'pooled'", -1) }
+	pool, err := env.OpenPool(cfg)
+	testErr(err, t)
+
+	ses, err := pool.Get()
+	testErr(err, t)
+	// pooled sessions apply PoolCfg.Rewrite
+	rset, err := ses.PrepAndQry("SELECT {x} FROM dual")
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0] != "pooled" {
+		t.Fatalf("expected(pooled), actual(%v, %v)", rset.Row, rset.Err)
+	}
+
+	// a Get waiting on the exhausted pool fails once the pool closes
+	done := make(chan error, 1)
+	go func() {
+		ses, err := pool.Get()
+		if err == nil {
+			pool.Put(ses)
+		}
+		done <- err
+	}()
+	testErr(pool.Close(), t)
+	if err = <-done; err == nil {
+		t.Fatal("expected an error getting a session from a closed pool")
+	}
+	if n := pool.NumSes(); n != 0 {
+		t.Fatalf("sessions: expected 0, actual %v", n)
+	}
+}
+
+func TestEnv_NCharset(t *testing.T) {
+	cfg := ora.NewEnvCfg()
+	cfg.NCharset = "NO_SUCH_CHARSET"
+	if env, err := ora.OpenEnv(cfg); err == nil {
+		env.Close()
+		t.Fatal("expected an error for an unknown national character set")
+	}
+	cfg.NCharset = "AL16UTF16"
+	if env, err := ora.OpenEnv(cfg); err == nil {
+		env.Close()
+		t.Fatal("expected an error for a national character set other than UTF-8")
+	}
+
+	cfg.NCharset = "AL32UTF8"
+	env, err := ora.OpenEnv(cfg)
+	testErr(err, t)
+	defer env.Close()
+	srv, err := env.OpenSrv(testSrvCfg)
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	tableName, err := createTable(1, nvarchar248, ses)
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+
+	expected := "日本語 Ünïcödé"
+	_, err = ses.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (:1)", tableName), expected)
+	testErr(err, t)
+	rset, err := ses.PrepAndQry(fmt.Sprintf("select c1 from %v", tableName))
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal("expected a row")
+	}
+	if actual := rset.Row[0]; actual != expected {
+		t.Fatalf("expected(%q), actual(%q)", expected, actual)
+	}
+}
+
+func TestEnv_Charset(t *testing.T) {
+	cfg := ora.NewEnvCfg()
+	cfg.Charset = "WE8ISO8859P1"
+	if env, err := ora.OpenEnv(cfg); err == nil {
+		env.Close()
+		t.Fatal("expected an error for a character set other than UTF-8")
+	}
+
+	cfg.Charset = "AL32UTF8"
+	cfg.NCharset = "AL32UTF8"
+	env, err := ora.OpenEnv(cfg)
+	testErr(err, t)
+	defer env.Close()
+	srv, err := env.OpenSrv(testSrvCfg)
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	tableName := tableName()
+	_, err = ses.PrepAndExe(fmt.Sprintf("create table %v (c1 varchar2(100 char), c2 nvarchar2(100))", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+
+	expected := []string{"日本語のテキスト", "Ünïcödé ÄÖÜß", "中文 한국어 Ελληνικά", "😀 emoji"}
+	for _, value := range expected {
+		_, err = ses.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (:1, :2)", tableName), value, value)
+		testErr(err, t)
+	}
+	rset, err := ses.PrepAndQry(fmt.Sprintf("select c1, c2 from %v", tableName))
+	testErr(err, t)
+	actual := make(map[string]bool)
+	for rset.Next() {
+		c1, c2 := rset.Row[0].(string), rset.Row[1].(string)
+		if !bytes.Equal([]byte(c1), []byte(c2)) {
+			t.Fatalf("varchar2 %q and nvarchar2 %q differ", c1, c2)
+		}
+		actual[c1] = true
+	}
+	testErr(rset.Err, t)
+	for _, value := range expected {
+		if !actual[value] {
+			t.Fatalf("expected(%q) among the fetched values %v", value, actual)
+		}
+	}
+}
+
+func TestEnv_Sessions(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	defer env.Close()
+	if n := len(env.Sessions()); n != 0 {
+		t.Fatalf("sessions: expected(0), actual(%v)", n)
+	}
+	srv, err := env.OpenSrv(testSrvCfg)
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	stmt, err := ses.Prep("select 1 from dual")
+	testErr(err, t)
+	defer stmt.Close()
+
+	infos := env.Sessions()
+	if len(infos) != 1 {
+		t.Fatalf("sessions: expected(1), actual(%v)", len(infos))
+	}
+	info := infos[0]
+	if info.Id == 0 || info.Username != testSesCfg.Username || info.Dblink != testSrvCfg.Dblink || info.IsPooled {
+		t.Fatalf("unexpected session info %+v", info)
+	}
+	if info.NumStmt != 1 || info.Opened.IsZero() || info.LastUsed.Before(info.Opened) {
+		t.Fatalf("unexpected session info %+v", info)
+	}
+
+	testErr(ses.Close(), t)
+	if n := len(env.Sessions()); n != 0 {
+		t.Fatalf("sessions after Close: expected(0), actual(%v)", n)
+	}
+}
+
+// captureLogger records the messages logged through ora.Logger.
+type captureLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *captureLogger) add(msg string) {
+	l.mu.Lock()
+	l.messages = append(l.messages, msg)
+	l.mu.Unlock()
+}
+
+func (l *captureLogger) Infof(format string, v ...interface{})  { l.add(fmt.Sprintf(format, v...)) }
+func (l *captureLogger) Infoln(v ...interface{})                { l.add(fmt.Sprintln(v...)) }
+func (l *captureLogger) Warnf(format string, v ...interface{})  { l.add(fmt.Sprintf(format, v...)) }
+func (l *captureLogger) Warnln(v ...interface{})                { l.add(fmt.Sprintln(v...)) }
+func (l *captureLogger) Errorf(format string, v ...interface{}) { l.add(fmt.Sprintf(format, v...)) }
+func (l *captureLogger) Errorln(v ...interface{})               { l.add(fmt.Sprintln(v...)) }
+
+func TestEnv_Logger(t *testing.T) {
+	cfg := ora.Cfg()
+	logger, isLoggingClose := cfg.Log.Logger, cfg.Log.Env.Close
+	defer func() {
+		cfg.Log.Logger, cfg.Log.Env.Close = logger, isLoggingClose
+	}()
+	capture := &captureLogger{}
+	cfg.Log.Logger, cfg.Log.Env.Close = capture, true
+
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	testErr(env.Close(), t)
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	for _, msg := range capture.messages {
+		if strings.Contains(msg, "Env.Close") {
+			return
+		}
+	}
+	t.Fatalf("expected Env.Close to be logged, actual(%q)", capture.messages)
+}
+
+func TestEnv_OpenCon_external(t *testing.T) {
+	// This needs an externally identified account, e.g.
+	// "CREATE USER ops$oracle IDENTIFIED EXTERNALLY", usable from the test's
+	// OS account or wallet for the dblink in GO_ORA_DRV_TEST_EXTERNAL_DB.
+	dblink := os.Getenv("GO_ORA_DRV_TEST_EXTERNAL_DB")
+	if dblink == "" {
+		t.Skip("SKIP external authentication: GO_ORA_DRV_TEST_EXTERNAL_DB is not set")
+	}
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	defer env.Close()
+	con, err := env.OpenCon("/@" + dblink)
+	testErr(err, t)
+	defer con.Close()
+
+	db := sql.OpenDB(ora.NewConnector("/@" + dblink))
+	defer db.Close()
+	var user string
+	testErr(db.QueryRow("SELECT USER FROM dual").Scan(&user), t)
+	if user == "" {
+		t.Fatalf("expected the externally identified user, actual(%q)", user)
+	}
+	t.Logf("connected externally as %v", user)
+}
+
+func TestParseConStr(t *testing.T) {
+	for _, tc := range []struct {
+		str      string
+		expected ora.ConStr
+	}{
+		{"scott/tiger@orcl", ora.ConStr{Username: "scott", Password: "tiger", Dblink: "orcl"}},
+		{"scott/tiger", ora.ConStr{Username: "scott", Password: "tiger"}},
+		{"scott/ti/ger@orcl", ora.ConStr{Username: "scott", Password: "ti/ger", Dblink: "orcl"}},
+		{"scott/ti@ger@orcl", ora.ConStr{Username: "scott", Password: "ti@ger", Dblink: "orcl"}},
+		{`scott/"p@ss/word"@orcl`, ora.ConStr{Username: "scott", Password: "p@ss/word", Dblink: "orcl"}},
+		{`scott/"p@ss/word"`, ora.ConStr{Username: "scott", Password: "p@ss/word"}},
+		{"scott/tiger@dbhost:1521/orclpdb1", ora.ConStr{Username: "scott", Password: "tiger", Dblink: "dbhost:1521/orclpdb1"}},
+		{"scott/tiger@//dbhost/orclpdb1", ora.ConStr{Username: "scott", Password: "tiger", Dblink: "//dbhost/orclpdb1"}},
+		{"scott/tiger@(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=dbhost)(PORT=1521))(CONNECT_DATA=(SERVICE_NAME=orcl)))",
+			ora.ConStr{Username: "scott", Password: "tiger", Dblink: "(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=dbhost)(PORT=1521))(CONNECT_DATA=(SERVICE_NAME=orcl)))"}},
+		{"/@orcl", ora.ConStr{Dblink: "orcl"}},
+		{"/", ora.ConStr{}},
+		{"/ as sysdba", ora.ConStr{Privilege: ora.PrivilegeSysDBA}},
+		{"sys/secret@orcl AS SYSOPER", ora.ConStr{Username: "sys", Password: "secret", Dblink: "orcl", Privilege: ora.PrivilegeSysOper}},
+		{"appserver[scott]/secret@orcl", ora.ConStr{Username: "appserver[scott]", Password: "secret", Dblink: "orcl"}},
+	} {
+		actual, err := ora.ParseConStr(tc.str)
+		if err != nil {
+			t.Errorf("%q: %v", tc.str, err)
+			continue
+		}
+		if actual != tc.expected {
+			t.Errorf("%q: expected(%+v), actual(%+v)", tc.str, tc.expected, actual)
+		}
+	}
+	for _, str := range []string{`scott/"tiger@orcl`, `scott/"tiger"orcl`, "scott/tiger@", "/tiger@orcl"} {
+		if cs, err := ora.ParseConStr(str); err == nil {
+			t.Errorf("%q: expected an error, actual(%+v)", str, cs)
+		}
+	}
+}
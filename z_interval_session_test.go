@@ -5,6 +5,7 @@
 package ora_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -36,6 +37,39 @@ func TestBindDefine_OraIntervalYM_intervalYM_Negative99_session(t *testing.T) {
 	testBindDefine(ora.IntervalYM{Year: -99, Month: -9}, intervalYM, t, nil)
 }
 
+func TestBindDefine_OraIntervalYM_intervalYM_Positive2Y3M_session(t *testing.T) {
+	testBindDefine(ora.IntervalYM{Year: 2, Month: 3}, intervalYM, t, nil)
+}
+
+func TestBindDefine_OraIntervalYM_intervalYM_Negative2Y3M_session(t *testing.T) {
+	testBindDefine(ora.IntervalYM{Year: -2, Month: -3}, intervalYM, t, nil)
+}
+
+func TestBind_OraIntervalYM_intervalYM_literal_session(t *testing.T) {
+	tableName, err := createTable(1, intervalYM, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName), ora.IntervalYM{Year: 2, Month: 3})
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName), ora.IntervalYM{Year: -2, Month: -3})
+	testErr(err, t)
+
+	for literal, expected := range map[string]int64{"'2-3'": 1, "'-2-3'": 1, "'3-2'": 0} {
+		stmt, err := testSes.Prep(fmt.Sprintf("select count(*) from %v where c1 = interval %v year to month", tableName, literal), ora.I64)
+		testErr(err, t)
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatalf("%v: expected a row", literal)
+		}
+		if actual := rset.Row[0].(int64); actual != expected {
+			t.Fatalf("%v: expected(%v), actual(%v)", literal, expected, actual)
+		}
+		stmt.Close()
+	}
+}
+
 func TestBindDefine_OraIntervalYMSlice_intervalYM_session(t *testing.T) {
 	testBindDefine(gen_OraIntervalYMSlice(false), intervalYM, t, nil)
 }
@@ -137,3 +171,50 @@ func TestBindDefine_OraIntervalDS_ShiftTime_session(t *testing.T) {
 		t.Fatalf("expected(%v), actual(%v)", expected, actual)
 	}
 }
+
+func TestBindDefine_duration_intervalDS_session(t *testing.T) {
+	tableName, err := createTable(1, intervalDSNull, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	expected := []time.Duration{
+		0,
+		time.Nanosecond,
+		-time.Nanosecond,
+		49*time.Hour + 59*time.Minute + 59*time.Second + 999999999,
+		-(49*time.Hour + 59*time.Minute + 59*time.Second + 999999999),
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	for _, d := range expected {
+		_, err = stmt.Exe(d)
+		testErr(err, t)
+	}
+	_, err = stmt.Exe(ora.IntervalDS{IsNull: true})
+	testErr(err, t)
+
+	selectStmt, err := testSes.Prep(fmt.Sprintf("select c1 from %v", tableName), ora.Dur)
+	defer selectStmt.Close()
+	testErr(err, t)
+	rset, err := selectStmt.Qry()
+	testErr(err, t)
+	actual := make(map[time.Duration]bool)
+	var nulls int
+	for rset.Next() {
+		if rset.Row[0] == nil {
+			nulls++
+			continue
+		}
+		actual[rset.Row[0].(time.Duration)] = true
+	}
+	testErr(rset.Err, t)
+	for _, d := range expected {
+		if !actual[d] {
+			t.Fatalf("duration %v not fetched; actual(%v)", d, actual)
+		}
+	}
+	if nulls != 1 {
+		t.Fatalf("null count: expected(%v), actual(%v)", 1, nulls)
+	}
+}
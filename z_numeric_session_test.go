@@ -5,6 +5,10 @@
 package ora_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
 	"testing"
 
 	"gopkg.in/rana/ora.v2"
@@ -169,6 +173,27 @@ func TestBindSlice_int8_numberP38S0_session(t *testing.T) {
 	testBindDefine(gen_int8Slice(), numberP38S0, t, nil)
 }
 
+func TestBindSlice_int8_numberP3S0_session(t *testing.T) {
+	testBindDefine([]int8{-128, 127}, numberP3S0, t, nil)
+}
+
+func TestBindDefine_int8_numberP3S0_session(t *testing.T) {
+	testBindDefine(int8(-128), numberP3S0, t, nil)
+	testBindDefine(int8(127), numberP3S0, t, nil)
+}
+
+func TestBindDefine_uint8_numberP5S0_session(t *testing.T) {
+	testBindDefine(uint8(255), numberP5S0, t, nil)
+}
+
+func TestBindDefine_uint16_numberP5S0_session(t *testing.T) {
+	testBindDefine(uint16(65535), numberP5S0, t, nil)
+}
+
+func TestBindSlice_uint16_numberP5S0_session(t *testing.T) {
+	testBindDefine([]uint16{0, 255, 65535}, numberP5S0, t, nil)
+}
+
 func TestBindSlice_uint64_numberP38S0_session(t *testing.T) {
 	testBindDefine(gen_uint64Slice(), numberP38S0, t, nil)
 }
@@ -2442,3 +2467,209 @@ func TestWorkload_floatP126Null_session(t *testing.T) {
 func TestBindDefine_floatP126Null_nil_session(t *testing.T) {
 	testBindDefine(nil, floatP126Null, t, nil)
 }
+
+func TestBindDefine_bigInt_numberP38S0_session(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0Null, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	expected := make([]*big.Int, 0, 3)
+	for _, text := range []string{"12345678901234567890123456789012345678", "-99999999999999999999999999999999999999", "0"} {
+		value, ok := new(big.Int).SetString(text, 10)
+		if !ok {
+			t.Fatalf("invalid big.Int text %v", text)
+		}
+		expected = append(expected, value)
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	for _, value := range expected {
+		_, err = stmt.Exe(value)
+		testErr(err, t)
+	}
+	_, err = stmt.Exe((*big.Int)(nil))
+	testErr(err, t)
+
+	// opt NUMBER columns into big.Int through the statement configuration
+	cfg := ora.NewStmtCfg()
+	testErr(cfg.Rset.SetNumberInt(ora.BigInt), t)
+	selectStmt, err := testSes.Prep(fmt.Sprintf("select c1 from %v where c1 = :c1", tableName))
+	defer selectStmt.Close()
+	testErr(err, t)
+	selectStmt.SetCfg(cfg)
+	for _, value := range expected {
+		rset, err := selectStmt.Qry(value)
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatalf("expected a row for %v", value)
+		}
+		actual, ok := rset.Row[0].(*big.Int)
+		if !ok || actual.Cmp(value) != 0 {
+			t.Fatalf("expected(%v), actual(%v)", value, rset.Row[0])
+		}
+	}
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select count(*) from %v where c1 is null", tableName))
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0] != int64(1) {
+		t.Fatalf("expected one NULL row, actual(%v)", rset.Row)
+	}
+}
+
+func TestBindDefine_decimal_numberP10S4_session(t *testing.T) {
+	tableName, err := createTable(1, numberP10S4Null, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	for _, value := range []string{"0.1", "0.2", "123456.7891", "-0.0001"} {
+		_, err = stmt.Exe(ora.Decimal{Value: value})
+		testErr(err, t)
+	}
+	_, err = stmt.Exe(ora.Decimal{IsNull: true})
+	testErr(err, t)
+
+	// 0.1 + 0.2 stays exact
+	sumStmt, err := testSes.Prep(fmt.Sprintf("select sum(c1) from %v where c1 in (:1, :2)", tableName), ora.Dec)
+	defer sumStmt.Close()
+	testErr(err, t)
+	rset, err := sumStmt.Qry(ora.Decimal{Value: "0.1"}, ora.Decimal{Value: "0.2"})
+	testErr(err, t)
+	if !rset.Next() || !rset.Row[0].(ora.Decimal).Equals(ora.Decimal{Value: "0.3"}) {
+		t.Fatalf("expected(0.3), actual(%v)", rset.Row)
+	}
+
+	// column scale is kept through the statement configuration
+	cfg := ora.NewStmtCfg()
+	testErr(cfg.Rset.SetNumberFloat(ora.Dec), t)
+	selectStmt, err := testSes.Prep(fmt.Sprintf("select c1 from %v order by c1 nulls last", tableName))
+	defer selectStmt.Close()
+	testErr(err, t)
+	selectStmt.SetCfg(cfg)
+	rset, err = selectStmt.Qry()
+	testErr(err, t)
+	expected := []ora.Decimal{{Value: "-0.0001"}, {Value: "0.1000"}, {Value: "0.2000"}, {Value: "123456.7891"}, {IsNull: true}}
+	for _, value := range expected {
+		if !rset.Next() {
+			t.Fatalf("expected a row for %v", value)
+		}
+		if actual := rset.Row[0].(ora.Decimal); !actual.Equals(value) {
+			t.Fatalf("expected(%v), actual(%v)", value, actual)
+		}
+	}
+	testErr(rset.Err, t)
+}
+
+func TestNumber_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number(38,0), c2 number)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	big38 := ora.Number("12345678901234567890123456789012345678")
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (:1, :2)", tableName), big38, ora.Number("-0.000123"))
+	testErr(err, t)
+
+	// ora round trip through a Dec column
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c1 from %v", tableName), ora.Dec)
+	testErr(err, t)
+	var n ora.Number
+	if !rset.Next() {
+		t.Fatalf("expected a row, actual(%v)", rset.Err)
+	}
+	testErr(n.Scan(rset.Row[0]), t)
+	if n != big38 {
+		t.Fatalf("expected(%v), actual(%v)", big38, n)
+	}
+
+	// database/sql round trip through sql.Scanner and driver.Valuer; NUMBER
+	// columns are int64 by default, so the text form keeps every digit
+	var c1, c2 ora.Number
+	var null *ora.Number
+	row := testDb.QueryRow(fmt.Sprintf("select to_char(c1), to_char(c2), null from %v where c1 = :1", tableName), big38)
+	testErr(row.Scan(&c1, &c2, &null), t)
+	if c1 != big38 {
+		t.Fatalf("expected(%v), actual(%v)", big38, c1)
+	}
+	if c2 != "-0.000123" {
+		t.Fatalf("expected(-0.000123), actual(%v)", c2)
+	}
+	if null != nil {
+		t.Fatalf("expected nil, actual(%v)", *null)
+	}
+
+	// arithmetic and comparison stay exact
+	if sum := ora.Number("0.1").Add("0.2"); sum != "0.3" {
+		t.Fatalf("expected(0.3), actual(%v)", sum)
+	}
+	if product := big38.Mul("10").Sub(big38.Mul("9")); product != big38 {
+		t.Fatalf("expected(%v), actual(%v)", big38, product)
+	}
+	if c1.Add("1").Cmp(c1) != 1 || c2.Cmp(c2.Neg()) != -1 {
+		t.Fatalf("unexpected comparison of %v and %v", c1, c2)
+	}
+	if n, err := ora.ParseNumber("+001.2500E+2"); err != nil || n != "125" {
+		t.Fatalf("expected(125), actual(%v, %v)", n, err)
+	}
+
+	// JSON is a numeric literal
+	b, err := json.Marshal(struct{ N ora.Number }{big38})
+	testErr(err, t)
+	if string(b) != `{"N":`+string(big38)+`}` {
+		t.Fatalf("unexpected JSON %s", b)
+	}
+	var decoded struct{ N ora.Number }
+	testErr(json.Unmarshal(b, &decoded), t)
+	if decoded.N != big38 {
+		t.Fatalf("expected(%v), actual(%v)", big38, decoded.N)
+	}
+}
+
+func TestFloat32_bind_noLogging_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 binary_float)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	cfg := ora.Cfg()
+	logger := cfg.Log.Logger
+	defer func() { cfg.Log.Logger = logger }()
+	capture := &captureLogger{}
+	cfg.Log.Logger = capture
+	logged := func(params ...interface{}) int {
+		capture.mu.Lock()
+		capture.messages = capture.messages[:0]
+		capture.mu.Unlock()
+		stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:1)", tableName))
+		testErr(err, t)
+		_, err = stmt.Exe(params...)
+		testErr(err, t)
+		testErr(stmt.Close(), t)
+		capture.mu.Lock()
+		defer capture.mu.Unlock()
+		// no binder logs under the default cfg; a bnd method logs with its
+		// caller info, such as [bndFloat32.bind]
+		for _, message := range capture.messages {
+			if strings.Contains(message, "[bnd") || strings.Contains(message, "position:") {
+				t.Fatalf("expected no bind messages, actual(%q)", message)
+			}
+		}
+		return len(capture.messages)
+	}
+
+	// a float32 bind logs no more than a float64 bind under the default cfg
+	if f32, f64 := logged(float32(1.5)), logged(float64(1.5)); f32 != f64 {
+		t.Fatalf("expected %v messages binding a float32, as for a float64, actual(%v)", f64, f32)
+	}
+	// the number of messages doesn't grow with the number of bound values
+	values := make([]float32, 100000)
+	for n := range values {
+		values[n] = float32(n)
+	}
+	if one, many := logged(values[:1]), logged(values); one != many {
+		t.Fatalf("expected %v messages binding 100000 float32 values, as for one, actual(%v)", one, many)
+	}
+}
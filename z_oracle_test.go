@@ -33,8 +33,11 @@ const (
 	numberP38S0Identity oracleColumnType = "number(38,0) generated always as identity (start with 1 increment by 1)"
 	numberP38S0         oracleColumnType = "number(38,0) not null"
 	numberP38S0Null     oracleColumnType = "number(38,0) null"
+	numberP3S0          oracleColumnType = "number(3,0) not null"
+	numberP5S0          oracleColumnType = "number(5,0) not null"
 	numberP16S15        oracleColumnType = "number(16,15) not null"
 	numberP16S15Null    oracleColumnType = "number(16,15) null"
+	numberP10S4Null     oracleColumnType = "number(10,4) null"
 	binaryDouble        oracleColumnType = "binary_double not null"
 	binaryDoubleNull    oracleColumnType = "binary_double null"
 	binaryFloat         oracleColumnType = "binary_float not null"
@@ -5,7 +5,11 @@
 package ora_test
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"testing"
 
 	"gopkg.in/rana/ora.v2"
@@ -66,3 +70,429 @@ func Test_cursor1_session(t *testing.T) {
 		testErr(rset.Err, t)
 	}
 }
+
+func TestRset_NextMap_session(t *testing.T) {
+	rows, err := testSes.QryMaps("select 1 as a, 'x' as b, 2 as a from dual connect by level <= 2")
+	testErr(err, t)
+	if len(rows) != 2 {
+		t.Fatalf("rows: expected(%v), actual(%v)", 2, len(rows))
+	}
+	for _, row := range rows {
+		if len(row) != 3 || row["A"] != int64(1) || row["B"] != "x" || row["A_2"] != int64(2) {
+			t.Fatalf("unexpected row %v", row)
+		}
+	}
+}
+
+func TestRset_oraRowscn_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 varchar2(48 char)) rowdependencies", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (1, 'a')", tableName))
+	testErr(err, t)
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c2, ora_rowscn from %v where c1 = 1", tableName))
+	testErr(err, t)
+	if !rset.Next() {
+		testErr(rset.Err, t)
+		t.Fatal("expected a row")
+	}
+	scn, ok := rset.Row[1].(uint64)
+	if !ok || scn == 0 {
+		t.Fatalf("expected a uint64 scn, actual %T (%v)", rset.Row[1], rset.Row[1])
+	}
+
+	// the first conditional update succeeds and changes the scn
+	rowsAffected, err := testSes.PrepAndExe(fmt.Sprintf("update %v set c2 = :1 where c1 = 1 and ora_rowscn = :2", tableName), "b", scn)
+	testErr(err, t)
+	if rowsAffected != 1 {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", 1, rowsAffected)
+	}
+	// a stale scn no longer matches
+	rowsAffected, err = testSes.PrepAndExe(fmt.Sprintf("update %v set c2 = :1 where c1 = 1 and ora_rowscn = :2", tableName), "c", scn)
+	testErr(err, t)
+	if rowsAffected != 0 {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", 0, rowsAffected)
+	}
+}
+
+func TestRset_refCursorOut_session(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (:1)", tableName), []int64{1, 2, 3})
+	testErr(err, t)
+	procName := tableName + "_p"
+	_, err = testSes.PrepAndExe(fmt.Sprintf("create or replace procedure %v(p1 in number, p2 out sys_refcursor) as begin open p2 for select c1 from %v where c1 >= p1 order by c1; end;", procName, tableName))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop procedure %v", procName))
+
+	stmt, err := testSes.Prep(fmt.Sprintf("begin %v(:1, :2); end;", procName))
+	testErr(err, t)
+	var rset *ora.Rset
+	_, err = stmt.Exe(int64(2), ora.Out{Value: &rset})
+	testErr(err, t)
+	if rset == nil || !rset.IsOpen() {
+		t.Fatal("expected an open Rset for the REF CURSOR")
+	}
+	var actual []interface{}
+	for rset.Next() {
+		actual = append(actual, rset.Row[0])
+	}
+	testErr(rset.Err, t)
+	if len(actual) != 2 || actual[0] != int64(2) || actual[1] != int64(3) {
+		t.Fatalf("expected [2 3], actual %v", actual)
+	}
+
+	// re-execution resets the Rset to the new cursor
+	first := rset
+	_, err = stmt.Exe(int64(1), ora.Out{Value: &rset})
+	testErr(err, t)
+	if rset != first {
+		t.Fatal("expected the Rset to be reused on re-execution")
+	}
+	rowCount := 0
+	for rset.Next() {
+		rowCount++
+	}
+	testErr(rset.Err, t)
+	if rowCount != 3 {
+		t.Fatalf("re-executed row count: expected(%v), actual(%v)", 3, rowCount)
+	}
+	if stmt.NumRset() != 1 {
+		t.Fatalf("open Rsets: expected(%v), actual(%v)", 1, stmt.NumRset())
+	}
+
+	// closing the Stmt closes the returned Rset
+	testErr(stmt.Close(), t)
+	if rset.IsOpen() {
+		t.Fatal("expected the Rset to be closed with its Stmt")
+	}
+}
+
+func TestRset_clobStream_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 clob)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	// write a multi-megabyte clob on the server
+	const chunkCount, chunkSize = 100, 32000
+	_, err = testSes.PrepAndExe(fmt.Sprintf(`declare
+	c clob;
+begin
+	dbms_lob.createtemporary(c, true);
+	for i in 1..%v loop
+		dbms_lob.writeappend(c, %v, rpad(chr(65 + mod(i, 26)), %v, chr(97 + mod(i, 26))));
+	end loop;
+	insert into %v (c1) values (c);
+	dbms_lob.freetemporary(c);
+end;`, chunkCount, chunkSize, chunkSize, tableName))
+	testErr(err, t)
+	var expected bytes.Buffer
+	for i := 1; i <= chunkCount; i++ {
+		expected.WriteByte(byte('A' + i%26))
+		expected.WriteString(strings.Repeat(string(rune('a'+i%26)), chunkSize-1))
+	}
+
+	// stream the clob in 8KB chunks
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c1 from %v", tableName))
+	testErr(err, t)
+	if !rset.Next() {
+		testErr(rset.Err, t)
+		t.Fatal("expected a row")
+	}
+	lob, ok := rset.Row[0].(ora.Lob)
+	if !ok {
+		t.Fatalf("expected an ora.Lob, actual %T", rset.Row[0])
+	}
+	var actual bytes.Buffer
+	chunk := make([]byte, 8192)
+	for {
+		n, err := lob.Read(chunk)
+		actual.Write(chunk[:n])
+		if err == io.EOF {
+			break
+		}
+		testErr(err, t)
+	}
+	testErr(lob.Close(), t)
+	if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+		t.Fatalf("streamed clob: expected %v bytes, actual %v bytes", expected.Len(), actual.Len())
+	}
+
+	// materialize the clob as a string
+	stmt, err := testSes.Prep(fmt.Sprintf("select c1 from %v", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	stmt.Cfg().IsMaterializingLobs = true
+	rset, err = stmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		testErr(rset.Err, t)
+		t.Fatal("expected a row")
+	}
+	str, ok := rset.Row[0].(string)
+	if !ok {
+		t.Fatalf("expected a string, actual %T", rset.Row[0])
+	}
+	if str != expected.String() {
+		t.Fatalf("materialized clob: expected %v bytes, actual %v bytes", expected.Len(), len(str))
+	}
+}
+
+func TestRset_CurrentRowid_session(t *testing.T) {
+	tableName, err := createTable(2, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (:1, 0)", tableName), []int64{1, 2, 3, 4, 5})
+	testErr(err, t)
+
+	tx, err := testSes.StartTx()
+	testErr(err, t)
+	defer tx.Rollback()
+	stmt, err := testSes.Prep(fmt.Sprintf("select c1 from %v order by c1 for update", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	testErr(stmt.Cfg().SetPrefetchRowCount(10), t)
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	updStmt, err := testSes.Prep(fmt.Sprintf("update %v set c2 = c1 * 10 where rowid = :1", tableName))
+	defer updStmt.Close()
+	testErr(err, t)
+	for rset.Next() {
+		if rset.Row[0].(int64)%2 != 0 {
+			continue
+		}
+		rowid, err := rset.CurrentRowid()
+		testErr(err, t)
+		rowsAffected, err := updStmt.Exe(rowid)
+		testErr(err, t)
+		if rowsAffected != 1 {
+			t.Fatalf("rows affected: expected(%v), actual(%v)", 1, rowsAffected)
+		}
+	}
+	testErr(rset.Err, t)
+	testErr(tx.Commit(), t)
+
+	rset, err = testSes.PrepAndQry(fmt.Sprintf("select c1, c2 from %v order by c1", tableName))
+	testErr(err, t)
+	for rset.Next() {
+		c1 := rset.Row[0].(int64)
+		expected := int64(0)
+		if c1%2 == 0 {
+			expected = c1 * 10
+		}
+		if rset.Row[1] != expected {
+			t.Fatalf("c2 for c1 %v: expected(%v), actual(%v)", c1, expected, rset.Row[1])
+		}
+	}
+	testErr(rset.Err, t)
+}
+
+func TestRset_IsFallingBackToString_session(t *testing.T) {
+	qry := `select v from (
+	select 1 n, 1 v from dual union all
+	select 2, 1e30 from dual union all
+	select 3, 2 from dual) order by n`
+
+	// without the fallback an out of range value fails the fetch
+	rset, err := testSes.PrepAndQry(qry, ora.I64)
+	testErr(err, t)
+	for rset.Next() {
+	}
+	if rset.Err == nil {
+		t.Fatalf("expected a conversion error for 1e30")
+	}
+
+	stmt, err := testSes.Prep(qry, ora.I64)
+	defer stmt.Close()
+	testErr(err, t)
+	stmt.Cfg().Rset.IsFallingBackToString = true
+	rset, err = stmt.Qry()
+	testErr(err, t)
+	var values []interface{}
+	for rset.Next() {
+		values = append(values, rset.Row[0])
+	}
+	testErr(rset.Err, t)
+	// the column remains a string once it falls back
+	expected := []interface{}{int64(1), "1000000000000000000000000000000", "2"}
+	if len(values) != len(expected) {
+		t.Fatalf("expected(%v), actual(%v)", expected, values)
+	}
+	for n := range expected {
+		if values[n] != expected[n] {
+			t.Fatalf("row %v: expected(%#v), actual(%#v)", n, expected[n], values[n])
+		}
+	}
+}
+
+func TestRset_FetchArraySize_session(t *testing.T) {
+	const rows = 100000
+	stmt, err := testSes.Prep(`select level, to_char(level),
+	case when mod(level, 7) = 0 then null else level end
+	from dual connect by level <= `+strconv.Itoa(rows), ora.I64, ora.S, ora.OraI64)
+	defer stmt.Close()
+	testErr(err, t)
+	// 999 doesn't divide the row count, so the final batch is partial
+	if err = stmt.Cfg().SetFetchArraySize(999); err != nil {
+		t.Fatal(err)
+	}
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	var n int64
+	for rset.Next() {
+		n++
+		if rset.Row[0] != n {
+			t.Fatalf("row %v: expected(%v), actual(%#v)", n, n, rset.Row[0])
+		}
+		if rset.Row[1] != strconv.FormatInt(n, 10) {
+			t.Fatalf("row %v: expected(%q), actual(%#v)", n, strconv.FormatInt(n, 10), rset.Row[1])
+		}
+		expected := ora.Int64{Value: n}
+		if n%7 == 0 {
+			expected = ora.Int64{IsNull: true}
+		}
+		if rset.Row[2] != expected {
+			t.Fatalf("row %v: expected(%v), actual(%#v)", n, expected, rset.Row[2])
+		}
+	}
+	testErr(rset.Err, t)
+	if n != rows || rset.Len() != rows {
+		t.Fatalf("expected %v rows, actual(%v, Len %v)", rows, n, rset.Len())
+	}
+	if rset.Next() {
+		t.Fatalf("expected no row after the last batch")
+	}
+}
+
+func TestRset_scrollable_session(t *testing.T) {
+	qry := "select level, 'row ' || level from dual connect by level <= 100"
+	// read sequentially for a baseline
+	rset, err := testSes.PrepAndQry(qry, ora.I64, ora.S)
+	testErr(err, t)
+	var baseline [][]interface{}
+	for rset.Next() {
+		baseline = append(baseline, []interface{}{rset.Row[0], rset.Row[1]})
+	}
+	testErr(rset.Err, t)
+	if len(baseline) != 100 {
+		t.Fatalf("expected 100 rows, actual(%v)", len(baseline))
+	}
+
+	stmt, err := testSes.Prep(qry, ora.I64, ora.S)
+	defer stmt.Close()
+	testErr(err, t)
+	stmt.Cfg().IsScrollable = true
+	rset, err = stmt.Qry()
+	testErr(err, t)
+	check := func(name string, ok bool, index int) {
+		if !ok {
+			t.Fatalf("%v: no row (%v)", name, rset.Err)
+		}
+		if rset.Index != index {
+			t.Fatalf("%v: expected index %v, actual(%v)", name, index, rset.Index)
+		}
+		if rset.Row[0] != baseline[index][0] || rset.Row[1] != baseline[index][1] {
+			t.Fatalf("%v: expected(%v), actual(%v)", name, baseline[index], rset.Row)
+		}
+	}
+	check("Seek(49)", rset.Seek(49), 49)
+	check("Seek(9)", rset.Seek(9), 9)
+	check("Next", rset.Next(), 10)
+	check("Last", rset.Last(), 99)
+	check("Prev", rset.Prev(), 98)
+	check("First", rset.First(), 0)
+	if rset.Prev() {
+		t.Fatalf("expected no row before the first row")
+	}
+	testErr(rset.Err, t)
+	if rset.Seek(100) {
+		t.Fatalf("expected no row after the last row")
+	}
+	testErr(rset.Err, t)
+	check("Seek(0)", rset.Seek(0), 0)
+}
+
+func TestRset_Columns_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf(`create table %v (
+		c1 number(10,2) not null,
+		c2 varchar2(20),
+		c3 nvarchar2(10),
+		c4 char(3) not null,
+		c5 date,
+		c6 clob,
+		c7 binary_double,
+		c8 raw(16),
+		c9 number,
+		c10 float(63))`, tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select * from %v", tableName))
+	testErr(err, t)
+	expected := []ora.Column{
+		{Name: "C1", Type: "NUMBER", Size: 22, Precision: 10, Scale: 2},
+		{Name: "C2", Type: "VARCHAR2", Size: 20, IsNullable: true},
+		{Name: "C3", Type: "NVARCHAR2", IsNullable: true},
+		{Name: "C4", Type: "CHAR", Size: 3},
+		{Name: "C5", Type: "DATE", Size: 7, IsNullable: true},
+		{Name: "C6", Type: "CLOB", IsNullable: true},
+		{Name: "C7", Type: "BINARY_DOUBLE", Size: 8, IsNullable: true},
+		{Name: "C8", Type: "RAW", Size: 16, IsNullable: true},
+		{Name: "C9", Type: "NUMBER", Size: 22, Scale: -127, IsNullable: true},
+		{Name: "C10", Type: "FLOAT", Size: 22, Precision: 63, Scale: -127, IsNullable: true},
+	}
+	columns := rset.Columns()
+	if len(columns) != len(expected) {
+		t.Fatalf("expected %v columns, actual(%v)", len(expected), len(columns))
+	}
+	for n, column := range columns {
+		if expected[n].Size == 0 {
+			// the byte sizes of NVARCHAR2 and CLOB columns vary by release and
+			// character set
+			column.Size = 0
+		}
+		if column != expected[n] {
+			t.Errorf("column %v: expected(%+v), actual(%+v)", n, expected[n], column)
+		}
+	}
+	for rset.Next() {
+	}
+	testErr(rset.Err, t)
+}
+
+func TestRset_nullsAreNil_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf(`create table %v (c0 number, c1 number(10), c2 number(10,2),
+	c3 binary_double, c4 varchar2(10), c5 char(1), c6 nvarchar2(10), c7 date, c8 timestamp,
+	c9 raw(10), c10 clob, c11 blob, c12 long, c13 number(10))`, tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c0) values (1)", tableName))
+	testErr(err, t)
+
+	query := fmt.Sprintf("select c1, c2, c3, c4, c5, c6, c7, c8, c9, c10, c11, c12, c13, cast(null as rowid) from %v", tableName)
+	for _, isMaterializingLobs := range []bool{false, true} {
+		stmt, err := testSes.Prep(query,
+			ora.D, ora.D, ora.D, ora.D, ora.B, ora.D, ora.D, ora.D, ora.D, ora.D, ora.D, ora.D, ora.U64, ora.D)
+		testErr(err, t)
+		stmt.Cfg().IsMaterializingLobs = isMaterializingLobs
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		if !rset.Next() {
+			testErr(rset.Err, t)
+			t.Fatal("expected a row")
+		}
+		for n, value := range rset.Row {
+			if value != nil {
+				t.Errorf("materializing lobs %v: %v (%v): expected nil, actual %T (%v)",
+					isMaterializingLobs, rset.ColumnNames[n], rset.Columns()[n].Type, value, value)
+			}
+		}
+		stmt.Close()
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+
+	"gopkg.in/rana/ora.v2"
 )
 
 // test on heap table to retreive ROWID
@@ -98,3 +100,90 @@ func testRowid(isUrowid bool, t *testing.T) {
 		}
 	}
 }
+
+func TestRowid_Parse_Equals(t *testing.T) {
+	rset, err := testSes.PrepAndQry("select rowid from dual")
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("no row returned")
+	}
+	testErr(rset.Err, t)
+	rowid, err := ora.ParseRowid(rset.Row[0].(string))
+	testErr(err, t)
+	for rset.Next() {
+	}
+	other, err := ora.ParseRowid(rowid.String())
+	testErr(err, t)
+	if !rowid.Equals(other) {
+		t.Fatalf("rowid equality: expected(%v), actual(%v)", rowid, other)
+	}
+	seen := map[ora.Rowid]bool{rowid: true}
+	if !seen[other] {
+		t.Fatalf("rowid map key: expected(%v) to be found", other)
+	}
+	if _, err = ora.ParseRowid("not a rowid"); err == nil {
+		t.Fatalf("expected error parsing invalid rowid")
+	}
+}
+
+func TestRowid_update(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 varchar2(48 byte))", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) select level, 'go' from dual connect by level <= 3", tableName))
+	testErr(err, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("select rowid, c1 from %v order by c1", tableName), ora.OraRowid, ora.I64)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	rowids := make(map[int64]ora.Rowid)
+	for rset.Next() {
+		rowid, ok := rset.Row[0].(ora.Rowid)
+		if !ok {
+			t.Fatalf("expected ora.Rowid, actual %T", rset.Row[0])
+		}
+		if _, err = ora.ParseRowid(rowid.String()); err != nil {
+			t.Fatal(err)
+		}
+		rowids[rset.Row[1].(int64)] = rowid
+	}
+	testErr(rset.Err, t)
+
+	// the rowid of the second row updates only that row
+	rowsAffected, err := testSes.PrepAndExe(fmt.Sprintf("update %v set c2 = 'go go go' where rowid = :1", tableName), rowids[2])
+	testErr(err, t)
+	if rowsAffected != 1 {
+		t.Fatalf("update rows affected: expected(%v), actual(%v)", 1, rowsAffected)
+	}
+	rset, err = testSes.PrepAndQry(fmt.Sprintf("select c1, c2 from %v order by c1", tableName))
+	testErr(err, t)
+	for rset.Next() {
+		expected := "go"
+		if rset.Row[0].(int64) == 2 {
+			expected = "go go go"
+		}
+		if rset.Row[1].(string) != expected {
+			t.Fatalf("row %v: expected(%v), actual(%v)", rset.Row[0], expected, rset.Row[1])
+		}
+	}
+	testErr(rset.Err, t)
+
+	// a NULL rowid
+	stmt, err = testSes.Prep("select cast(null as rowid) from dual", ora.OraRowid)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err = stmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("no row returned")
+	}
+	if rowid := rset.Row[0].(ora.Rowid); !rowid.IsNull {
+		t.Fatalf("expected a null rowid, actual(%v)", rowid)
+	}
+	for rset.Next() {
+	}
+	testErr(rset.Err, t)
+}
@@ -5,7 +5,12 @@
 package ora_test
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/rana/ora.v2"
 )
@@ -61,4 +66,289 @@ func TestServer_Version(t *testing.T) {
 	if version == "" {
 		t.Fatal("Version is empty.")
 	}
+	rset, err := ses.PrepAndQry("SELECT banner FROM v$version WHERE banner LIKE 'Oracle%'")
+	testErr(err, t)
+	for rset.Next() {
+		if banner := rset.Row[0].(string); !strings.HasPrefix(version, banner) {
+			t.Fatalf("expected version %q to start with v$version banner %q", version, banner)
+		}
+	}
+	testErr(rset.Err, t)
+}
+
+func TestServer_VersionNum(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	version, err := srv.VersionNum()
+	testErr(err, t)
+	if version.Major < 9 {
+		t.Fatalf("major version: expected at least 9, actual(%v)", version)
+	}
+	if !version.AtLeast(version.Major, version.Minor) || version.AtLeast(version.Major+1, 0) {
+		t.Fatalf("AtLeast is inconsistent with %v", version)
+	}
+}
+
+func TestSes_ServerVersion(t *testing.T) {
+	version, err := testSes.ServerVersion()
+	testErr(err, t)
+	rset, err := testSes.PrepAndQry("SELECT banner FROM v$version WHERE banner LIKE 'Oracle%'")
+	testErr(err, t)
+	if !rset.Next() {
+		testErr(rset.Err, t)
+		t.Fatal("no Oracle banner in v$version")
+	}
+	banner := rset.Row[0].(string)
+	for rset.Next() {
+	}
+	testErr(rset.Err, t)
+	// from 18c, the banner holds the base release, e.g. 19.0.0.0.0
+	release := fmt.Sprintf("Release %d.", version.Major)
+	if !strings.Contains(banner, release) {
+		t.Fatalf("expected v$version banner %q to contain %q (%v)", banner, release, version)
+	}
+}
+
+func TestServer_DbCharset(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	defer env.Close()
+	testErr(err, t)
+	// dbCharset opens a Srv to dblink, returning its DbCharset and the
+	// character set queried by its session
+	dbCharset := func(dblink string) (charset, expected string) {
+		srv, err := env.OpenSrv(&ora.SrvCfg{Dblink: dblink})
+		testErr(err, t)
+		defer srv.Close()
+		if charset := srv.DbCharset(); charset != "" {
+			t.Fatalf("expected no charset before a session, actual(%v)", charset)
+		}
+		ses, err := srv.OpenSes(testSesCfg)
+		testErr(err, t)
+		rset, err := ses.PrepAndQry("SELECT value FROM nls_database_parameters WHERE parameter = 'NLS_CHARACTERSET'")
+		testErr(err, t)
+		row := rset.NextRow()
+		testErr(rset.Err, t)
+		return srv.DbCharset(), row[0].(string)
+	}
+
+	charset, expected := dbCharset(testSrvCfg.Dblink)
+	if charset != expected {
+		t.Fatalf("expected(%v), actual(%v)", expected, charset)
+	}
+
+	// This needs a Dblink in GO_ORA_DRV_TEST_CHARSET_DB to a database with a
+	// different character set, e.g. WE8ISO8859P1, and the test user.
+	dblink := os.Getenv("GO_ORA_DRV_TEST_CHARSET_DB")
+	if dblink == "" {
+		t.Skip("SKIP second charset: GO_ORA_DRV_TEST_CHARSET_DB is not set")
+	}
+	otherCharset, otherExpected := dbCharset(dblink)
+	if otherCharset != otherExpected {
+		t.Fatalf("expected(%v), actual(%v)", otherExpected, otherCharset)
+	}
+	if otherCharset == charset {
+		t.Fatalf("expected databases with different character sets, both are %v", charset)
+	}
+	// a Srv reattaching to the first database determines its charset anew
+	if charset, _ = dbCharset(testSrvCfg.Dblink); charset != expected {
+		t.Fatalf("reattached: expected(%v), actual(%v)", expected, charset)
+	}
+}
+
+func TestServer_OpenSesContext(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+
+	ses, err := srv.OpenSesContext(context.Background(), testSesCfg)
+	testErr(err, t)
+	err = ses.Close()
+	testErr(err, t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ses, err = srv.OpenSesContext(ctx, testSesCfg)
+	if err != context.Canceled {
+		t.Fatalf("canceled open: expected(%v), actual(%v)", context.Canceled, err)
+	}
+	if ses != nil {
+		t.Fatal("canceled open: expected nil session")
+	}
+}
+
+func TestServer_DRCP(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	testErr(err, t)
+	defer env.Close()
+	srvCfg := *testSrvCfg
+	srvCfg.IsDRCP = true
+	srv, err := env.OpenSrv(&srvCfg)
+	if err != nil {
+		t.Skipf("SKIP attach to a DRCP server: %v", err)
+	}
+	defer srv.Close()
+
+	openSes := func(purity ora.Purity) *ora.Ses {
+		sesCfg := *testSesCfg
+		sesCfg.ConnectionClass = "GO_ORA_TEST"
+		sesCfg.Purity = purity
+		ses, err := srv.OpenSes(&sesCfg)
+		if err != nil {
+			t.Skipf("SKIP open a DRCP session: %v", err)
+		}
+		return ses
+	}
+	clientInfo := func(ses *ora.Ses) string {
+		rset, err := ses.PrepAndQry("SELECT NVL(sys_context('USERENV', 'CLIENT_INFO'), '-') FROM dual")
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatal("expected a row")
+		}
+		return rset.Row[0].(string)
+	}
+
+	ses := openSes(ora.PuritySelf)
+	rset, err := ses.PrepAndQry("SELECT server FROM v$session WHERE sid = sys_context('USERENV', 'SID')")
+	if err != nil {
+		t.Skipf("SKIP query v$session: %v", err)
+	}
+	if !rset.Next() || rset.Row[0] != "POOLED" {
+		t.Skipf("SKIP DRCP isn't enabled for %q", srvCfg.Dblink)
+	}
+	_, err = ses.PrepAndExe("BEGIN dbms_application_info.set_client_info('go-ora-drcp'); END;")
+	testErr(err, t)
+	testErr(ses.Close(), t)
+
+	// a new session doesn't carry the state of a previous use
+	ses = openSes(ora.PurityNew)
+	if info := clientInfo(ses); info != "-" {
+		t.Fatalf("PurityNew client info: expected none, actual %q", info)
+	}
+	testErr(ses.Close(), t)
+
+	// a reused session may carry the state of a previous use of its connection class
+	ses = openSes(ora.PuritySelf)
+	if info := clientInfo(ses); info != "go-ora-drcp" {
+		t.Logf("PuritySelf was given another pooled server (client info %q)", info)
+	}
+	testErr(ses.Close(), t)
+}
+
+func TestServer_failoverCallback(t *testing.T) {
+	var actualSrv *ora.Srv
+	var actualType ora.FailoverType
+	var actualEvent ora.FailoverEvent
+	retry := true
+	fn := func(srv *ora.Srv, failoverType ora.FailoverType, event ora.FailoverEvent) bool {
+		actualSrv, actualType, actualEvent = srv, failoverType, event
+		return retry
+	}
+	const ociFoRetry = 25410
+	for _, c := range []struct {
+		failoverType ora.FailoverType
+		event        ora.FailoverEvent
+		retry        bool
+		expected     int32
+	}{
+		{ora.FailoverSelect, ora.FailoverBegin, true, 0},
+		{ora.FailoverSelect, ora.FailoverError, true, ociFoRetry},
+		{ora.FailoverSession, ora.FailoverError, false, 0},
+		{ora.FailoverTransactional, ora.FailoverEnd, true, 0},
+		{ora.FailoverNone, ora.FailoverAbort, true, 0},
+		{ora.FailoverSession, ora.FailoverReauth, false, 0},
+	} {
+		retry = c.retry
+		actual := ora.Failover(fn, testSrv, uint32(c.failoverType), uint32(c.event))
+		if actual != c.expected {
+			t.Errorf("%v %v retry %v: expected(%v), actual(%v)", c.failoverType, c.event, c.retry, c.expected, actual)
+		}
+		if actualSrv != testSrv || actualType != c.failoverType || actualEvent != c.event {
+			t.Errorf("expected(%p, %v, %v), actual(%p, %v, %v)", testSrv, c.failoverType, c.event, actualSrv, actualType, actualEvent)
+		}
+	}
+	if s := ora.FailoverEvent(0).String(); s != "FailoverEvent(0)" {
+		t.Errorf("expected(FailoverEvent(0)), actual(%v)", s)
+	}
+	if s := ora.FailoverSelect.String(); s != "SELECT" {
+		t.Errorf("expected(SELECT), actual(%v)", s)
+	}
+}
+
+func TestServer_RegisterFailover(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	events := make(chan ora.FailoverEvent, 16)
+	testErr(srv.RegisterFailover(func(srv *ora.Srv, failoverType ora.FailoverType, event ora.FailoverEvent) bool {
+		events <- event
+		return false
+	}), t)
+	testErr(srv.RegisterFailover(nil), t)
+	testErr(srv.RegisterFailover(func(srv *ora.Srv, failoverType ora.FailoverType, event ora.FailoverEvent) bool {
+		events <- event
+		return false
+	}), t)
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	testErr(ses.Ping(), t)
+	select {
+	case event := <-events:
+		t.Fatalf("expected no failover, actual(%v)", event)
+	default:
+	}
+}
+
+func TestServer_RegisterFailover_taf(t *testing.T) {
+	// This needs a Dblink in GO_ORA_DRV_TEST_TAF_DB whose connect descriptor
+	// specifies a FAILOVER_MODE, e.g. (FAILOVER_MODE=(TYPE=SELECT)(METHOD=BASIC)),
+	// and "GRANT ALTER SYSTEM TO test" to disconnect the session.
+	dblink := os.Getenv("GO_ORA_DRV_TEST_TAF_DB")
+	if dblink == "" {
+		t.Skip("SKIP failover: GO_ORA_DRV_TEST_TAF_DB is not set")
+	}
+	env, err := ora.OpenEnv(nil)
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(&ora.SrvCfg{Dblink: dblink})
+	defer srv.Close()
+	testErr(err, t)
+	var events []ora.FailoverEvent
+	testErr(srv.RegisterFailover(func(srv *ora.Srv, failoverType ora.FailoverType, event ora.FailoverEvent) bool {
+		events = append(events, event)
+		if event == ora.FailoverError {
+			time.Sleep(time.Second)
+			return true
+		}
+		return false
+	}), t)
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+
+	rset, err := ses.PrepAndQry("SELECT sid, serial# FROM v$session WHERE sid = sys_context('USERENV', 'SID')")
+	testErr(err, t)
+	row := rset.NextRow()
+	testErr(rset.Err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("ALTER SYSTEM DISCONNECT SESSION '%v,%v' IMMEDIATE", row[0], row[1]))
+	testErr(err, t)
+	// the next call fails over
+	testErr(ses.Ping(), t)
+	if len(events) < 2 || events[0] != ora.FailoverBegin || events[len(events)-1] != ora.FailoverEnd {
+		t.Fatalf("expected BEGIN ... END events, actual(%v)", events)
+	}
 }
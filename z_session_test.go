@@ -5,8 +5,13 @@
 package ora_test
 
 import (
+	"database/sql"
 	"fmt"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/rana/ora.v2"
 )
@@ -161,6 +166,44 @@ func TestSession_Tx_StartRollback(t *testing.T) {
 	}
 }
 
+func TestSession_Tx_Savepoint(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	tx, err := testSes.StartTx()
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (9)", tableName))
+	testErr(err, t)
+	err = testSes.Savepoint("sp_9")
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (11)", tableName))
+	testErr(err, t)
+	err = testSes.RollbackTo("sp_9")
+	testErr(err, t)
+	err = testSes.ReleaseSavepoint("sp_9")
+	testErr(err, t)
+	err = tx.Commit()
+	testErr(err, t)
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c1 from %v", tableName))
+	testErr(err, t)
+	for rset.Next() {
+		if rset.Row[0].(int64) != 9 {
+			t.Fatalf("expected(9), actual(%v)", rset.Row[0])
+		}
+	}
+	if 1 != rset.Len() {
+		t.Fatalf("row count: expected(%v), actual(%v)", 1, rset.Len())
+	}
+
+	for _, name := range []string{"", "1sp", "sp; drop table x", `"sp"`} {
+		if err = testSes.Savepoint(name); err == nil {
+			t.Fatalf("expected an error for savepoint name %q", name)
+		}
+	}
+}
+
 func TestSession_PrepAndExe(t *testing.T) {
 	rowsAffected, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number)", tableName()))
 	testErr(err, t)
@@ -209,3 +252,939 @@ func TestSession_PrepAndQry(t *testing.T) {
 		t.Fatalf("expected(%v), actual(%v)", 9, row[0])
 	}
 }
+
+func TestSession_UniqueConstraintColumns(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	_, err = testSes.PrepAndExe(fmt.Sprintf("alter table %v add constraint %v_uk unique (c1)", tableName, tableName))
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (9)", tableName))
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (9)", tableName))
+	if err == nil {
+		t.Fatal("expected ORA-00001 error")
+	}
+	uniqueErr := err
+	columns, err := testSes.UniqueConstraintColumns(uniqueErr)
+	testErr(err, t)
+	if len(columns) != 1 || columns[0] != "C1" {
+		t.Fatalf("constraint columns: expected(%v), actual(%v)", []string{"C1"}, columns)
+	}
+
+	// a session mapping VARCHAR2 to ora.String reads the columns as well
+	sesCfg := *testSesCfg
+	stmtCfg := ora.NewStmtCfg()
+	if sesCfg.StmtCfg != nil {
+		*stmtCfg = *sesCfg.StmtCfg
+	}
+	testErr(stmtCfg.Rset.SetVarchar(ora.OraS), t)
+	sesCfg.StmtCfg = stmtCfg
+	ses, err := testSrv.OpenSes(&sesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	columns, err = ses.UniqueConstraintColumns(uniqueErr)
+	testErr(err, t)
+	if len(columns) != 1 || columns[0] != "C1" {
+		t.Fatalf("constraint columns: expected(%v), actual(%v)", []string{"C1"}, columns)
+	}
+}
+
+func TestSession_Rewrite(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	requestID := 1
+	sesCfg := *testSesCfg
+	sesCfg.PrepCacheSize = 2
+	sesCfg.Rewrite = func(sql string) string {
+		return fmt.Sprintf("/*req:%v*/ ", requestID) + strings.Replace(sql, "{greeting}", "'go'", -1)
+	}
+	ses, err := srv.OpenSes(&sesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	// the sql text is rewritten before it's prepared
+	rset, err := ses.PrepAndQry("select {greeting} from dual")
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0] != "go" {
+		t.Fatalf("expected(go), actual(%v)", rset.Row)
+	}
+
+	// cached statements are matched by the rewritten sql text
+	sql := "select {greeting} from dual"
+	stmt, err := ses.PrepCached(sql)
+	testErr(err, t)
+	testErr(stmt.Close(), t)
+	reused, err := ses.PrepCached(sql)
+	testErr(err, t)
+	testErr(reused.Close(), t)
+	if reused != stmt {
+		t.Fatal("expected the cached Stmt to be reused for identical rewritten sql")
+	}
+	requestID = 2
+	other, err := ses.PrepCached(sql)
+	testErr(err, t)
+	testErr(other.Close(), t)
+	if other == stmt {
+		t.Fatal("expected a new Stmt for different rewritten sql")
+	}
+}
+
+func TestSession_LanguageTerritory(t *testing.T) {
+	sesCfg := *testSesCfg
+	sesCfg.Language = "GERMAN"
+	sesCfg.Territory = "AUSTRIA"
+	ses, err := testSrv.OpenSes(&sesCfg)
+	testErr(err, t)
+	defer ses.Close()
+
+	rset, err := ses.PrepAndQry("SELECT parameter, value FROM nls_session_parameters WHERE parameter IN ('NLS_LANGUAGE', 'NLS_TERRITORY')")
+	testErr(err, t)
+	actual := make(map[string]string)
+	for rset.Next() {
+		actual[rset.Row[0].(string)] = rset.Row[1].(string)
+	}
+	testErr(rset.Err, t)
+	if actual["NLS_LANGUAGE"] != "GERMAN" || actual["NLS_TERRITORY"] != "AUSTRIA" {
+		t.Fatalf("expected(GERMAN, AUSTRIA), actual(%v, %v)", actual["NLS_LANGUAGE"], actual["NLS_TERRITORY"])
+	}
+
+	// the error text is localized when the server has German messages
+	rset, err = ses.PrepAndQry("SELECT 1/0 FROM DUAL")
+	if err == nil {
+		rset.Next()
+		err = rset.Err
+	}
+	oe, ok := err.(*ora.Error)
+	if !ok || oe.Code != 1476 {
+		t.Fatalf("expected ORA-01476, actual(%v)", err)
+	}
+	t.Logf("localized error: %v", oe)
+}
+
+func TestSession_PrepCached(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	sesCfg := *testSesCfg
+	sesCfg.PrepCacheSize = 1
+	ses, err := srv.OpenSes(&sesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	const sql = "select 'go' from dual"
+	stmt, err := ses.PrepCached(sql)
+	testErr(err, t)
+	// the cached statement is in use, so a new one is prepared
+	other, err := ses.PrepCached(sql)
+	testErr(err, t)
+	if other == stmt {
+		t.Fatal("expected a new Stmt while the cached Stmt is in use")
+	}
+	testErr(other.Close(), t)
+	stmt.Cfg().IsScrollable = true
+	testErr(stmt.Close(), t)
+	if !stmt.IsOpen() {
+		t.Fatal("expected the cached Stmt to remain open after Close")
+	}
+	reused, err := ses.PrepCached(sql)
+	testErr(err, t)
+	if reused != stmt {
+		t.Fatal("expected the cached Stmt to be reused")
+	}
+	// the previous caller's cfg changes don't carry over
+	if reused.Cfg().IsScrollable {
+		t.Fatal("expected the reused Stmt to have the session's StmtCfg")
+	}
+	rset, err := reused.Qry()
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0] != "go" {
+		t.Fatalf("expected(go), actual(%v)", rset.Row)
+	}
+	testErr(reused.Close(), t)
+
+	testErr(ses.ClearPrepCache(), t)
+	if stmt.IsOpen() {
+		t.Fatal("expected ClearPrepCache to close the idle cached Stmt")
+	}
+}
+
+func TestSession_Exists(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName), int64(7))
+	testErr(err, t)
+
+	exists, err := testSes.Exists(fmt.Sprintf("select c1 from %v where c1 = :c1", tableName), int64(7))
+	testErr(err, t)
+	if !exists {
+		t.Fatal("expected a matching row to exist")
+	}
+	exists, err = testSes.Exists(fmt.Sprintf("select c1 from %v where c1 = :c1", tableName), int64(8))
+	testErr(err, t)
+	if exists {
+		t.Fatal("expected no matching row to exist")
+	}
+	exists, err = testSes.Exists(fmt.Sprintf("select c1 from %v where c1 >= :c1 order by c1", tableName), int64(7))
+	testErr(err, t)
+	if !exists {
+		t.Fatal("expected a matching row to exist with an order by")
+	}
+	exists, err = testSes.Exists(fmt.Sprintf("select c1 from %v where c1 = :c1 -- trailing comment", tableName), int64(7))
+	testErr(err, t)
+	if !exists {
+		t.Fatal("expected a matching row to exist with a trailing comment")
+	}
+	exists, err = testSes.Exists(fmt.Sprintf("select c1 from %v where c1 = :c1;\n", tableName), int64(8))
+	testErr(err, t)
+	if exists {
+		t.Fatal("expected no matching row to exist with a trailing semicolon")
+	}
+	_, err = testSes.Exists(fmt.Sprintf("delete from %v", tableName))
+	if err == nil {
+		t.Fatal("expected an error for a non-SELECT statement")
+	}
+}
+
+func TestSession_EnableParallelDML(t *testing.T) {
+	hinted := ora.ParallelHint("  insert into t (c1) select c1 from s", 4)
+	if expected := "  insert /*+ PARALLEL(4) */ into t (c1) select c1 from s"; hinted != expected {
+		t.Fatalf("expected(%v), actual(%v)", expected, hinted)
+	}
+
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	testErr(testSes.EnableParallelDML(2), t)
+	defer testSes.DisableParallelDML()
+	_, err = testSes.PrepAndExe(ora.ParallelHint(fmt.Sprintf("insert into %v (c1) select level from dual connect by level <= 10", tableName), 2))
+	testErr(err, t)
+}
+
+func TestSession_StmtCacheSize(t *testing.T) {
+	sesCfg := *testSesCfg
+	sesCfg.StmtCacheSize = 10
+	ses, err := testSrv.OpenSes(&sesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	parseCount := func() int64 {
+		stmt, err := ses.Prep("select m.value from v$mystat m join v$statname n on n.statistic# = m.statistic# where n.name = 'parse count (total)'", ora.I64)
+		testErr(err, t)
+		defer stmt.Close()
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatal("expected a parse count row")
+		}
+		return rset.Row[0].(int64)
+	}
+	counts := make([]int64, 3)
+	for n := range counts {
+		stmt, err := ses.Prep("select 'go' from dual")
+		testErr(err, t)
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		for rset.Next() {
+		}
+		testErr(stmt.Close(), t)
+		counts[n] = parseCount()
+	}
+	// once both statements are cached, no further parse calls occur
+	if counts[2] != counts[1] {
+		t.Fatalf("expected cached statements to be reused without parsing: parse counts %v", counts)
+	}
+}
+
+func TestSession_SetIsolation(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (1)", tableName))
+	testErr(err, t)
+
+	ses, err := testSrv.OpenSes(testSesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	// a row committed by another session after a serializable transaction
+	// began can't be updated
+	testErr(ses.SetIsolation(ora.IsolationSerializable), t)
+	tx, err := ses.StartTx()
+	testErr(err, t)
+	rset, err := ses.PrepAndQry(fmt.Sprintf("select c1 from %v", tableName))
+	testErr(err, t)
+	for rset.Next() {
+	}
+	testErr(rset.Err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("update %v set c1 = 2", tableName))
+	testErr(err, t)
+	_, err = ses.PrepAndExe(fmt.Sprintf("update %v set c1 = 3", tableName))
+	if oraErr, ok := err.(*ora.Error); !ok || oraErr.Code != 8177 {
+		t.Fatalf("expected ORA-08177, actual(%v)", err)
+	}
+	testErr(tx.Rollback(), t)
+
+	// a read-only transaction permits queries only
+	testErr(ses.SetIsolation(ora.IsolationReadOnly), t)
+	tx, err = ses.StartTx()
+	testErr(err, t)
+	_, err = ses.PrepAndExe(fmt.Sprintf("update %v set c1 = 4", tableName))
+	if oraErr, ok := err.(*ora.Error); !ok || oraErr.Code != 1456 {
+		t.Fatalf("expected ORA-01456, actual(%v)", err)
+	}
+	testErr(tx.Rollback(), t)
+
+	testErr(ses.SetIsolation(ora.IsolationReadCommitted), t)
+	_, err = ses.PrepAndExe(fmt.Sprintf("update %v set c1 = 5", tableName))
+	testErr(err, t)
+}
+
+func TestSession_StartGlobalTx(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	// two sessions simulate the branches of one distributed transaction
+	globalID := []byte(tableName)
+	txs := make([]*ora.Tx, 2)
+	for n := range txs {
+		ses, err := testSrv.OpenSes(testSesCfg)
+		testErr(err, t)
+		defer ses.Close()
+		txs[n], err = ses.StartGlobalTx(ora.Xid{FormatID: 0x4f52, GlobalID: globalID, BranchID: []byte{byte(n + 1)}})
+		testErr(err, t)
+		_, err = ses.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (:1)", tableName), int64(n))
+		testErr(err, t)
+	}
+	// nothing is visible before the second phase
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select count(*) from %v", tableName))
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0].(int64) != 0 {
+		t.Fatalf("expected no committed rows, actual(%v)", rset.Row)
+	}
+	for _, tx := range txs {
+		isReadOnly, err := tx.Prepare()
+		testErr(err, t)
+		if isReadOnly {
+			t.Fatal("expected a branch with changes")
+		}
+	}
+	for _, tx := range txs {
+		testErr(tx.Commit(), t)
+	}
+	rset, err = testSes.PrepAndQry(fmt.Sprintf("select count(*) from %v", tableName))
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0].(int64) != 2 {
+		t.Fatalf("expected 2 committed rows, actual(%v)", rset.Row)
+	}
+
+	if _, err = testSes.StartGlobalTx(ora.Xid{}); err == nil {
+		t.Fatal("expected an error for an empty GlobalID")
+	}
+}
+
+func TestSession_Edition(t *testing.T) {
+	edition, err := testSes.Edition()
+	testErr(err, t)
+	if edition == "" {
+		t.Fatal("expected the default edition")
+	}
+
+	// the default edition may be named explicitly
+	sesCfg := *testSesCfg
+	sesCfg.Edition = edition
+	ses, err := testSrv.OpenSes(&sesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	actual, err := ses.Edition()
+	testErr(err, t)
+	if actual != edition {
+		t.Fatalf("expected(%v), actual(%v)", edition, actual)
+	}
+
+	sesCfg.Edition = "GO_ORA_NO_SUCH_EDITION"
+	if ses, err = testSrv.OpenSes(&sesCfg); err == nil {
+		ses.Close()
+		t.Fatal("expected an error for an unknown edition")
+	}
+}
+
+func TestSession_Ping(t *testing.T) {
+	env, err := ora.OpenEnv(nil)
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+
+	testErr(ses.Ping(), t)
+	testErr(srv.Close(), t)
+	if err = ses.Ping(); err == nil {
+		t.Fatal("expected Ping to fail once the server is closed")
+	}
+}
+
+func TestSession_AutoCommit(t *testing.T) {
+	ses, err := testSrv.OpenSes(testSesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	if !ses.AutoCommit() {
+		t.Fatal("expected auto commit by default")
+	}
+	ses.SetAutoCommit(false)
+	if ses.AutoCommit() {
+		t.Fatal("expected auto commit to be disabled")
+	}
+	if !testSesCfg.StmtCfg.IsAutoCommitting {
+		t.Fatal("expected the shared StmtCfg to be unchanged")
+	}
+	ses.SetAutoCommit(true)
+	tx, err := ses.StartTx()
+	testErr(err, t)
+	if ses.AutoCommit() {
+		t.Fatal("expected no auto commit during a transaction")
+	}
+	testErr(tx.Rollback(), t)
+	if !ses.AutoCommit() {
+		t.Fatal("expected auto commit after the transaction")
+	}
+}
+
+func TestSession_AnyData(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 sys.anydata)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	date := time.Date(2015, 6, 7, 8, 9, 10, 0, time.Local)
+	expected := []ora.AnyData{
+		{Value: int64(42)},
+		{Value: 3.25},
+		{Value: "forty-two"},
+		{Value: date},
+		{IsNull: true},
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (:c1, :c2)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	for n, value := range expected {
+		_, err = stmt.Exe(int64(n), value)
+		testErr(err, t)
+	}
+	// values wrapped on the server are read the same way
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (5, sys.anydata.ConvertVarchar2('server'))", tableName))
+	testErr(err, t)
+	expected = append(expected, ora.AnyData{Value: "server"})
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c2 from %v order by c1", tableName))
+	testErr(err, t)
+	n := 0
+	for rset.Next() {
+		actual, ok := rset.Row[0].(ora.AnyData)
+		if !ok {
+			t.Fatalf("row %v: expected ora.AnyData, actual %T", n, rset.Row[0])
+		}
+		if !actual.Equals(expected[n]) {
+			t.Fatalf("row %v: expected(%v), actual(%v)", n, expected[n], actual)
+		}
+		n++
+	}
+	testErr(rset.Err, t)
+	if n != len(expected) {
+		t.Fatalf("expected %v rows, actual %v", len(expected), n)
+	}
+}
+
+func TestAnyData_Equals(t *testing.T) {
+	for n, c := range []struct {
+		a, b     ora.AnyData
+		expected bool
+	}{
+		{ora.AnyData{Value: int64(1)}, ora.AnyData{Value: int64(1)}, true},
+		{ora.AnyData{Value: int64(1)}, ora.AnyData{Value: 1.0}, false},
+		{ora.AnyData{Value: []byte{1, 2}}, ora.AnyData{Value: []byte{1, 2}}, true},
+		{ora.AnyData{Value: []byte{1, 2}}, ora.AnyData{Value: []byte{1}}, false},
+		{ora.AnyData{Value: []int64{1, 2}}, ora.AnyData{Value: []int64{1, 2}}, true},
+		{ora.AnyData{Value: []byte{1}}, ora.AnyData{IsNull: true}, false},
+		{ora.AnyData{IsNull: true}, ora.AnyData{IsNull: true}, true},
+	} {
+		// uncomparable values don't panic
+		if actual := c.a.Equals(c.b); actual != c.expected {
+			t.Errorf("%d. expected(%v), actual(%v)", n, c.expected, actual)
+		}
+	}
+}
+
+func TestSession_PrepKey(t *testing.T) {
+	sesCfg := *testSesCfg
+	sesCfg.StmtCacheSize = 10
+	ses, err := testSrv.OpenSes(&sesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	parseCount := func() int64 {
+		stmt, err := ses.Prep("select m.value from v$mystat m join v$statname n on n.statistic# = m.statistic# where n.name = 'parse count (total)'", ora.I64)
+		testErr(err, t)
+		defer stmt.Close()
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatal("expected a parse count row")
+		}
+		return rset.Row[0].(int64)
+	}
+	// differently formatted sql sharing a key resolves to one cached statement
+	sqls := []string{"select 'go' from dual", "select  'go'\n  from dual", "SELECT 'go' FROM dual"}
+	counts := make([]int64, len(sqls))
+	for n, sql := range sqls {
+		stmt, err := ses.PrepKey("go-from-dual", sql)
+		testErr(err, t)
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		if !rset.Next() || rset.Row[0] != "go" {
+			t.Fatalf("expected a 'go' row, actual(%v)", rset.Row)
+		}
+		for rset.Next() {
+		}
+		testErr(stmt.Close(), t)
+		counts[n] = parseCount()
+	}
+	if counts[2] != counts[1] {
+		t.Fatalf("expected the keyed statement to be reused without parsing: parse counts %v", counts)
+	}
+}
+
+func TestSession_ExecDDL(t *testing.T) {
+	ses, err := testSrv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	tableName := tableName()
+	testErr(ses.ExecDDL(fmt.Sprintf("create table %v (c1 number)", tableName)), t)
+	defer ses.ExecDDL(fmt.Sprintf("drop table %v", tableName))
+
+	if err = ses.ExecDDL("select 1 from dual"); err == nil {
+		t.Fatal("expected an error executing a query with ExecDDL")
+	}
+
+	tx, err := ses.StartTx()
+	testErr(err, t)
+	_, err = ses.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (1)", tableName))
+	testErr(err, t)
+	if err = ses.ExecDDL(fmt.Sprintf("create index %v_i on %v (c1)", tableName, tableName)); err == nil {
+		t.Fatal("expected an error executing DDL inside a Tx")
+	}
+	testErr(tx.Rollback(), t)
+
+	// the insert wasn't committed by the rejected DDL
+	exists, err := ses.Exists(fmt.Sprintf("select c1 from %v", tableName))
+	testErr(err, t)
+	if exists {
+		t.Fatal("expected the rolled back insert to be absent")
+	}
+	testErr(ses.ExecDDL(fmt.Sprintf("create index %v_i on %v (c1)", tableName, tableName)), t)
+}
+
+func TestSession_ProxyUsername(t *testing.T) {
+	proxyUsername := os.Getenv("GO_ORA_DRV_TEST_PROXY_USERNAME")
+	if proxyUsername == "" {
+		t.Skip("SKIP set GO_ORA_DRV_TEST_PROXY_USERNAME to a user which grants CONNECT THROUGH the test user")
+	}
+	user := func(ses *ora.Ses) string {
+		rset, err := ses.PrepAndQry("SELECT USER FROM dual")
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatal("expected a row")
+		}
+		return rset.Row[0].(string)
+	}
+
+	sesCfg := *testSesCfg
+	sesCfg.ProxyUsername = proxyUsername
+	ses, err := testSrv.OpenSes(&sesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	if actual := user(ses); actual != strings.ToUpper(proxyUsername) {
+		t.Fatalf("user: expected(%v), actual(%v)", strings.ToUpper(proxyUsername), actual)
+	}
+
+	db, err := sql.Open(ora.Name, fmt.Sprintf("%v[%v]/%v@%v", testSesCfg.Username, proxyUsername, testSesCfg.Password, testSrvCfg.Dblink))
+	testErr(err, t)
+	defer db.Close()
+	var actual string
+	testErr(db.QueryRow("SELECT USER FROM dual").Scan(&actual), t)
+	if actual != strings.ToUpper(proxyUsername) {
+		t.Fatalf("user with proxy[client] connection string: expected(%v), actual(%v)", strings.ToUpper(proxyUsername), actual)
+	}
+}
+
+func TestSession_PrivilegeSysDBA(t *testing.T) {
+	username := os.Getenv("GO_ORA_DRV_TEST_SYSDBA_USERNAME")
+	password := os.Getenv("GO_ORA_DRV_TEST_SYSDBA_PASSWORD")
+	if username == "" {
+		t.Skip("SKIP set GO_ORA_DRV_TEST_SYSDBA_USERNAME and GO_ORA_DRV_TEST_SYSDBA_PASSWORD to a user granted SYSDBA")
+	}
+	sesCfg := ora.NewSesCfg()
+	sesCfg.Username = username
+	sesCfg.Password = password
+	sesCfg.Privilege = ora.PrivilegeSysDBA
+	ses, err := testSrv.OpenSes(sesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	rset, err := ses.PrepAndQry("SELECT sys_context('USERENV', 'ISDBA'), instance_name FROM v$instance")
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("expected a row: %v", rset.Err)
+	}
+	if rset.Row[0] != "TRUE" {
+		t.Fatalf("ISDBA: expected(TRUE), actual(%v)", rset.Row[0])
+	}
+
+	db, err := sql.Open(ora.Name, fmt.Sprintf("%v/%v@%v as sysdba", username, password, testSrvCfg.Dblink))
+	testErr(err, t)
+	defer db.Close()
+	var isDBA, instanceName string
+	testErr(db.QueryRow("SELECT sys_context('USERENV', 'ISDBA'), instance_name FROM v$instance").Scan(&isDBA, &instanceName), t)
+	if isDBA != "TRUE" {
+		t.Fatalf("ISDBA with AS SYSDBA connection string: expected(TRUE), actual(%v)", isDBA)
+	}
+}
+
+func TestSession_ChangePassword(t *testing.T) {
+	username := "GO_ORA_" + strings.ToUpper(tableName())
+	if _, err := testSes.PrepAndExe(fmt.Sprintf(`CREATE USER %v IDENTIFIED BY "Expired_1"`, username)); err != nil {
+		t.Skipf("SKIP create user: %v", err)
+	}
+	defer testSes.PrepAndExe(fmt.Sprintf("DROP USER %v CASCADE", username))
+	_, err := testSes.PrepAndExe(fmt.Sprintf("GRANT CREATE SESSION TO %v", username))
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("ALTER USER %v PASSWORD EXPIRE", username))
+	testErr(err, t)
+
+	sesCfg := ora.NewSesCfg()
+	sesCfg.Username = username
+	sesCfg.Password = "Expired_1"
+	if _, err = testSrv.OpenSes(sesCfg); !ora.IsPasswordExpired(err) {
+		t.Fatalf("expected an ORA-28001 error, actual %v", err)
+	}
+
+	// the expired password is changed when the session is opened
+	sesCfg.NewPassword = "Changed_2"
+	ses, err := testSrv.OpenSes(sesCfg)
+	testErr(err, t)
+	if ses.Cfg().Password != "Changed_2" || sesCfg.Password != "Expired_1" {
+		t.Fatalf("passwords: expected(Changed_2, Expired_1), actual(%v, %v)", ses.Cfg().Password, sesCfg.Password)
+	}
+	testErr(ses.ChangePassword("Changed_2", "Changed_3"), t)
+	testErr(ses.Close(), t)
+
+	sesCfg.Password = "Changed_3"
+	sesCfg.NewPassword = ""
+	ses, err = testSrv.OpenSes(sesCfg)
+	testErr(err, t)
+	testErr(ses.Close(), t)
+}
+
+func TestSession_Error_code(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number primary key)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (1)", tableName))
+	testErr(err, t)
+
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (1)", tableName))
+	oe, ok := err.(*ora.Error)
+	if !ok {
+		t.Fatalf("expected an *ora.Error, actual %T %v", err, err)
+	}
+	if oe.Code != 1 {
+		t.Fatalf("code: expected(1), actual(%v)", oe.Code)
+	}
+	if !strings.HasPrefix(oe.Text, "ORA-00001:") || !strings.Contains(oe.Error(), oe.Text) {
+		t.Fatalf("unexpected text %q of error %q", oe.Text, oe.Error())
+	}
+}
+
+func TestSes_Queue(t *testing.T) {
+	// This needs "GRANT EXECUTE ON DBMS_AQADM TO test" and
+	// "GRANT EXECUTE ON DBMS_AQ TO test".
+	queueTable := tableName() + "_qt"
+	queueName := tableName() + "_q"
+	_, err := testSes.PrepAndExe(fmt.Sprintf(`begin
+		dbms_aqadm.create_queue_table(queue_table => '%v', queue_payload_type => 'RAW');
+		dbms_aqadm.create_queue(queue_name => '%v', queue_table => '%v');
+		dbms_aqadm.start_queue(queue_name => '%v');
+	end;`, queueTable, queueName, queueTable, queueName))
+	if err != nil {
+		t.Skipf("SKIP create queue: %v", err)
+	}
+	defer func() {
+		_, err := testSes.PrepAndExe(fmt.Sprintf(`begin
+			dbms_aqadm.stop_queue(queue_name => '%v');
+			dbms_aqadm.drop_queue(queue_name => '%v');
+			dbms_aqadm.drop_queue_table(queue_table => '%v');
+		end;`, queueName, queueName, queueTable))
+		testErr(err, t)
+	}()
+
+	queue, err := testSes.OpenQueue(queueName)
+	testErr(err, t)
+	// nothing is queued
+	_, ok, err := queue.Dequeue(ora.DeqOptions{})
+	testErr(err, t)
+	if ok {
+		t.Fatalf("expected no message from an empty queue")
+	}
+
+	expected := []byte("hello, queue")
+	testErr(queue.Enqueue(expected, ora.EnqOptions{Visibility: ora.VisibilityImmediate}), t)
+	payload, ok, err := queue.Dequeue(ora.DeqOptions{
+		Visibility: ora.VisibilityImmediate,
+		Navigation: ora.NavigationFirstMsg,
+		Wait:       5 * time.Second,
+	})
+	testErr(err, t)
+	if !ok {
+		t.Fatalf("expected a message")
+	}
+	if string(payload) != string(expected) {
+		t.Fatalf("expected(%q), actual(%q)", expected, payload)
+	}
+}
+
+func TestResultCacheHint(t *testing.T) {
+	for _, c := range []struct{ sql, expected string }{
+		{"select c1 from t", "select /*+ result_cache */ c1 from t"},
+		{" -- lookup\n SELECT/*+ index(t) */ c1 from t", " -- lookup\n SELECT/*+ result_cache index(t) */ c1 from t"},
+		{"select /*+ RESULT_CACHE */ c1 from t", "select /*+ RESULT_CACHE */ c1 from t"},
+		{"/* lookup */select\nc1 from t", "/* lookup */select /*+ result_cache */\nc1 from t"},
+		{"insert into t select c1 from u", "insert into t select c1 from u"},
+		{"with x as (select 1 c1 from dual) select c1 from x", "with x as (select 1 c1 from dual) select c1 from x"},
+		{"selected", "selected"},
+	} {
+		if actual := ora.ResultCacheHint(c.sql); actual != c.expected {
+			t.Errorf("%q: expected(%q), actual(%q)", c.sql, c.expected, actual)
+		}
+	}
+}
+
+func TestSession_IsResultCached(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (:1)", tableName), []int64{1, 2, 3})
+	testErr(err, t)
+
+	sesCfg := *testSesCfg
+	sesCfg.IsResultCached = true
+	ses, err := testSrv.OpenSes(&sesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	qry := fmt.Sprintf("select c1 from %v order by c1", tableName)
+	var sqlID string
+	for n := 0; n < 2; n++ { // the second query may be answered from the result cache
+		stmt, err := ses.Prep(qry, ora.I64)
+		testErr(err, t)
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		var actual []int64
+		for rset.Next() {
+			actual = append(actual, rset.Row[0].(int64))
+		}
+		testErr(rset.Err, t)
+		if len(actual) != 3 || actual[0] != 1 || actual[1] != 2 || actual[2] != 3 {
+			t.Fatalf("expected([1 2 3]), actual(%v)", actual)
+		}
+		sqlID, err = stmt.SQLID()
+		testErr(err, t)
+		testErr(stmt.Close(), t)
+	}
+
+	// This needs "GRANT SELECT ON v_$sql TO test".
+	rset, err := testSes.PrepAndQry("select sql_text from v$sql where sql_id = :1", sqlID)
+	if err != nil {
+		t.Skipf("SKIP query v$sql: %v", err)
+	}
+	row := rset.NextRow()
+	testErr(rset.Err, t)
+	if row == nil || !strings.Contains(row[0].(string), "/*+ result_cache */") {
+		t.Fatalf("expected a result_cache hint, actual(%v)", row)
+	}
+}
+
+type testObjPoint struct {
+	X     int64
+	Label *string `db:"NAME"`
+	Seen  time.Time
+	Note  string `db:"-"`
+}
+
+func TestSession_Object(t *testing.T) {
+	tableName := tableName()
+	typeName := tableName + "_point"
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create type %v as object (x number(10), name varchar2(40), seen date)", typeName))
+	testErr(err, t)
+	defer func() {
+		_, err := testSes.PrepAndExe("drop type " + typeName)
+		testErr(err, t)
+	}()
+	_, err = testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 %v)", tableName, typeName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	testErr(ora.AddObj(testObjPoint{}, typeName), t)
+
+	label := "origin"
+	seen := time.Date(2015, 6, 7, 8, 9, 10, 0, time.Local)
+	expected := []*testObjPoint{
+		{X: 42, Label: &label, Seen: seen},
+		{X: -7, Seen: seen},
+		nil,
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (:c1, :c2)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	for n, value := range expected {
+		_, err = stmt.Exe(int64(n), value)
+		testErr(err, t)
+	}
+	// objects constructed on the server are read the same way
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (3, %v(3, 'server', null))", tableName, typeName))
+	testErr(err, t)
+	server := "server"
+	expected = append(expected, &testObjPoint{X: 3, Label: &server})
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c2 from %v order by c1", tableName))
+	testErr(err, t)
+	n := 0
+	for rset.Next() {
+		actual, ok := rset.Row[0].(*testObjPoint)
+		if !ok {
+			t.Fatalf("row %v: expected *testObjPoint, actual %T", n, rset.Row[0])
+		}
+		if (actual == nil) != (expected[n] == nil) {
+			t.Fatalf("row %v: expected(%v), actual(%v)", n, expected[n], actual)
+		}
+		if actual != nil {
+			e := expected[n]
+			if actual.X != e.X || !actual.Seen.Equal(e.Seen) ||
+				(actual.Label == nil) != (e.Label == nil) ||
+				(actual.Label != nil && *actual.Label != *e.Label) {
+				t.Fatalf("row %v: expected(%+v), actual(%+v)", n, *e, *actual)
+			}
+		}
+		n++
+	}
+	testErr(rset.Err, t)
+	if n != len(expected) {
+		t.Fatalf("expected %v rows, actual %v", len(expected), n)
+	}
+}
+
+func TestSession_Coll_procedure(t *testing.T) {
+	typeName := tableName() + "_nums"
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create type %v as table of number", typeName))
+	testErr(err, t)
+	defer func() {
+		_, err := testSes.PrepAndExe("drop type " + typeName)
+		testErr(err, t)
+	}()
+	procName := tableName()
+	_, err = testSes.PrepAndExe(fmt.Sprintf(`create or replace procedure %v(p1 in %v, p2 out number) as
+begin
+	if p1 is null then
+		p2 := -1;
+	else
+		p2 := 0;
+		for i in 1 .. p1.count loop
+			p2 := p2 + p1(i);
+		end loop;
+	end if;
+end;`, procName, typeName))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop procedure %v", procName))
+
+	stmt, err := testSes.Prep(fmt.Sprintf("begin %v(:1, :2); end;", procName))
+	testErr(err, t)
+	defer stmt.Close()
+	for _, c := range []struct {
+		coll     ora.Coll
+		expected int64
+	}{
+		{ora.Coll{TypeName: typeName, Value: []int64{1, 2, 3, -4}}, 2},
+		{ora.Coll{TypeName: typeName, Value: []int64{}}, 0},
+		{ora.Coll{TypeName: typeName, IsNull: true}, -1},
+	} {
+		var sum int64
+		_, err = stmt.Exe(c.coll, ora.Out{Value: &sum})
+		testErr(err, t)
+		if sum != c.expected {
+			t.Fatalf("%v: expected(%v), actual(%v)", c.coll, c.expected, sum)
+		}
+	}
+}
+
+func TestSession_Coll_table(t *testing.T) {
+	tableName := tableName()
+	numsName := tableName + "_nums"
+	namesName := tableName + "_names"
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create type %v as varray(10) of number(10)", numsName))
+	testErr(err, t)
+	defer testSes.PrepAndExe("drop type " + numsName)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("create type %v as table of varchar2(40)", namesName))
+	testErr(err, t)
+	defer testSes.PrepAndExe("drop type " + namesName)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 %v, c3 %v) nested table c3 store as %v_c3", tableName, numsName, namesName, tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	expected := [][2]ora.Coll{
+		{{Value: []int64{7, -8, 9}}, {Value: []string{"a", "bc"}}},
+		{{Value: []int64{}}, {Value: []string{}}},
+		{{IsNull: true}, {IsNull: true}},
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2, c3) values (:c1, :c2, :c3)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	for n, value := range expected {
+		nums, names := value[0], value[1]
+		nums.TypeName, names.TypeName = numsName, namesName
+		_, err = stmt.Exe(int64(n), nums, names)
+		testErr(err, t)
+	}
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c2, c3 from %v order by c1", tableName))
+	testErr(err, t)
+	n := 0
+	for rset.Next() {
+		for m := range expected[n] {
+			actual, ok := rset.Row[m].(ora.Coll)
+			if !ok {
+				t.Fatalf("row %v column %v: expected ora.Coll, actual %T", n, m, rset.Row[m])
+			}
+			if actual.IsNull != expected[n][m].IsNull ||
+				(!actual.IsNull && !reflect.DeepEqual(actual.Value, expected[n][m].Value)) {
+				t.Fatalf("row %v column %v: expected(%v), actual(%v)", n, m, expected[n][m], actual)
+			}
+		}
+		n++
+	}
+	testErr(rset.Err, t)
+	if n != len(expected) {
+		t.Fatalf("expected %v rows, actual %v", len(expected), n)
+	}
+}
@@ -5,8 +5,14 @@
 package ora_test
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/rana/ora.v2"
 )
 
 func TestStmt_Exe_table_create_alter_drop(t *testing.T) {
@@ -142,3 +148,960 @@ func TestStmt_Exe_select(t *testing.T) {
 		t.Fatalf("rows affected: expected(%v), actual(%v)", 2, rset.Len())
 	}
 }
+
+func TestStmt_SQLID(t *testing.T) {
+	stmt, err := testSes.Prep("select 1 from dual")
+	defer stmt.Close()
+	testErr(err, t)
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	for rset.Next() {
+	}
+	sqlID, err := stmt.SQLID()
+	testErr(err, t)
+	if 13 != len(sqlID) {
+		t.Fatalf("sql id length: expected(%v), actual(%v) %q", 13, len(sqlID), sqlID)
+	}
+	cached, err := stmt.SQLID()
+	testErr(err, t)
+	if sqlID != cached {
+		t.Fatalf("cached sql id: expected(%v), actual(%v)", sqlID, cached)
+	}
+}
+
+func TestStmt_Exe_insert_commitRowCount(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number(10) check (c1 < 9))", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	// count the committed rows from another session
+	ses, err := testSrv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	count := func() int64 {
+		rset, err := ses.PrepAndQry(fmt.Sprintf("select count(*) from %v", tableName))
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatal("expected a row")
+		}
+		return rset.Row[0].(int64)
+	}
+
+	// batches of 0-2, 3-5 and 6-8 are committed; the batch of 9-11 fails
+	values := make([]int64, 12)
+	for n := range values {
+		values[n] = int64(n)
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	err = stmt.Cfg().SetCommitRowCount(3)
+	testErr(err, t)
+	rowsAffected, err := stmt.Exe(values)
+	if oe, ok := err.(*ora.Error); !ok || oe.Code != 2290 { // ORA-02290: check constraint violated
+		t.Fatalf("expected ORA-02290, actual(%v)", err)
+	}
+	if rowsAffected != 9 {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", 9, rowsAffected)
+	}
+	if actual := count(); actual != 9 {
+		t.Fatalf("committed rows: expected(%v), actual(%v)", 9, actual)
+	}
+
+	// without auto-commit, batches aren't committed
+	stmt.Cfg().IsAutoCommitting = false
+	_, err = stmt.Exe(values[:9])
+	testErr(err, t)
+	if actual := count(); actual != 9 {
+		t.Fatalf("committed rows: expected(%v), actual(%v)", 9, actual)
+	}
+	_, err = testSes.PrepAndExe("ROLLBACK")
+	testErr(err, t)
+}
+
+func TestStmt_Exe_insert_batchErrors(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number(10) check (c1 > 0))", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	stmt.Cfg().IsBatchErrors = true
+	rowsAffected, err := stmt.Exe([]int64{1, -2, 3, -4, 5})
+	batchErrs, ok := err.(ora.BatchErrors)
+	if !ok {
+		t.Fatalf("expected ora.BatchErrors, actual(%#v)", err)
+	}
+	if len(batchErrs) != 2 || batchErrs[0].Index != 1 || batchErrs[1].Index != 3 {
+		t.Fatalf("expected rows 1 and 3 to fail, actual(%v)", batchErrs)
+	}
+	for _, batchErr := range batchErrs {
+		if batchErr.Err.Code != 2290 { // ORA-02290: check constraint violated
+			t.Fatalf("expected ORA-02290, actual(%v)", batchErr.Err.Text)
+		}
+	}
+	if rowsAffected != 3 {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", 3, rowsAffected)
+	}
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select count(*) from %v", tableName))
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0].(int64) != 3 {
+		t.Fatalf("expected 3 rows, actual(%v)", rset.Row)
+	}
+}
+
+func TestStmt_ExeRows(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	defer dropTable(tableName, testSes, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	var n int64
+	rowsAffected, err := stmt.ExeRows(4, func() ([]interface{}, error) {
+		if n == 10 {
+			return nil, io.EOF
+		}
+		n++
+		return []interface{}{n}, nil
+	})
+	testErr(err, t)
+	if 10 != rowsAffected {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", 10, rowsAffected)
+	}
+}
+
+func TestStmt_ExeStream(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	defer dropTable(tableName, testSes, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	rows := make(chan []interface{})
+	go func() {
+		defer close(rows)
+		for n := int64(1); n <= 10; n++ {
+			rows <- []interface{}{n}
+		}
+	}()
+	rowsAffected, err := stmt.ExeStream(4, rows)
+	testErr(err, t)
+	if 10 != rowsAffected {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", 10, rowsAffected)
+	}
+
+	// the first error stops draining the channel
+	done := make(chan struct{})
+	rows = make(chan []interface{})
+	go func() {
+		defer close(rows)
+		for _, row := range [][]interface{}{{int64(11)}, {"twelve"}, {int64(13)}} {
+			select {
+			case rows <- row:
+			case <-done:
+				return
+			}
+		}
+	}()
+	_, err = stmt.ExeStream(4, rows)
+	close(done)
+	if err == nil {
+		t.Fatal("expected an error for a mismatched row type")
+	}
+}
+
+func TestStmt_Exe_insert_returning(t *testing.T) {
+	tableName, err := createTable(2, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	seqName := tableName + "_s"
+	_, err = testSes.PrepAndExe(fmt.Sprintf("create sequence %v start with 100", seqName))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop sequence %v", seqName))
+
+	// returning a sequence generated number into an int64 pointer
+	var id int64
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (%v.nextval, 7) returning c1 into :c1", tableName, seqName))
+	defer stmt.Close()
+	testErr(err, t)
+	_, err = stmt.Exe(&id)
+	testErr(err, t)
+	if id != 100 {
+		t.Fatalf("returned id: expected(%v), actual(%v)", 100, id)
+	}
+
+	// returning a rowid into a string pointer
+	var rowid string
+	stmt2, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (%v.nextval, 8) returning rowid into :r", tableName, seqName))
+	defer stmt2.Close()
+	testErr(err, t)
+	_, err = stmt2.Exe(&rowid)
+	testErr(err, t)
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c1 from %v where rowid = :r", tableName), rowid)
+	testErr(err, t)
+	if !rset.Next() {
+		testErr(rset.Err, t)
+		t.Fatalf("expected returned rowid (%v) to locate the inserted row", rowid)
+	}
+	if rset.Row[0] != int64(101) {
+		t.Fatalf("expected(%v), actual(%v)", 101, rset.Row[0])
+	}
+}
+
+func TestStmt_Exe_insert_returning_rowids(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	values := []int64{3, 1, 4, 1, 5}
+	var rowids []ora.Rowid
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1) returning rowid into :r", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	rowsAffected, err := stmt.Exe(values, &rowids)
+	testErr(err, t)
+	if rowsAffected != uint64(len(values)) || len(rowids) != len(values) {
+		t.Fatalf("expected %v rows and rowids, actual(%v, %v)", len(values), rowsAffected, rowids)
+	}
+	// each rowid locates the row inserted from the value at the same index
+	selectStmt, err := testSes.Prep(fmt.Sprintf("select c1 from %v where rowid = :r", tableName))
+	defer selectStmt.Close()
+	testErr(err, t)
+	for n, rowid := range rowids {
+		if _, err = ora.ParseRowid(rowid.Value); err != nil {
+			t.Fatalf("row %v: %v", n, err)
+		}
+		rset, err := selectStmt.Qry(rowid.Value)
+		testErr(err, t)
+		if !rset.Next() || rset.Row[0].(int64) != values[n] {
+			t.Fatalf("row %v: expected(%v), actual(%v)", n, values[n], rset.Row)
+		}
+	}
+}
+
+func TestStmt_Exe_out(t *testing.T) {
+	procName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf(`create or replace procedure %v(p1 in number, p2 out number, p3 in out varchar2, p4 in out number) as
+begin
+	p2 := p1 * 2;
+	p3 := upper(p3) || '!';
+	p4 := nvl(p4, -1) + 1;
+end;`, procName))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop procedure %v", procName))
+
+	stmt, err := testSes.Prep(fmt.Sprintf("begin %v(:1, :2, :3, :4); end;", procName))
+	defer stmt.Close()
+	testErr(err, t)
+	var doubled int64
+	var doubledIsNull bool
+	shout := "go"
+	var counter float64
+	counterIsNull := true
+	_, err = stmt.Exe(int64(21), ora.Out{Value: &doubled, IsNull: &doubledIsNull}, ora.Out{Value: &shout}, ora.Out{Value: &counter, IsNull: &counterIsNull})
+	testErr(err, t)
+	if doubled != 42 || doubledIsNull {
+		t.Fatalf("OUT number: expected(42), actual(%v, isNull %v)", doubled, doubledIsNull)
+	}
+	if shout != "GO!" {
+		t.Fatalf("IN OUT varchar2: expected(%v), actual(%v)", "GO!", shout)
+	}
+	if counter != 0 || counterIsNull {
+		t.Fatalf("IN OUT null number: expected(0), actual(%v, isNull %v)", counter, counterIsNull)
+	}
+
+	// re-execution sends the updated values
+	_, err = stmt.Exe(int64(1), ora.Out{Value: &doubled}, ora.Out{Value: &shout}, ora.Out{Value: &counter, IsNull: &counterIsNull})
+	testErr(err, t)
+	if doubled != 2 || shout != "GO!!" || counter != 1 {
+		t.Fatalf("expected(2, GO!!, 1), actual(%v, %v, %v)", doubled, shout, counter)
+	}
+
+	_, err = stmt.Exe(int64(1), ora.Out{Value: doubled}, ora.Out{Value: &shout}, ora.Out{Value: &counter})
+	if err == nil {
+		t.Fatal("expected an error for a non-pointer Out value")
+	}
+}
+
+func TestStmt_nullable_roundTrip(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c0 number, c1 number(10), c2 varchar2(20), c3 number(10,2), c4 date)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	date := time.Date(2015, 6, 7, 8, 9, 10, 0, time.Local)
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c0, c1, c2, c3, c4) values (:0, :1, :2, :3, :4)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	// zero values are distinct from NULLs
+	_, err = stmt.Exe(int64(0), ora.Int64{Value: 0}, ora.String{Value: "0"}, ora.Float64{Value: 0}, ora.Time{Value: date})
+	testErr(err, t)
+	_, err = stmt.Exe(int64(1), ora.Int64{IsNull: true}, ora.String{IsNull: true}, ora.Float64{IsNull: true}, ora.Time{IsNull: true})
+	testErr(err, t)
+
+	// nullable decoding requested through the statement configuration
+	cfg := ora.NewStmtCfg()
+	testErr(cfg.Rset.SetNumberInt(ora.OraI64), t)
+	testErr(cfg.Rset.SetVarchar(ora.OraS), t)
+	testErr(cfg.Rset.SetNumberFloat(ora.OraF64), t)
+	testErr(cfg.Rset.SetDate(ora.OraT), t)
+	selectStmt, err := testSes.Prep(fmt.Sprintf("select c1, c2, c3, c4 from %v order by c0", tableName))
+	testErr(err, t)
+	defer selectStmt.Close()
+	selectStmt.SetCfg(cfg)
+	rset, err := selectStmt.Qry()
+	testErr(err, t)
+	for isNull := false; rset.Next(); isNull = true {
+		i, s, f, d := rset.Row[0].(ora.Int64), rset.Row[1].(ora.String), rset.Row[2].(ora.Float64), rset.Row[3].(ora.Time)
+		if i.IsNull != isNull || s.IsNull != isNull || f.IsNull != isNull || d.IsNull != isNull {
+			t.Fatalf("expected IsNull %v, actual(%v, %v, %v, %v)", isNull, i, s, f, d)
+		}
+		if !isNull && (i.Value != 0 || s.Value != "0" || f.Value != 0 || !d.Value.Equal(date)) {
+			t.Fatalf("expected(0, 0, 0, %v), actual(%v, %v, %v, %v)", date, i, s, f, d)
+		}
+	}
+	testErr(rset.Err, t)
+	if rset.Len() != 2 {
+		t.Fatalf("expected 2 rows, actual %v", rset.Len())
+	}
+}
+
+func TestStmt_Exe_plsArr(t *testing.T) {
+	pkgName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf(`create or replace package %v as
+	type num_tab is table of number index by binary_integer;
+	type str_tab is table of varchar2(100) index by binary_integer;
+	procedure double(p1 in out num_tab);
+	procedure shout(p1 in str_tab, p2 out str_tab);
+end;`, pkgName))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop package %v", pkgName))
+	_, err = testSes.PrepAndExe(fmt.Sprintf(`create or replace package body %v as
+	procedure double(p1 in out num_tab) as
+	begin
+		for i in 1 .. p1.count loop
+			p1(i) := p1(i) * 2;
+		end loop;
+		p1(p1.count + 1) := -1;
+	end;
+	procedure shout(p1 in str_tab, p2 out str_tab) as
+	begin
+		for i in 1 .. p1.count loop
+			p2(i) := upper(p1(i)) || '!';
+		end loop;
+	end;
+end;`, pkgName))
+	testErr(err, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("begin %v.double(:1); end;", pkgName))
+	testErr(err, t)
+	defer stmt.Close()
+	values := []int64{1, 2, 3, -21}
+	_, err = stmt.Exe(ora.PlsArr{Value: &values, Cap: 10})
+	testErr(err, t)
+	expected := []int64{2, 4, 6, -42, -1}
+	if len(values) != len(expected) {
+		t.Fatalf("expected(%v), actual(%v)", expected, values)
+	}
+	for n := range expected {
+		if values[n] != expected[n] {
+			t.Fatalf("expected(%v), actual(%v)", expected, values)
+		}
+	}
+	// an empty table
+	values = values[:0]
+	_, err = stmt.Exe(ora.PlsArr{Value: &values, Cap: 10})
+	testErr(err, t)
+	if len(values) != 1 || values[0] != -1 {
+		t.Fatalf("expected([-1]), actual(%v)", values)
+	}
+
+	stmt, err = testSes.Prep(fmt.Sprintf("begin %v.shout(:1, :2); end;", pkgName))
+	testErr(err, t)
+	defer stmt.Close()
+	var shouts []string
+	_, err = stmt.Exe(ora.PlsArr{Value: []string{"go", "oracle"}}, ora.PlsArr{Value: &shouts, Cap: 2})
+	testErr(err, t)
+	if len(shouts) != 2 || shouts[0] != "GO!" || shouts[1] != "ORACLE!" {
+		t.Fatalf("expected([GO! ORACLE!]), actual(%v)", shouts)
+	}
+}
+
+func TestStmt_Exe_clobThreshold(t *testing.T) {
+	procName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf(`create or replace procedure %v(p1 in clob, p2 out number, p3 out varchar2) as
+begin
+	p2 := dbms_lob.getlength(p1);
+	p3 := dbms_lob.substr(p1, 10, 1) || dbms_lob.substr(p1, 10, p2 - 9);
+end;`, procName))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop procedure %v", procName))
+
+	// a string longer than ClobThreshold is bound as a temporary CLOB
+	payload := "BEGIN-----" + strings.Repeat("0123456789", 5<<20/10) + "-------END"
+	stmt, err := testSes.Prep(fmt.Sprintf("begin %v(:1, :2, :3); end;", procName))
+	defer stmt.Close()
+	testErr(err, t)
+	var length int64
+	var ends string
+	_, err = stmt.Exe(payload, &length, &ends)
+	testErr(err, t)
+	if length != int64(len(payload)) || ends != "BEGIN-----"+"-------END" {
+		t.Fatalf("expected(%v, %v), actual(%v, %v)", len(payload), "BEGIN------------END", length, ends)
+	}
+
+	// a failed execution frees the temporary CLOB
+	_, err = stmt.Exe(ora.String{Value: payload}, &length, int64(1))
+	if err == nil {
+		t.Fatal("expected an error for a non-pointer OUT parameter")
+	}
+	_, err = stmt.Exe(ora.String{Value: payload}, &length, &ends)
+	testErr(err, t)
+	if length != int64(len(payload)) {
+		t.Fatalf("expected(%v), actual(%v)", len(payload), length)
+	}
+}
+
+func TestStmt_Exe_insert_arrayMixedColumns(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number(19,0), c2 varchar2(48 char), c3 binary_double)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	const rowCount = 10000
+	ids := make([]int64, rowCount)
+	names := make([]string, rowCount)
+	amounts := make([]float64, rowCount)
+	for n := range ids {
+		ids[n] = int64(n)
+		names[n] = fmt.Sprintf("name %v", n)
+		amounts[n] = float64(n) / 4
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2, c3) values (:c1, :c2, :c3)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	rowsAffected, err := stmt.Exe(ids, names, amounts)
+	testErr(err, t)
+	if rowCount != rowsAffected {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", rowCount, rowsAffected)
+	}
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c2, c3 from %v where c1 = :c1", tableName), int64(9999))
+	testErr(err, t)
+	if !rset.Next() {
+		testErr(rset.Err, t)
+		t.Fatal("expected the last row to be loaded")
+	}
+	if rset.Row[0] != names[9999] || rset.Row[1] != amounts[9999] {
+		t.Fatalf("expected(%v, %v), actual(%v, %v)", names[9999], amounts[9999], rset.Row[0], rset.Row[1])
+	}
+}
+
+func TestStmt_Exe_rebindTypeChange(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 varchar2(48 char))", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	// the same placeholder receives a different type on each execution
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (:c1, :c2)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	values := []interface{}{int64(7), "eight", nil, ora.Int64{Value: 9}, ora.String{Value: "ten"}, int32(11)}
+	for n, value := range values {
+		_, err = stmt.Exe(int64(n), value)
+		testErr(err, t)
+	}
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c2 from %v order by c1", tableName))
+	testErr(err, t)
+	expected := []interface{}{"7", "eight", "", "9", "ten", "11"}
+	for rset.Next() {
+		if rset.Row[0] != expected[rset.Index] {
+			t.Fatalf("row %v: expected(%v), actual(%v)", rset.Index, expected[rset.Index], rset.Row[0])
+		}
+	}
+	testErr(rset.Err, t)
+	if rset.Len() != len(expected) {
+		t.Fatalf("row count: expected(%v), actual(%v)", len(expected), rset.Len())
+	}
+}
+
+func TestStmt_Exe_insert_arrayLenMismatch(t *testing.T) {
+	tableName, err := createTable(2, numberP38S0, testSes)
+	defer dropTable(tableName, testSes, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (:c1, :c2)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	_, err = stmt.Exe([]int64{1, 2, 3}, []int64{1, 2})
+	if err == nil {
+		t.Fatal("expected array bind length error")
+	}
+	if !strings.Contains(err.Error(), "position 2 has 2 elements") {
+		t.Fatalf("expected error naming position 2, actual(%v)", err)
+	}
+}
+
+type warnLgr struct {
+	ora.EmpLgr
+	warnings []string
+}
+
+func (l *warnLgr) Warnf(format string, v ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(format, v...))
+}
+
+func TestStmt_Qry_slowQueryThreshold(t *testing.T) {
+	lgr := &warnLgr{}
+	cfg := ora.Cfg()
+	logger, threshold := cfg.Log.Logger, cfg.SlowQueryThreshold
+	cfg.Log.Logger, cfg.SlowQueryThreshold = lgr, time.Nanosecond
+	defer func() {
+		cfg.Log.Logger, cfg.SlowQueryThreshold = logger, threshold
+	}()
+
+	stmt, err := testSes.Prep("select 1 from dual")
+	defer stmt.Close()
+	testErr(err, t)
+	_, err = stmt.Qry()
+	testErr(err, t)
+	if len(lgr.warnings) != 1 || !strings.Contains(lgr.warnings[0], "select 1 from dual") {
+		t.Fatalf("slow query warnings: expected one naming the sql, actual(%v)", lgr.warnings)
+	}
+}
+
+// infoLgr is a Logger which doesn't implement ora.WarnLogger.
+type infoLgr struct {
+	infos []string
+}
+
+func (l *infoLgr) Infof(format string, v ...interface{}) {
+	l.infos = append(l.infos, fmt.Sprintf(format, v...))
+}
+func (l *infoLgr) Infoln(v ...interface{})                {}
+func (l *infoLgr) Errorf(format string, v ...interface{}) {}
+func (l *infoLgr) Errorln(v ...interface{})               {}
+
+func TestStmt_Qry_slowQueryThreshold_infoLogger(t *testing.T) {
+	lgr := &infoLgr{}
+	cfg := ora.Cfg()
+	logger, threshold := cfg.Log.Logger, cfg.SlowQueryThreshold
+	cfg.Log.Logger, cfg.SlowQueryThreshold = lgr, time.Nanosecond
+	defer func() {
+		cfg.Log.Logger, cfg.SlowQueryThreshold = logger, threshold
+	}()
+
+	stmt, err := testSes.Prep("select 2 from dual")
+	defer stmt.Close()
+	testErr(err, t)
+	_, err = stmt.Qry()
+	testErr(err, t)
+	// the warning falls back to Infof
+	var slow []string
+	for _, info := range lgr.infos {
+		if strings.Contains(info, "slow query") {
+			slow = append(slow, info)
+		}
+	}
+	if len(slow) != 1 || !strings.Contains(slow[0], "select 2 from dual") {
+		t.Fatalf("slow query infos: expected one naming the sql, actual(%v)", lgr.infos)
+	}
+}
+
+func TestStmt_ServerTime(t *testing.T) {
+	stmt, err := testSes.Prep("select count(*) from all_objects")
+	defer stmt.Close()
+	testErr(err, t)
+	stmt.Cfg().IsTimingServer = true
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	for rset.Next() {
+	}
+	serverTime, err := stmt.ServerTime()
+	testErr(err, t)
+	if serverTime <= 0 {
+		t.Fatalf("server time: expected greater than zero, actual(%v)", serverTime)
+	}
+}
+
+func TestStmt_ExeContext(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	defer dropTable(tableName, testSes, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	rowsAffected, err := stmt.ExeContext(context.Background(), []int64{1, 2, 3})
+	testErr(err, t)
+	if 3 != rowsAffected {
+		t.Fatalf("rows affected: expected(%v), actual(%v)", 3, rowsAffected)
+	}
+
+	// an endless statement is interrupted at the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	loopStmt, err := testSes.Prep("declare n number := 0; begin loop n := n + 1; end loop; end;")
+	defer loopStmt.Close()
+	testErr(err, t)
+	_, err = loopStmt.ExeContext(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected(%v), actual(%v)", context.DeadlineExceeded, err)
+	}
+
+	// the session remains usable
+	rset, err := testSes.PrepAndQry("select 'go' from dual")
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0] != "go" {
+		t.Fatalf("expected(go), actual(%v)", rset.Row)
+	}
+}
+
+func TestStmt_QryContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	stmt, err := testSes.Prep("select count(*) from all_objects a, all_objects b, all_objects c")
+	defer stmt.Close()
+	testErr(err, t)
+	_, err = stmt.QryContext(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected(%v), actual(%v)", context.DeadlineExceeded, err)
+	}
+
+	// the session remains usable
+	rset, err := testSes.PrepAndQryContext(context.Background(), "select 'go' from dual")
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0] != "go" {
+		t.Fatalf("expected(go), actual(%v)", rset.Row)
+	}
+}
+
+func TestStmt_concurrentUse(t *testing.T) {
+	stmt, err := testSes.Prep("begin dbms_lock.sleep(2); end;")
+	defer stmt.Close()
+	testErr(err, t)
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		close(started)
+		_, err := stmt.Exe()
+		done <- err
+	}()
+	<-started
+	// both calls block in dbms_lock.sleep, so whichever call is second finds
+	// the Stmt in use
+	_, err = stmt.Exe()
+	errs := []error{err, <-done}
+	var concurrent, succeeded int
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else if strings.Contains(err.Error(), "Concurrent use of Stmt") {
+			concurrent++
+		} else {
+			t.Skipf("SKIP dbms_lock.sleep: %v", err)
+		}
+	}
+	if concurrent != 1 || succeeded != 1 {
+		t.Fatalf("expected one call to succeed and one concurrent use error, actual(%v)", errs)
+	}
+}
+
+func TestStmt_Qry_prefetchDisabled(t *testing.T) {
+	name := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create or replace type %v_t as table of number", name))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop type %v_t", name))
+	_, err = testSes.PrepAndExe(fmt.Sprintf(`create or replace package %v as
+	produced number := 0;
+	function rows_of(n number) return %v_t pipelined;
+	function produced_count return number;
+end;`, name, name))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop package %v", name))
+	_, err = testSes.PrepAndExe(fmt.Sprintf(`create or replace package body %v as
+	function rows_of(n number) return %v_t pipelined is
+	begin
+		for i in 1 .. n loop
+			produced := produced + 1;
+			pipe row (i);
+		end loop;
+		return;
+	end;
+	function produced_count return number is
+	begin
+		return produced;
+	end;
+end;`, name, name))
+	testErr(err, t)
+
+	ses, err := testSrv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	stmt, err := ses.Prep(fmt.Sprintf("select column_value from table(%v.rows_of(1000))", name), ora.I64)
+	testErr(err, t)
+	defer stmt.Close()
+	stmt.Cfg().IsPrefetchDisabled = true
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("expected a row: %v", rset.Err)
+	}
+
+	// the pipelined function hasn't produced the rows which weren't fetched
+	count, err := ses.Prep(fmt.Sprintf("select %v.produced_count from dual", name), ora.I64)
+	testErr(err, t)
+	defer count.Close()
+	countRset, err := count.Qry()
+	testErr(err, t)
+	if !countRset.Next() {
+		t.Fatalf("expected a row: %v", countRset.Err)
+	}
+	if produced := countRset.Row[0].(int64); produced >= 1000 {
+		t.Fatalf("rows produced after the first fetch: expected fewer than 1000, actual %v", produced)
+	}
+	n := 1
+	for rset.Next() {
+		n++
+	}
+	testErr(rset.Err, t)
+	if n != 1000 {
+		t.Fatalf("rows fetched: expected(1000), actual(%v)", n)
+	}
+}
+
+func TestStmt_Qry_prefetchRowCount(t *testing.T) {
+	ses, err := testSrv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	roundTrips := func() int64 {
+		rset, err := ses.PrepAndQry("select m.value from v$mystat m join v$statname n on n.statistic# = m.statistic# where n.name = 'SQL*Net roundtrips to/from client'", ora.I64)
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatalf("expected a row: %v", rset.Err)
+		}
+		value := rset.Row[0].(int64)
+		for rset.Next() {
+		}
+		return value
+	}
+	// fetch 1000 rows, returning the number of round trips taken
+	fetch := func(prefetchRowCount uint32) int64 {
+		stmt, err := ses.Prep("select level from dual connect by level <= 1000", ora.I64)
+		testErr(err, t)
+		defer stmt.Close()
+		testErr(stmt.Cfg().SetPrefetchRowCount(prefetchRowCount), t)
+		before := roundTrips()
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		n := 0
+		for rset.Next() {
+			n++
+		}
+		testErr(rset.Err, t)
+		if n != 1000 {
+			t.Fatalf("rows fetched: expected(1000), actual(%v)", n)
+		}
+		// exclude the round trip of the statistic query itself
+		return roundTrips() - before - 1
+	}
+	one, many := fetch(1), fetch(500)
+	t.Logf("round trips fetching 1000 rows: prefetch 1 (%v), prefetch 500 (%v)", one, many)
+	if many >= one || many > 5 {
+		t.Fatalf("expected fewer round trips with a larger prefetch: prefetch 1 (%v), prefetch 500 (%v)", one, many)
+	}
+}
+
+func TestStmt_Qry_named(t *testing.T) {
+	qry := `select n from (
+	select 1 n, 1 a, 2 b from dual union all
+	select 2, 2, 1 from dual union all
+	select 3, 3, 3 from dual)
+	where a = :x or b = :x order by n`
+	// the placeholder appears twice but is bound once by name
+	rset, err := testSes.PrepAndQry(qry, ora.Named{Name: "x", Value: int64(2)})
+	testErr(err, t)
+	var values []int64
+	for rset.Next() {
+		values = append(values, rset.Row[0].(int64))
+	}
+	testErr(rset.Err, t)
+	if len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Fatalf("expected([1 2]), actual(%v)", values)
+	}
+
+	// named parameters in any order, with or without a colon
+	var sum int64
+	stmt, err := testSes.Prep("begin :sum := :a + 10 * :b; end;")
+	defer stmt.Close()
+	testErr(err, t)
+	_, err = stmt.Exe(ora.Named{Name: ":b", Value: int64(2)}, ora.Named{Name: "sum", Value: &sum}, ora.Named{Name: "a", Value: int64(1)})
+	testErr(err, t)
+	if sum != 21 {
+		t.Fatalf("expected(21), actual(%v)", sum)
+	}
+
+	if _, err = stmt.Exe(ora.Named{Name: "a", Value: int64(1)}, int64(2), &sum); err == nil {
+		t.Fatalf("expected an error mixing named and positional parameters")
+	}
+}
+
+func TestStmt_Qry_describeReused(t *testing.T) {
+	// the select-list type depends on the parameter type
+	stmt, err := testSes.Prep("select :1 from dual")
+	defer stmt.Close()
+	testErr(err, t)
+	for _, param := range []interface{}{int64(7), int64(8), "seven", "eight!", int64(9)} {
+		rset, err := stmt.Qry(param)
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatalf("%v: expected a row, actual(%v)", param, rset.Err)
+		}
+		if rset.Row[0] != param {
+			t.Fatalf("expected(%#v), actual(%#v)", param, rset.Row[0])
+		}
+	}
+}
+
+func TestStmt_Qry_describeAfterDDL(t *testing.T) {
+	name := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number)", name))
+	testErr(err, t)
+	defer testSes.PrepAndExe(fmt.Sprintf("drop table %v", name))
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (1)", name))
+	testErr(err, t)
+	stmt, err := testSes.Prep(fmt.Sprintf("select * from %v", name))
+	defer stmt.Close()
+	testErr(err, t)
+	columnCount := func() int {
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatalf("expected a row, actual(%v)", rset.Err)
+		}
+		return len(rset.Row)
+	}
+	if n := columnCount(); n != 1 {
+		t.Fatalf("expected(1), actual(%v)", n)
+	}
+	// the kept describe is discarded once the select-list changes
+	_, err = testSes.PrepAndExe(fmt.Sprintf("alter table %v add (c2 varchar2(10))", name))
+	testErr(err, t)
+	if n := columnCount(); n != 2 {
+		t.Fatalf("expected(2), actual(%v)", n)
+	}
+
+	// and once a column's type changes with the column count unchanged
+	_, err = testSes.PrepAndExe(fmt.Sprintf("drop table %v", name))
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number(10,2), c2 varchar2(10))", name))
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (1.25, 'x')", name))
+	testErr(err, t)
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("expected a row, actual(%v)", rset.Err)
+	}
+	if rset.Row[0] != 1.25 {
+		t.Fatalf("expected(1.25), actual(%#v)", rset.Row[0])
+	}
+}
+
+func BenchmarkStmt_Qry_describe(b *testing.B) {
+	qry := `select 1 c1, 2 c2, 'a' c3, 'b' c4, sysdate c5, systimestamp c6,
+	3.5 c7, 'c' c8, 4 c9, 'd' c10, 5 c11, 'e' c12 from dual`
+	query := func(b *testing.B, stmt *ora.Stmt) {
+		rset, err := stmt.Qry()
+		if err != nil {
+			b.Fatal(err)
+		}
+		for rset.Next() {
+		}
+		if rset.Err != nil {
+			b.Fatal(rset.Err)
+		}
+	}
+	// count the describes with the Rset.describe log
+	cfg := ora.Cfg()
+	logger, isLoggingDescribe := cfg.Log.Logger, cfg.Log.Rset.Describe
+	defer func() { cfg.Log.Logger, cfg.Log.Rset.Describe = logger, isLoggingDescribe }()
+	capture := &captureLogger{}
+	cfg.Log.Logger, cfg.Log.Rset.Describe = capture, true
+	reportDescribes := func(b *testing.B) {
+		capture.mu.Lock()
+		defer capture.mu.Unlock()
+		var describes int
+		for _, message := range capture.messages {
+			if strings.Contains(message, "Rset.describe") {
+				describes++
+			}
+		}
+		capture.messages = capture.messages[:0]
+		b.ReportMetric(float64(describes)/float64(b.N), "describes/op")
+	}
+	// a new Stmt describes its columns on its first Qry
+	b.Run("new", func(b *testing.B) {
+		defer reportDescribes(b)
+		for n := 0; n < b.N; n++ {
+			stmt, err := testSes.Prep(qry)
+			if err != nil {
+				b.Fatal(err)
+			}
+			query(b, stmt)
+			stmt.Close()
+		}
+	})
+	// a reused Stmt skips the describe after its first Qry
+	b.Run("reused", func(b *testing.B) {
+		defer reportDescribes(b)
+		stmt, err := testSes.Prep(qry)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer stmt.Close()
+		for n := 0; n < b.N; n++ {
+			query(b, stmt)
+		}
+	})
+}
+
+func TestStmt_CallTimeout(t *testing.T) {
+	if major, _, _, _, _ := ora.ClientVersion(); major < 18 {
+		t.Skipf("SKIP call timeout: client version %v is before 18c", major)
+	}
+	ses, err := testSrv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	version, err := ses.ServerVersion()
+	testErr(err, t)
+	if version.Major < 18 {
+		t.Skipf("SKIP call timeout: server version %v is before 18c", version)
+	}
+
+	stmt, err := ses.Prep("BEGIN DBMS_SESSION.SLEEP(5); END;")
+	testErr(err, t)
+	defer stmt.Close()
+	stmt.Cfg().CallTimeout = 500 * time.Millisecond
+	start := time.Now()
+	_, err = stmt.Exe()
+	elapsed := time.Since(start)
+	oe, ok := err.(*ora.Error)
+	if !ok || !oe.Timeout() {
+		t.Fatalf("expected a call timeout error, actual(%v)", err)
+	}
+	if elapsed > 4*time.Second {
+		t.Fatalf("expected the call to end near its timeout, actual(%v)", elapsed)
+	}
+
+	// the interrupted call was reset, so the session is usable here, without
+	// the timeout of the former statement
+	stmt, err = ses.Prep("BEGIN DBMS_SESSION.SLEEP(1); END;")
+	testErr(err, t)
+	defer stmt.Close()
+	_, err = stmt.Exe()
+	testErr(err, t)
+}
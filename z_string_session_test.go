@@ -5,7 +5,11 @@
 package ora_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+
+	"gopkg.in/rana/ora.v2"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -737,3 +741,39 @@ func TestWorkload_nclobNull_session(t *testing.T) {
 func TestBindDefine_nclobNull_nil_session(t *testing.T) {
 	testBindDefine(nil, nclobNull, t, nil)
 }
+
+func TestDefine_long_pieces_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 long)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	expected := strings.Repeat("0123456789abcdef", 100<<10/16+7) // over 100KB
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (1, :1)", tableName), expected)
+	testErr(err, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1, c2) values (2, null)", tableName))
+	testErr(err, t)
+
+	// pieces smaller than the value are joined
+	stmt, err := testSes.Prep(fmt.Sprintf("select c2 from %v order by c1", tableName), ora.OraS)
+	testErr(err, t)
+	defer stmt.Close()
+	testErr(stmt.Cfg().SetLongBufferSize(4096), t)
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("no row returned: %v", rset.Err)
+	}
+	if actual := rset.Row[0].(ora.String); actual.IsNull || actual.Value != expected {
+		t.Fatalf("expected a LONG of %v bytes, actual %v bytes (isNull %v)", len(expected), len(actual.Value), actual.IsNull)
+	}
+	if !rset.Next() {
+		t.Fatalf("no second row returned: %v", rset.Err)
+	}
+	if actual := rset.Row[0].(ora.String); !actual.IsNull {
+		t.Fatalf("expected a NULL LONG, actual %v bytes", len(actual.Value))
+	}
+	for rset.Next() {
+	}
+	testErr(rset.Err, t)
+}
@@ -5,7 +5,11 @@
 package ora_test
 
 import (
+	"fmt"
 	"testing"
+	"time"
+
+	"gopkg.in/rana/ora.v2"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -271,3 +275,173 @@ func TestWorkload_timestampLtzP9Null_session(t *testing.T) {
 func TestBindDefine_timestampLtzP9Null_nil_session(t *testing.T) {
 	testBindDefine(nil, timestampLtzP9Null, t, nil)
 }
+
+func TestBindDefine_time_timestampTzP9_zoneOffset_session(t *testing.T) {
+	tableName, err := createTable(1, timestampTzP9, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	offsets := []int{5*3600 + 30*60, -(30 * 60), -(9*3600 + 30*60)}
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	for _, offset := range offsets {
+		_, err = stmt.Exe(time.Date(2016, 2, 3, 4, 5, 6, 7, time.FixedZone("", offset)))
+		testErr(err, t)
+	}
+
+	selectStmt, err := testSes.Prep(fmt.Sprintf("select c1 from %v where to_char(c1, 'TZH:TZM') = :1", tableName))
+	defer selectStmt.Close()
+	testErr(err, t)
+	for _, offset := range offsets {
+		expected := time.Date(2016, 2, 3, 4, 5, 6, 7, time.FixedZone("", offset))
+		rset, err := selectStmt.Qry(expected.Format("-07:00"))
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatalf("expected a row stored with offset %v", expected.Format("-07:00"))
+		}
+		actual := rset.Row[0].(time.Time)
+		if _, actualOffset := actual.Zone(); actualOffset != offset {
+			t.Fatalf("zone offset: expected(%v), actual(%v)", offset, actualOffset)
+		}
+		if !actual.Equal(expected) || actual.Hour() != expected.Hour() {
+			t.Fatalf("expected(%v), actual(%v)", expected, actual)
+		}
+	}
+}
+
+func TestBindDefine_time_timestampLtzP9_sesTimeZone_session(t *testing.T) {
+	sesCfg := *testSesCfg
+	sesCfg.TimeZone = "+05:30"
+	ses, err := testSrv.OpenSes(&sesCfg)
+	defer ses.Close()
+	testErr(err, t)
+	tableName, err := createTable(1, timestampLtzP9, ses)
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+
+	// a machine local zone differing from the session zone
+	expected := time.Date(2016, 2, 3, 4, 5, 6, 7, time.FixedZone("", -7*3600))
+	_, err = ses.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (:c1)", tableName), expected)
+	testErr(err, t)
+
+	rset, err := ses.PrepAndQry(fmt.Sprintf("select c1, to_char(c1, 'HH24:MI') from %v", tableName))
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal("expected a row")
+	}
+	actual := rset.Row[0].(time.Time)
+	if !actual.Equal(expected) {
+		t.Fatalf("expected(%v), actual(%v)", expected, actual)
+	}
+	if _, offset := actual.Zone(); offset != 5*3600+30*60 {
+		t.Fatalf("zone offset: expected(%v), actual(%v)", 5*3600+30*60, offset)
+	}
+	// 04:05 at -07:00 is 16:35 at +05:30
+	if rset.Row[1] != "16:35" {
+		t.Fatalf("session time: expected(16:35), actual(%v)", rset.Row[1])
+	}
+}
+
+func TestBindDefine_time_date_fraction_session(t *testing.T) {
+	tableName := tableName()
+	_, err := testSes.PrepAndExe(fmt.Sprintf("create table %v (c1 number, c2 date)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	value := time.Date(2016, 2, 29, 23, 59, 59, 600000000, time.Local)
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1, c2) values (:1, :2)", tableName))
+	defer stmt.Close()
+	testErr(err, t)
+	stmt.Cfg().DateFraction = ora.ErrorFraction
+	if _, err = stmt.Exe(int64(0), value); err == nil {
+		t.Fatal("expected an error for a time with fractional seconds")
+	}
+	_, err = stmt.Exe(int64(0), value.Truncate(time.Second))
+	testErr(err, t)
+	stmt.Cfg().DateFraction = ora.TruncateFraction
+	_, err = stmt.Exe(int64(1), value)
+	testErr(err, t)
+	stmt.Cfg().DateFraction = ora.RoundFraction
+	_, err = stmt.Exe(int64(2), ora.Time{Value: value})
+	testErr(err, t)
+
+	expected := []time.Time{
+		time.Date(2016, 2, 29, 23, 59, 59, 0, time.Local),
+		time.Date(2016, 2, 29, 23, 59, 59, 0, time.Local),
+		time.Date(2016, 3, 1, 0, 0, 0, 0, time.Local),
+	}
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select c2 from %v order by c1", tableName))
+	testErr(err, t)
+	for rset.Next() {
+		if actual := rset.Row[0].(time.Time); !actual.Equal(expected[rset.Index]) {
+			t.Fatalf("row %v: expected(%v), actual(%v)", rset.Index, expected[rset.Index], actual)
+		}
+	}
+	testErr(rset.Err, t)
+}
+
+func TestDefine_time_asString_session(t *testing.T) {
+	ses, err := testSrv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+	_, err = ses.PrepAndExe("ALTER SESSION SET NLS_DATE_FORMAT = 'YYYY/MM/DD HH24.MI.SS'")
+	testErr(err, t)
+	_, err = ses.PrepAndExe("ALTER SESSION SET NLS_TIMESTAMP_FORMAT = 'DD.MM.YYYY HH24:MI:SS.FF3'")
+	testErr(err, t)
+	tableName := tableName()
+	_, err = ses.PrepAndExe(fmt.Sprintf("create table %v (c0 number, c1 date, c2 timestamp)", tableName))
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+	value := time.Date(2016, 2, 3, 4, 5, 6, 789000000, time.Local)
+	_, err = ses.PrepAndExe(fmt.Sprintf("insert into %v (c0, c1, c2) values (1, :1, :2)", tableName), value.Truncate(time.Second), value)
+	testErr(err, t)
+	_, err = ses.PrepAndExe(fmt.Sprintf("insert into %v (c0) values (2)", tableName))
+	testErr(err, t)
+	query := fmt.Sprintf("select c1, c2 from %v order by c0", tableName)
+
+	// the default remains time.Time
+	rset, err := ses.PrepAndQry(query)
+	testErr(err, t)
+	row := rset.NextRow()
+	testErr(rset.Err, t)
+	if actual, ok := row[0].(time.Time); !ok || !actual.Equal(value.Truncate(time.Second)) {
+		t.Fatalf("date: expected(%v), actual(%v)", value.Truncate(time.Second), row[0])
+	}
+	for rset.Next() {
+	}
+
+	// text formatted by the session's NLS formats
+	stmt, err := ses.Prep(query)
+	testErr(err, t)
+	defer stmt.Close()
+	testErr(stmt.Cfg().Rset.SetDate(ora.S), t)
+	testErr(stmt.Cfg().Rset.SetTimestamp(ora.OraS), t)
+	rset, err = stmt.Qry()
+	testErr(err, t)
+	row = rset.NextRow()
+	testErr(rset.Err, t)
+	if row[0] != "2016/02/03 04.05.06" {
+		t.Fatalf("date: expected(2016/02/03 04.05.06), actual(%v)", row[0])
+	}
+	if row[1] != (ora.String{Value: "03.02.2016 04:05:06.789"}) {
+		t.Fatalf("timestamp: expected(03.02.2016 04:05:06.789), actual(%v)", row[1])
+	}
+	row = rset.NextRow()
+	testErr(rset.Err, t)
+	if row[0] != nil || row[1] != (ora.String{IsNull: true}) {
+		t.Fatalf("nulls: expected(<nil>, null String), actual(%v, %v)", row[0], row[1])
+	}
+
+	// a GoColumnType of the statement
+	stmt, err = ses.Prep(fmt.Sprintf("select c1 from %v where c0 = 1", tableName), ora.S)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err = stmt.Qry()
+	testErr(err, t)
+	row = rset.NextRow()
+	testErr(rset.Err, t)
+	if row[0] != "2016/02/03 04.05.06" {
+		t.Fatalf("date with S: expected(2016/02/03 04.05.06), actual(%v)", row[0])
+	}
+}